@@ -0,0 +1,58 @@
+// Команда reconcile - сверка баллов без HTTP: журнал баллов против выполненных событий
+// и суммы в user_points против журнала.
+//
+//	go run ./cmd/reconcile           # пробный запуск, только отчет
+//	go run ./cmd/reconcile -apply    # исправить расхождения
+//
+// Отчет совпадает с ответом POST /admin/points/reconcile.
+// Подключение к БД берется из тех же переменных окружения, что и у сервера (DB_HOST, DB_PORT, ...).
+// Записи журнала и запись в журнале действий создаются с actor_id = 0.
+package main
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/api/services"
+	"bobri/internal/config"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	apply := flag.Bool("apply", false, "исправить расхождения (без флага - только отчет)")
+	flag.Parse()
+
+	db, err := config.ConnectDB()
+	if err != nil {
+		log.Fatalf("Error while connecting to db:%v", err)
+	}
+	defer db.Close()
+
+	service := services.NewPointsService(
+		repositories.NewPointsRepository(db),
+		repositories.NewUserRepository(db),
+		services.NewAuditService(repositories.NewAuditRepository(db)),
+		repositories.NewUoW(db),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	report, err := service.Reconcile(ctx, 0, !*apply)
+	if err != nil {
+		log.Fatalf("Reconcile failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		log.Fatalf("Error while writing report: %v", err)
+	}
+
+	if !*apply && (len(report.EventDrift) > 0 || len(report.TotalDrift) > 0) {
+		log.Print("Dry run: nothing changed, run with -apply to fix drift")
+	}
+}
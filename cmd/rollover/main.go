@@ -0,0 +1,78 @@
+// Команда rollover - переход на новый учебный год без HTTP: перевод групп и выпуск студентов.
+//
+//	go run ./cmd/rollover -file mapping.json          # пробный запуск, только отчет
+//	go run ./cmd/rollover -file mapping.json -apply   # применить
+//
+// Формат файла совпадает с телом POST /admin/rollover:
+//
+//	{"graduating_groups": ["ИВТ-41"], "mappings": [{"from": "ИВТ-31", "to": "ИВТ-41"}]}
+//
+// Подключение к БД берется из тех же переменных окружения, что и у сервера (DB_HOST, DB_PORT, ...).
+// В журнале действий запуск записывается с actor_id = 0.
+package main
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/api/services"
+	"bobri/internal/config"
+	"bobri/internal/models"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"time"
+)
+
+func main() {
+	file := flag.String("file", "", "JSON с таблицей перевода групп и выпускающимися группами")
+	apply := flag.Bool("apply", false, "применить изменения (без флага - только отчет)")
+	flag.Parse()
+
+	if *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	data, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("Error while reading %s: %v", *file, err)
+	}
+
+	var req models.RolloverRequest
+	if err = json.Unmarshal(data, &req); err != nil {
+		log.Fatalf("Error while parsing %s: %v", *file, err)
+	}
+
+	db, err := config.ConnectDB()
+	if err != nil {
+		log.Fatalf("Error while connecting to db:%v", err)
+	}
+	defer db.Close()
+
+	service := services.NewRolloverService(
+		repositories.NewInstitutesRepository(db),
+		repositories.NewStudentsRepository(db),
+		repositories.NewUserRepository(db),
+		services.NewAuditService(repositories.NewAuditRepository(db)),
+		repositories.NewUoW(db),
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	report, err := service.Rollover(ctx, 0, req, !*apply)
+	if err != nil {
+		log.Fatalf("Rollover failed: %v", err)
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err = enc.Encode(report); err != nil {
+		log.Fatalf("Error while writing report: %v", err)
+	}
+
+	if !*apply {
+		log.Print("Dry run: nothing changed, run with -apply to apply")
+	}
+}
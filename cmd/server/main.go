@@ -2,12 +2,20 @@ package main
 
 import (
 	_ "bobri/docs"
+	"bobri/internal/api/repositories"
 	"bobri/internal/api/routes"
+	"bobri/internal/api/services"
 	"bobri/internal/config"
 	"bobri/internal/models"
 	"bobri/pkg/helpers"
+	"bobri/pkg/storage"
+	"context"
+	"errors"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -22,12 +30,21 @@ func main() {
 	}
 	defer db.Close()
 
-	// Получаем secret из .env и создаем JWTMaker
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Fatal("JWT_SECRET not set")
+	// Загружаем ключи подписи из директории и создаем JWTMaker.
+	// JWT_ACTIVE_KID - имя файла ключа (без .pem), которым подписываются новые токены
+	keysDir := os.Getenv("JWT_KEYS_DIR")
+	activeKid := os.Getenv("JWT_ACTIVE_KID")
+	if keysDir == "" || activeKid == "" {
+		log.Fatal("JWT_KEYS_DIR or JWT_ACTIVE_KID not set")
+	}
+	signingKeys, err := helpers.LoadSigningKeys(keysDir)
+	if err != nil {
+		log.Fatalf("Error while loading JWT keys: %v", err)
+	}
+	AccessJwtMaker, err := helpers.NewJWTMaker(signingKeys, activeKid, 15*time.Minute)
+	if err != nil {
+		log.Fatalf("Error while creating JWT maker: %v", err)
 	}
-	AccessJwtMaker := helpers.NewJWTMaker([]byte(secret), 15*time.Minute)
 
 	fromEmail := os.Getenv("FROM_EMAIL")
 	emailPass := os.Getenv("EMAIL_PASSWORD")
@@ -36,18 +53,77 @@ func main() {
 		log.Fatal("Email credentials are missing in ENV")
 	}
 
+	// Политика для пользователей с неподтвержденной почтой: off, reset (по умолчанию) или strict
+	verificationPolicy, err := services.ParseEmailVerificationPolicy(os.Getenv("EMAIL_VERIFICATION_POLICY"))
+	if err != nil {
+		log.Fatalf("Error while parsing email verification policy: %v", err)
+	}
+	emailAuth := models.EmailAuth{
+		EmailFrom: fromEmail,
+		EmailPass: emailPass,
+	}
+
+	// Хранилище загруженных файлов: local (по умолчанию, ./uploads) или s3 (STORAGE_BACKEND=s3 и S3_*)
+	fileStorage, err := storage.New(storage.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Error while creating file storage: %v", err)
+	}
+
 	// Создаем движок gin для работы с HTTP и регистрируем роутеры
 	engine := gin.Default()
 
-	routes.AuthRoutes(engine, db, AccessJwtMaker, models.EmailAuth{
-		EmailFrom: fromEmail,
-		EmailPass: emailPass})
-	routes.AdminRoutes(engine, db, AccessJwtMaker)
-	routes.UserRoutes(engine, db, AccessJwtMaker)
+	// Доверяем X-Forwarded-For только от прокси во внутренней сети (Caddy),
+	// иначе ограничение попыток по IP можно обойти подменой заголовка
+	if err := engine.SetTrustedProxies([]string{"127.0.0.1", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"}); err != nil {
+		log.Fatalf("Error while setting trusted proxies: %v", err)
+	}
 
-	// Запускаем движок
-	if err := engine.Run(":8080"); err != nil {
-		log.Fatalf("Error while starting server: %v", err)
+	// Файлы локального хранилища раздает сам сервер
+	if local, ok := fileStorage.(*storage.LocalStorage); ok {
+		engine.Static(storage.LocalRoute, local.Dir())
 	}
 
+	routes.AuthRoutes(engine, db, AccessJwtMaker, emailAuth, verificationPolicy)
+	routes.AdminRoutes(engine, db, AccessJwtMaker, emailAuth, verificationPolicy, fileStorage)
+	routes.UserRoutes(engine, db, AccessJwtMaker, emailAuth, verificationPolicy, fileStorage)
+
+	// Контекст отменяется по SIGINT/SIGTERM: сервер и фоновые задачи завершаются вместе
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Анонимизация аккаунтов, у которых истек срок на отмену удаления
+	userRepo := repositories.NewUserRepository(db)
+	uow := repositories.NewUoW(db)
+	throttleService := services.NewAuthThrottleService(repositories.NewAuthThrottleRepository(db), services.NewAuditService(repositories.NewAuditRepository(db)), uow)
+	accountService := services.NewAccountService(
+		userRepo,
+		repositories.NewRefreshTokensRepository(db),
+		repositories.NewCompletedEventsRepository(db),
+		repositories.NewPointsRepository(db),
+		services.NewEmailVerificationService(repositories.NewEmailVerificationRepository(db), userRepo, services.NewEmailProvider(emailAuth), throttleService, verificationPolicy, uow),
+		uow,
+	)
+	workerDone := make(chan struct{})
+	go func() {
+		defer close(workerDone)
+		accountService.RunDeletionWorker(ctx, time.Hour)
+	}()
+
+	// Запускаем сервер
+	server := &http.Server{Addr: ":8080", Handler: engine}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Error while starting server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Print("Shutting down")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Error while shutting down server: %v", err)
+	}
+	<-workerDone
 }
@@ -15,9 +15,29 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/.well-known/jwks.json": {
+            "get": {
+                "description": "Возвращает публичные ключи, которыми можно проверить подпись access токенов.\nКлюч выбирается по заголовку kid токена. Во время ротации в наборе присутствуют и старые, и новые ключи.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Набор публичных ключей (JWKS)",
+                "responses": {
+                    "200": {
+                        "description": "Набор публичных ключей",
+                        "schema": {
+                            "$ref": "#/definitions/helpers.JWKSet"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/add_completed_event": {
             "post": {
-                "description": "Добавляет запись о выполнении события конкретным пользователем. Требует прав администратора.",
+                "description": "Добавляет запись о выполнении события конкретным пользователем. Требует прав администратора.\nУдаленные события и черновики засчитать нельзя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -66,6 +86,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Событие не найдено, удалено или еще не опубликовано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "409": {
                         "description": "Событие уже было отмечено ранее",
                         "schema": {
@@ -81,54 +107,105 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/completed_events": {
+        "/admin/audit": {
             "get": {
-                "description": "Возвращает полный список выполненных событий всех пользователей системы.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Возвращает записи журнала: кто, когда и что изменил, с состоянием объекта до и после изменения.\nНапример, начисления баллов студенту: action=completed_event.add, target_type=user, target_id={user_id}.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Получить все выполненные события",
+                "summary": "Журнал действий администраторов",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Кто выполнил действие",
+                        "name": "actor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Действие (event.update, completed_event.add, user.delete, ...)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Тип объекта (event, user, role, auth_lock)",
+                        "name": "target_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Идентификатор объекта",
+                        "name": "target_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Начало периода (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Конец периода, не включая (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "default": 50,
-                        "description": "Максимальное количество мероприятий в выдаче",
+                        "description": "Максимальное количество записей",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Сколько записей пропустить",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список выполненных событий всех пользователей",
+                        "description": "Записи журнала, новые первыми",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.CompletedEvent"
+                                "$ref": "#/definitions/models.AuditEntry"
                             }
                         }
                     },
-                    "401": {
-                        "description": "Нет прав доступа",
+                    "400": {
+                        "description": "Некорректные параметры фильтра",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при получении выполненных событий",
+                        "description": "Ошибка при получении журнала",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -136,59 +213,50 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/create_event": {
-            "post": {
-                "description": "Создаёт новое событие в системе и возвращает полную информацию о созданной записи. Требует авторизации администратора. Поля помимо title могут не указываться, тогда они будут заменены на стандартные значения",
-                "consumes": [
-                    "application/json"
+        "/admin/auth_locks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Возвращает IP адреса и аккаунты, заблокированные из-за большого числа попыток входа,\nпроверки студенческого или сброса пароля.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Создать событие",
+                "summary": "Список блокировок",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Данные нового события",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateEventRequest"
-                        }
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество блокировок в выдаче",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Событие успешно создано",
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateEventResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Некорректный JSON или ошибка валидации",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Нет доступа — невалидный или отсутствующий токен",
+                        "description": "Список блокировок",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.AuthLock"
+                            }
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при создании события",
+                        "description": "Ошибка при получении блокировок",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -196,53 +264,88 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/create_suggest": {
-            "post": {
-                "description": "Создаёт рекомендацию для события с указанием времени истечения в часах с момента создания. Возвращает ID события. Требует авторизации пользователя.",
-                "consumes": [
-                    "application/json"
+        "/admin/claims": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Возвращает заявки студентов с подтверждениями. По умолчанию - ждущие рассмотрения, старые первыми.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Создать рекомендацию для события",
+                "summary": "Очередь заявок",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Данные для создания рекомендации",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateSuggestRequest"
-                        }
+                        "enum": [
+                            "pending",
+                            "approved",
+                            "rejected",
+                            "withdrawn"
+                        ],
+                        "type": "string",
+                        "default": "pending",
+                        "description": "Статус заявки",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Заявки одного студента",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Сколько записей пропустить",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "ID события, для которого была создана рекомендация",
+                        "description": "Заявки",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Claim"
+                            }
                         }
                     },
                     "400": {
-                        "description": "Некорректный JSON",
+                        "description": "Некорректные параметры фильтра",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при создании рекомендации",
+                        "description": "Ошибка при получении заявок",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -250,70 +353,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/delete_completed_event/{user_id}/{event_id}": {
-            "delete": {
-                "description": "Удаляет отметку о выполнении события конкретным пользователем. Требует прав администратора.",
-                "consumes": [
-                    "application/json"
+        "/admin/claims/{id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Одобряет заявку: событие отмечается выполненным у студента и начисляются баллы.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Удаление выполнения события",
+                "summary": "Одобрить заявку",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "ID пользователя",
-                        "name": "user_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "ID события",
-                        "name": "event_id",
+                        "description": "ID заявки",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Отметка о выполнении успешно удалена",
+                        "description": "Одобренная заявка",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteCompletedEventResponse"
+                            "$ref": "#/definitions/models.Claim"
                         }
                     },
                     "400": {
-                        "description": "Некорректный user_id или event_id",
+                        "description": "Некорректный ID заявки",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Нет прав доступа",
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Отметка о выполнении не найдена",
+                        "description": "Заявка не найдена или событие удалено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка уже рассмотрена или событие уже выполнено",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при удалении",
+                        "description": "Ошибка при одобрении заявки",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -321,9 +425,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/delete_event/{id}": {
-            "delete": {
-                "description": "Удаляет событие по его идентификатору. Требует прав администратора.",
+        "/admin/claims/{id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отклоняет заявку с указанием причины, которую студент увидит в /me/claims.",
                 "consumes": [
                     "application/json"
                 ],
@@ -333,51 +442,66 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Удалить событие",
+                "summary": "Отклонить заявку",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "ID события для удаления",
+                        "description": "ID заявки",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Причина отклонения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RejectClaimRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Событие успешно удалено",
+                        "description": "Отклоненная заявка",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteEventResponse"
+                            "$ref": "#/definitions/models.Claim"
                         }
                     },
                     "400": {
-                        "description": "Некорректный ID события",
+                        "description": "Некорректный запрос",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Нет прав доступа",
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Событие с указанным ID не найдено",
+                        "description": "Заявка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка уже рассмотрена",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при удалении",
+                        "description": "Ошибка при отклонении заявки",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -385,9 +509,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/delete_suggestion/{id}": {
-            "delete": {
-                "description": "Удаляет рекомендацию по заданному ID. Если рекомендация не найдена, возвращает ошибку 404. В случае других ошибок возвращается ошибка 500.",
+        "/admin/completed_events": {
+            "get": {
+                "description": "Возвращает полный список выполненных событий всех пользователей системы.",
                 "consumes": [
                     "application/json"
                 ],
@@ -397,7 +521,7 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Удалить рекомендацию по ID",
+                "summary": "Получить все выполненные события",
                 "parameters": [
                     {
                         "type": "string",
@@ -409,34 +533,30 @@ const docTemplate = `{
                     },
                     {
                         "type": "integer",
-                        "format": "int64",
-                        "description": "ID рекомендации для удаления",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "default": 50,
+                        "description": "Максимальное количество мероприятий в выдаче",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Рекомендация успешно удалена",
+                        "description": "Список выполненных событий всех пользователей",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteEventResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Некорректный формат ID рекомендации",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.CompletedEvent"
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Рекомендация с таким ID не найдено",
+                    "401": {
+                        "description": "Нет прав доступа",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при удалении рекомендации",
+                        "description": "Ошибка сервера при получении выполненных событий",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -444,9 +564,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/delete_user/{user_id}": {
-            "delete": {
-                "description": "Удаляет пользователя по его user_id. Требует прав администратора.",
+        "/admin/create_event": {
+            "post": {
+                "description": "Создаёт новое событие в системе и возвращает полную информацию о созданной записи. Требует авторизации администратора. Поля помимо title могут не указываться, тогда они будут заменены на стандартные значения",
                 "consumes": [
                     "application/json"
                 ],
@@ -456,7 +576,7 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Удалить пользователя",
+                "summary": "Создать событие",
                 "parameters": [
                     {
                         "type": "string",
@@ -467,40 +587,36 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "UserId удаляемого пользователя",
-                        "name": "email",
-                        "in": "path",
-                        "required": true
+                        "description": "Данные нового события",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Пользователь успешно удалён",
+                        "description": "Событие успешно создано",
                         "schema": {
-                            "$ref": "#/definitions/models.DeleteUserResponse"
+                            "$ref": "#/definitions/models.CreateEventResponse"
                         }
                     },
                     "400": {
-                        "description": "Передан пустой id пользователя",
+                        "description": "Некорректный JSON или ошибка валидации",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Нет прав доступа к операции",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Пользователь с таким user_id не найден",
+                        "description": "Нет доступа — невалидный или отсутствующий токен",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при удалении",
+                        "description": "Ошибка сервера при создании события",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -508,9 +624,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/events": {
-            "get": {
-                "description": "Возвращает полный список событий из базы данных.",
+        "/admin/create_suggest": {
+            "post": {
+                "description": "Создаёт рекомендацию для события с указанием времени истечения в часах с момента создания. Возвращает ID события. Требует авторизации пользователя.",
                 "consumes": [
                     "application/json"
                 ],
@@ -520,7 +636,7 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Получить все события",
+                "summary": "Создать рекомендацию для события",
                 "parameters": [
                     {
                         "type": "string",
@@ -531,25 +647,30 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Максимальное количество мероприятий в выдаче",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "Данные для создания рекомендации",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSuggestRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список событий",
+                        "description": "ID события, для которого была создана рекомендация",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Event"
-                            }
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при получении списка событий",
+                        "description": "Ошибка сервера при создании рекомендации",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -557,50 +678,70 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/students": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/admin/delete_completed_event/{user_id}/{event_id}": {
+            "delete": {
+                "description": "Удаляет отметку о выполнении события конкретным пользователем. Требует прав администратора.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Возвращает всех студентов из таблицы students.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Получение списка студентов",
+                "summary": "Удаление выполнения события",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
                         "type": "integer",
-                        "default": 50,
-                        "description": "Максимальное количество студентов в выдаче",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "event_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список студентов",
+                        "description": "Отметка о выполнении успешно удалена",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Student"
-                            }
+                            "$ref": "#/definitions/models.DeleteCompletedEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный user_id или event_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет прав доступа",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Отметка о выполнении не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при запросе или чтении данных",
+                        "description": "Ошибка сервера при удалении",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -608,9 +749,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/update_event": {
-            "patch": {
-                "description": "Производит частичное обновление данных события по его ID.\nОбновляются только те поля, которые переданы в теле запроса.",
+        "/admin/delete_event/{id}": {
+            "delete": {
+                "description": "Удаляет событие по его идентификатору. Требует прав администратора.\nУдаление мягкое: выполненные события и баллы студентов сохраняются, событие можно восстановить.",
                 "consumes": [
                     "application/json"
                 ],
@@ -620,7 +761,7 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Обновить выбранные поля события",
+                "summary": "Удалить событие",
                 "parameters": [
                     {
                         "type": "string",
@@ -631,36 +772,46 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "ID события и новые значения полей",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateEventRequest"
-                        }
+                        "type": "integer",
+                        "description": "ID события для удаления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Событие успешно обновлено",
+                        "description": "Событие успешно удалено",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.DeleteEventResponse"
                         }
                     },
                     "400": {
-                        "description": "Некорректный JSON или ошибка валидации",
+                        "description": "Некорректный ID события",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "401": {
-                        "description": "Неавторизованный доступ — неверный или отсутствующий токен",
+                        "description": "Нет прав доступа",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие с указанным ID не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие уже удалено",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при попытке обновления записи",
+                        "description": "Ошибка сервера при удалении",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -668,9 +819,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/update_user": {
-            "patch": {
-                "description": "Обновляет данные пользователя с проверкой прав доступа. Только пользователи с более высоким уровнем прав могут изменять данные пользователей с более низким уровнем прав.",
+        "/admin/delete_suggestion/{id}": {
+            "delete": {
+                "description": "Удаляет рекомендацию по заданному ID. Если рекомендация не найдена, возвращает ошибку 404. В случае других ошибок возвращается ошибка 500.",
                 "consumes": [
                     "application/json"
                 ],
@@ -680,47 +831,46 @@ const docTemplate = `{
                 "tags": [
                     "admin"
                 ],
-                "summary": "Обновление данных пользователя",
+                "summary": "Удалить рекомендацию по ID",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен в формате: Bearer {token} default(Bearer )",
+                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "description": "Данные для обновления пользователя",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateUserRequest"
-                        }
+                        "type": "integer",
+                        "format": "int64",
+                        "description": "ID рекомендации для удаления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Успешное обновление данных пользователя",
+                        "description": "Рекомендация успешно удалена",
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateUserResponse"
+                            "$ref": "#/definitions/models.DeleteEventResponse"
                         }
                     },
                     "400": {
-                        "description": "Ошибка в формате JSON",
+                        "description": "Некорректный формат ID рекомендации",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Недостаточно прав",
+                    "404": {
+                        "description": "Рекомендация с таким ID не найдено",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Внутренняя ошибка сервера",
+                        "description": "Ошибка при удалении рекомендации",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -728,118 +878,105 @@ const docTemplate = `{
                 }
             }
         },
-        "/admin/users": {
-            "get": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
+        "/admin/delete_user/{user_id}": {
+            "delete": {
+                "description": "Удаляет пользователя по его user_id. Требует прав администратора.",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Возвращает всех зарегистрированных пользователей из таблицы users.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Получение списка пользователей",
+                "summary": "Удалить пользователя",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен",
+                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 50,
-                        "description": "Максимальное количество пользователей в выдаче",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "UserId удаляемого пользователя",
+                        "name": "email",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список пользователей",
+                        "description": "Пользователь успешно удалён",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/models.DeleteUserResponse"
                         }
                     },
-                    "500": {
-                        "description": "Ошибка при запросе или чтении данных",
+                    "400": {
+                        "description": "Передан пустой id пользователя",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    }
-                }
-            }
-        },
-        "/auth/check": {
-            "post": {
-                "description": "Проверяет наличие студенческого билета в системе.\nЕсли студент не зарегистрирован, генерирует временный токен для регистрации.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "auth"
-                ],
-                "summary": "Проверка студенческого в системе",
-                "parameters": [
-                    {
-                        "description": "Номер студенческого билета",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.AuthBookRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Студенческий найден, выдан регистрационный токен",
-                        "schema": {
-                            "$ref": "#/definitions/models.AuthStatus"
-                        }
                     },
-                    "400": {
-                        "description": "Некорректный запрос — ошибка парсинга JSON",
+                    "401": {
+                        "description": "Нет прав доступа к операции",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "404": {
-                        "description": "Студенческий не найден в базе",
+                        "description": "Пользователь с таким user_id не найден",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Пользователь с таким номером уже зарегистрирован",
+                    "500": {
+                        "description": "Ошибка сервера при удалении",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
+                    }
+                }
+            }
+        },
+        "/admin/event_types": {
+            "get": {
+                "description": "Возвращает все типы мероприятий с иконками и цветами, упорядоченные по коду.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Типы мероприятий",
+                "responses": {
+                    "200": {
+                        "description": "Типы мероприятий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EventType"
+                            }
+                        }
                     },
                     "500": {
-                        "description": "Ошибка при генерации или сохранении токена",
+                        "description": "Ошибка при получении типов",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/login": {
+            },
             "post": {
-                "description": "Проверяет почту и пароль пользователя. При успешной авторизации выдает пару access и refresh токенов.",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавляет тип мероприятия. Новый тип сразу появляется в статистике выполненных мероприятий.",
                 "consumes": [
                     "application/json"
                 ],
@@ -847,47 +984,55 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Авторизация пользователя",
+                "summary": "Добавление типа мероприятия",
                 "parameters": [
                     {
-                        "description": "Данные для входа (почта и пароль)",
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Тип мероприятия",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.LoginRequest"
+                            "$ref": "#/definitions/models.CreateEventTypeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Успешная авторизация — возвращается пользователь и пара токенов",
+                    "201": {
+                        "description": "Добавленный тип",
                         "schema": {
-                            "$ref": "#/definitions/models.LoginResponse"
+                            "$ref": "#/definitions/models.EventType"
                         }
                     },
                     "400": {
-                        "description": "Некорректный запрос — ошибка парсинга JSON",
+                        "description": "Некорректные данные",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Неправильный пароль",
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Пользователь с такой почтой не найден",
+                    "409": {
+                        "description": "Тип с таким кодом уже существует",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера при работе с базой данных или токенами",
+                        "description": "Ошибка при добавлении типа",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -895,61 +1040,75 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/refresh": {
-            "post": {
-                "description": "Принимает refresh токен, проверяет его валидность и возвращает новую пару токенов.",
-                "consumes": [
-                    "application/json"
+        "/admin/event_types/{code}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Удаляет тип мероприятия. События этого типа получают тип 0 (неизвестно), выполненные мероприятия сохраняются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Обновление access и refresh токенов",
+                "summary": "Удаление типа мероприятия",
                 "parameters": [
                     {
-                        "description": "Refresh токен",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.RefreshTokenRequest"
-                        }
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Код типа",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Успешное обновление токенов",
+                    "204": {
+                        "description": "Тип удален"
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
-                            "$ref": "#/definitions/models.RefreshTokenResponse"
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Некорректный JSON",
+                    "404": {
+                        "description": "Тип не найден",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Невалидный или просроченный refresh токен",
+                    "409": {
+                        "description": "Тип 0 нельзя удалить",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при обновлении/генерации токенов",
+                        "description": "Ошибка при удалении типа",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        },
-        "/auth/register": {
-            "post": {
-                "description": "Регистрирует нового пользователя на основе временного токена, выданного после проверки студенческого билета.",
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет название, иконку или цвет типа. Пустые поля не меняются, код не меняется.",
                 "consumes": [
                     "application/json"
                 ],
@@ -957,47 +1116,62 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Регистрация пользователя по токену",
+                "summary": "Изменение типа мероприятия",
                 "parameters": [
                     {
-                        "description": "Почта, пароль и регистрационный токен",
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Код типа",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
                         "name": "input",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterRequest"
+                            "$ref": "#/definitions/models.UpdateEventTypeRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Успешная регистрация",
+                    "200": {
+                        "description": "Обновленный тип",
                         "schema": {
-                            "$ref": "#/definitions/models.RegisterResponse"
+                            "$ref": "#/definitions/models.EventType"
                         }
                     },
                     "400": {
-                        "description": "Некорректный формат JSON или токен истёк",
+                        "description": "Некорректные данные",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Студент с таким номером студенческого не найден",
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "409": {
-                        "description": "Пользователь с такой почтой уже существует",
+                    "404": {
+                        "description": "Тип не найден",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка сервера (база данных, хеширование, транзакция)",
+                        "description": "Ошибка при изменении типа",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1005,9 +1179,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/reset_password": {
-            "post": {
-                "description": "Отправляет на почту пользователя ссылку для сброса пароля.\nЕсли пользователь с указанной почтой существует — ему придёт письмо с временной ссылкой на установку нового пароля.",
+        "/admin/events": {
+            "get": {
+                "description": "Возвращает полный список событий из базы данных.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1015,38 +1189,38 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Запрос на сброс пароля",
+                "summary": "Получить все события",
                 "parameters": [
                     {
-                        "description": "Почта пользователя",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ResetPasswordRequest"
-                        }
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество мероприятий в выдаче",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Инструкция отправлена на почту",
+                        "description": "Список событий",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Некорректный JSON",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    },
                     "500": {
-                        "description": "Ошибка при поиске пользователя или отправке письма",
+                        "description": "Ошибка сервера при получении списка событий",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1054,51 +1228,136 @@ const docTemplate = `{
                 }
             }
         },
-        "/auth/set_new_password": {
-            "post": {
-                "description": "Устанавливает новый пароль по токену из письма. Токен действителен ограниченное время (15 минут).",
-                "consumes": [
-                    "application/json"
+        "/admin/events/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Полнотекстовый поиск по названию и описанию (морфология русского языка) с фильтрами и сортировкой.\nВыдача постраничная: следующая страница запрашивается с cursor из next_cursor и теми же sort и order.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "admin"
                 ],
-                "summary": "Установка нового пароля",
+                "summary": "Поиск событий",
                 "parameters": [
                     {
-                        "description": "Токен и новый пароль",
-                        "name": "input",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.SetNewPasswordRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Пароль успешно обновлён",
-                        "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
-                        }
-                    },
-                    "400": {
-                        "description": "Некорректный JSON",
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Тип мероприятия",
+                        "name": "event_type_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата события от (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата события до, не включая (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Баллы от",
+                        "name": "points_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Баллы до (включительно)",
+                        "name": "points_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Статусы через запятую (draft,published,archived), по умолчанию любые",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Искать среди удаленных событий",
+                        "name": "deleted",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "date",
+                            "points"
+                        ],
+                        "type": "string",
+                        "default": "date",
+                        "description": "Сортировка",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Порядок",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница событий",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventSearchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры поиска",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "401": {
-                        "description": "Невалидный или истекший токен",
+                    "403": {
+                        "description": "Недостаточно прав",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при обновлении пароля",
+                        "description": "Ошибка при поиске событий",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1106,41 +1365,71 @@ const docTemplate = `{
                 }
             }
         },
-        "/get_suggests": {
-            "get": {
-                "description": "Возвращает список рекомендаций для событий. Если произошла ошибка при получении данных, возвращается код ошибки 500.",
-                "consumes": [
-                    "application/json"
+        "/admin/events/{id}/archive": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Отправляет событие в архив: оно пропадает из каталога, запись и отметка по QR коду закрываются.\nВыполненные события и баллы студентов сохраняются.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Получить список рекомендаций для событий",
+                "summary": "Архивация события",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список рекомендаций событий",
+                        "description": "Событие",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Event"
-                            }
+                            "$ref": "#/definitions/models.CreateEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие удалено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении рекомендаций",
+                        "description": "Ошибка при изменении события",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1148,42 +1437,77 @@ const docTemplate = `{
                 }
             }
         },
-        "/leaderboard": {
-            "get": {
+        "/admin/events/{id}/attendance": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает список пользователей, отсортированный по количеству набранных очков.",
+                "description": "Отмечает пришедших на событие и засчитывает им событие с начислением баллов одним действием.\nall=true отмечает всех получивших место. Не записанные и ожидающие пропускаются,\nуже выполнившим событие баллы повторно не начисляются.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Лидерборд пользователей",
+                "summary": "Отметка посещения",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "integer",
-                        "default": 50,
-                        "description": "Максимальное количество пользователей в выдаче",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Пришедшие пользователи",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MarkAttendanceRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Список пользователей с их количеством очков",
+                        "description": "Итог отметки",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.UserWithPoints"
-                            }
+                            "$ref": "#/definitions/models.MarkAttendanceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении лидерборда",
+                        "description": "Ошибка при отметке посещения",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1191,40 +1515,68 @@ const docTemplate = `{
                 }
             }
         },
-        "/me/completed_events": {
+        "/admin/events/{id}/attendees": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает список выполненных пользователем событий, а также статистику по категориям:\n- Хакатоны (type = 1)\n- Статьи (type = 2)\n- Олимпиады (type = 3)\n- Проекты (type = 4)",
+                "description": "Возвращает получивших место (status=registered) и лист ожидания по порядку,\nс отметкой посещения и признаком засчитанного события.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Получить выполненные события пользователя",
+                "summary": "Список записавшихся на событие",
                 "parameters": [
                     {
                         "type": "string",
                         "default": "Bearer",
-                        "description": "Bearer токен в формате: Bearer {token}",
+                        "description": "Bearer токен",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Записавшиеся",
                         "schema": {
-                            "$ref": "#/definitions/models.CompletedEventsFullResponse"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Attendee"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при получении данных",
+                        "description": "Ошибка при получении списка",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1232,21 +1584,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/me/profile": {
+        "/admin/events/{id}/checkin": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Возвращает данные о пользователе",
+                "description": "Возвращает окно самостоятельной отметки по QR коду и число отметившихся.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Получение профиля пользователя",
+                "summary": "Окно отметки на событии",
                 "parameters": [
                     {
                         "type": "string",
@@ -1255,308 +1607,6744 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Данные о пользователе",
+                        "description": "Окно отметки",
                         "schema": {
-                            "$ref": "#/definitions/models.ProfileResponse"
+                            "$ref": "#/definitions/models.CheckinSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено или окно не задано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
                     "500": {
-                        "description": "Ошибка при запросе или чтении данных",
+                        "description": "Ошибка при получении окна отметки",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            }
-        }
-    },
-    "definitions": {
-        "models.AuthBookRequest": {
-            "type": "object",
-            "properties": {
-                "book_id": {
-                    "type": "integer"
-                }
-            }
-        },
-        "models.AuthStatus": {
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Задает время, в которое студенты могут отметиться на событии по QR коду.\nИзменение окна не отзывает уже выданные токены, для этого есть /checkin/rotate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Задание окна отметки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Окно отметки",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetCheckinSettingsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Окно отметки",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckinSettings"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сохранении окна отметки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events/{id}/checkin/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет ключ подписи QR кодов события: все ранее выданные токены перестают действовать.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Отзыв токенов отметки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Ключ заменен"
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено или окно не задано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при замене ключа",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events/{id}/checkin/token": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает подписанный токен для показа в QR коде. Токен живет ttl секунд (от 10 до 600, по умолчанию 60),\nно не дольше окна отметки. Экран с QR кодом запрашивает новый токен до expires_at. Вне окна токен не выдается.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Токен для QR кода отметки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 60,
+                        "description": "Время жизни токена в секундах",
+                        "name": "ttl",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckinTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено или окно не задано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Отметка еще не началась или закончилась",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при выдаче токена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events/{id}/publish": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Публикует черновик или возвращает событие из архива: оно появляется в каталоге студентов.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Публикация события",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие удалено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/events/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает удаленное событие с тем статусом, который был до удаления.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Восстановление удаленного события",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateEventResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие не удалено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/groups": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавляет учебную группу. Без study_id группа попадает в список групп без направления.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Добавление группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Группа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Добавленная группа",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Направление не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Такое название уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении группы",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/groups/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет группу, в которой нет студентов реестра и пользователей.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удаление группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Группа удалена"
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В группе есть студенты или пользователи",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении группы",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет название группы или переносит ее на другое направление. Пустые поля не меняются. Студенты и пользователи группы сразу видят новое название.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменение группы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id группы",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленная группа",
+                        "schema": {
+                            "$ref": "#/definitions/models.StudentGroup"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа или направление не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Такое название уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении группы",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/impersonate/{user_id}": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает access токен от имени пользователя с ролью ниже своей, чтобы увидеть сервис его глазами.\nТокен содержит claim act с id разработчика, по умолчанию разрешает только запросы на чтение и не продлевается.\nНачало и завершение входа записываются в журнал.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Вход от имени пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Причина и режим доступа",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StartImpersonationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Токен от имени пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImpersonationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при входе от имени пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/impersonations": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает последние входы от имени пользователей: кто, к кому, зачем, когда начал и закончил.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "История входов от имени пользователей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только входы от имени этого пользователя",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество записей",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессии, новые первыми",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ImpersonationSession"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении истории",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/impersonations/{id}/end": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает сессию входа от имени пользователя до истечения токена. Выданный токен перестает приниматься.\nЗавершить можно только свою сессию, чужую - с правом impersonations:manage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Завершение входа от имени пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сессии входа",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Завершенная сессия",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImpersonationSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия не найдена, уже завершена или начата другим",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при завершении сессии",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/institutes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Добавление института",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Название института",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InstituteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Добавленный институт",
+                        "schema": {
+                            "$ref": "#/definitions/models.Institute"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Такое название уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении института",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/institutes/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет институт, в котором не осталось направлений.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удаление института",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id института",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Институт удален"
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Институт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В институте есть направления",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении института",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Переименование института",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id института",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новое название",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.InstituteRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный институт",
+                        "schema": {
+                            "$ref": "#/definitions/models.Institute"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Институт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Такое название уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении института",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все права, которые можно выдать ролям.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Список прав",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список прав",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Permission"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/points/reconcile": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Сверяет журнал баллов с выполненными событиями (баллы за событие должны совпадать с текущими баллами события)\nи суммы в user_points с журналом. Возвращает найденные расхождения; без dry_run исправляет их записями reconcile\nи пересчетом сумм. То же можно выполнить командой go run ./cmd/reconcile.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Сверка баллов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Только найти расхождения",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчет о сверке",
+                        "schema": {
+                            "$ref": "#/definitions/models.PointsReconcileReport"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при сверке баллов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все роли вместе с выданными им правами.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Список ролей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список ролей",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении ролей",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Создает роль с уровнем ниже вашего. Выдать роли можно только те права, которые есть у вас самих.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Создание роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Код, название, уровень и права роли",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Созданная роль",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или неизвестное право",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Роль с таким кодом или уровнем уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании роли",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/roles/{level}/permissions": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Заменяет набор прав роли с уровнем ниже вашего. Access токены пользователей с этой ролью отзываются,\nновые права применяются после обновления токенов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменение прав роли",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Уровень роли",
+                        "name": "level",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новый набор прав",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetRolePermissionsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Права роли обновлены",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или неизвестное право",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Роль не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/rollover": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выпускает группы graduating_groups: студенты реестра и пользователи этих групп получают archived_at, перестают попадать в лидерборд, но сохраняют выполненные мероприятия.\nЗатем переводит действующих студентов и пользователей по таблице mappings (from -\u003e to). Выпуск выполняется раньше перевода, поэтому выпускающаяся группа может сразу принять следующий курс.\nГруппа to, которой еще нет, создается на направлении группы from. При dry_run=true ничего не меняет и возвращает тот же отчет.\nТо же можно выполнить командой go run ./cmd/rollover -file mapping.json.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Переход на новый учебный год",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Таблица перевода и выпускающиеся группы",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RolloverRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Только посчитать изменения",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчет о переводе",
+                        "schema": {
+                            "$ref": "#/definitions/models.RolloverReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная таблица перевода",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при переводе",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/students": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает всех студентов из таблицы students.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Получение списка студентов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество студентов в выдаче",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список студентов",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Student"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при запросе или чтении данных",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавляет студента в реестр, после чего он может зарегистрироваться через /auth/check.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Добавление студента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные студента",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStudentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Добавленный студент",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Студент с таким номером зачетки уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении студента",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/students/import": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загружает список студентов из CSV (разделитель \",\" или \";\") или XLSX (первый лист) в реестр, по которому проходит регистрация.\nПервая строка - заголовки: book_id (номер зачетной книжки), fio (ФИО) или surname, name, middle_name, birth_date (дата рождения, ДД.ММ.ГГГГ или ГГГГ-ММ-ДД), student_group (группа). Допускаются русские названия колонок.\nПри dry_run=true ничего не меняет и возвращает построчный отчет: insert, update, unchanged, conflict, invalid.\nИначе применяет все изменения в одной транзакции; если есть невалидные или конфликтующие строки, не применяет ничего и возвращает 422 с отчетом.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Загрузка списка студентов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл .csv или .xlsx",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "default": false,
+                        "description": "Только проверить файл",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Отчет об импорте",
+                        "schema": {
+                            "$ref": "#/definitions/models.RosterImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Нет файла, неподдерживаемый формат, нет обязательных колонок или слишком много строк",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Слишком большой файл",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "В файле есть ошибки, изменения не применены",
+                        "schema": {
+                            "$ref": "#/definitions/models.RosterImportReport"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при импорте",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/students/{book_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет студента из реестра. Аккаунт, зарегистрированный по его зачетке, сохраняется вместе с выполненными событиями, но отвязывается от зачетки, а его сессии завершаются.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удаление студента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер зачетной книжки",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Студент удален",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный номер зачетки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Студент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении студента",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет данные студента в реестре. Пустые поля не меняются. Аккаунт, зарегистрированный по этой зачетке, получает те же ФИО, дату рождения, группу и номер зачетки.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменение студента",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Номер зачетной книжки",
+                        "name": "book_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStudentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный студент",
+                        "schema": {
+                            "$ref": "#/definitions/models.Student"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Студент не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Новый номер зачетки уже занят",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Группа не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении студента",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/studies": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Добавление направления подготовки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Направление",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStudyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Добавленное направление",
+                        "schema": {
+                            "$ref": "#/definitions/models.Study"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Институт не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Такое название уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при добавлении направления",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/studies/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет направление, в котором не осталось групп.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Удаление направления подготовки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id направления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Направление удалено"
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Направление не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "В направлении есть группы",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении направления",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет название направления или переносит его в другой институт. Пустые поля не меняются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Изменение направления подготовки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id направления",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStudyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленное направление",
+                        "schema": {
+                            "$ref": "#/definitions/models.Study"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Направление или институт не найдены",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Такое название уже используется",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении направления",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/unlock": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Снимает блокировки и сбрасывает счетчики попыток для почты, номера студенческого или IP адреса.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Разблокировать аккаунт или IP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Почта, номер студенческого или IP",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UnlockRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Блокировка снята",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Блокировок для этого ключа нет",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при снятии блокировки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/update_event": {
+            "patch": {
+                "description": "Производит частичное обновление данных события по его ID.\nОбновляются только те поля, которые переданы в теле запроса.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Обновить выбранные поля события",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "ID события и новые значения полей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateEventRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие успешно обновлено",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или ошибка валидации",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неавторизованный доступ — неверный или отсутствующий токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие с таким названием уже существует или событие удалено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера при попытке обновления записи",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/update_user": {
+            "patch": {
+                "description": "Обновляет данные пользователя с проверкой прав доступа. Только пользователи с более высоким уровнем прав могут изменять данные пользователей с более низким уровнем прав.\nТребует право users:write, для смены роли - дополнительно users:manage_roles.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Обновление данных пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен в формате: Bearer {token} default(Bearer )",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Данные для обновления пользователя",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное обновление данных пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUserResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Ошибка в формате JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Такой роли или группы не существует",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Внутренняя ошибка сервера",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/uploads/icon": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загружает иконку (PNG, JPEG, WebP или GIF до 1 МБ) для события или типа мероприятия.\nПолученный url передается в icon_url при создании или изменении.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Загрузка иконки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Изображение",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Загруженный файл",
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadedFile"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не передан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Недопустимый тип файла",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при загрузке файла",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает всех зарегистрированных пользователей из таблицы users.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Получение списка пользователей",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество пользователей в выдаче",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список пользователей",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при запросе или чтении данных",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{user_id}/points": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает сумму баллов пользователя и записи журнала баллов, новые первыми.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Баллы и история начислений пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество записей (до 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Сколько записей пропустить",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Баллы и история",
+                        "schema": {
+                            "$ref": "#/definitions/models.PointsHistoryResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный user_id",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении баллов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Добавляет в журнал баллов запись reason=manual: начисление (delta \u003e 0) или списание (delta \u003c 0).\nКомментарий обязателен и виден пользователю в истории баллов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Ручное изменение баллов",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID пользователя",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Изменение баллов",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AdjustPointsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запись журнала",
+                        "schema": {
+                            "$ref": "#/definitions/models.PointsTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные данные",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Недостаточно прав",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении баллов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/check": {
+            "post": {
+                "description": "Проверяет наличие студенческого билета в системе.\nЕсли студент не зарегистрирован, генерирует временный токен для регистрации.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Проверка студенческого в системе",
+                "parameters": [
+                    {
+                        "description": "Номер студенческого билета",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthBookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Студенческий найден, выдан регистрационный токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос — ошибка парсинга JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Студенческий не найден в базе",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь с таким номером уже зарегистрирован",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много запросов с этого IP или для этого номера",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при генерации или сохранении токена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login": {
+            "post": {
+                "description": "Проверяет почту и пароль пользователя. При успешной авторизации выдает пару access и refresh токенов.\nЕсли у администратора включена 2FA, токены не выдаются: ответ содержит mfa_required = true и mfa_token,\nкоторый вместе с кодом нужно отправить на /auth/login/mfa.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Авторизация пользователя",
+                "parameters": [
+                    {
+                        "description": "Данные для входа (почта и пароль)",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешная авторизация — возвращается пользователь и пара токенов",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос — ошибка парсинга JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неправильный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с такой почтой не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неудачных попыток: Error = too_many_attempts (по IP) или account_locked (аккаунт заблокирован)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера при работе с базой данных или токенами",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/login/mfa": {
+            "post": {
+                "description": "Принимает mfa_token, выданный /auth/login с mfa_required = true, и код из приложения-аутентификатора\nили одноразовый код восстановления. При успехе выдает пару access и refresh токенов.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Второй шаг входа (2FA)",
+                "parameters": [
+                    {
+                        "description": "Токен челленджа и код",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginMFARequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешная авторизация — возвращается пользователь и пара токенов",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoginResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный запрос — ошибка парсинга JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неверный код или челлендж истек",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много неверных кодов: Error = too_many_attempts (по IP) или account_locked (аккаунт заблокирован)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера при работе с базой данных или токенами",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Завершает сессию, из которой выдан access токен: удаляет её refresh токены.\nAccess токен этой сессии перестает приниматься сразу, не дожидаясь истечения срока.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Выход из аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессия завершена",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Невалидный или отозванный access токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия уже завершена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при завершении сессии",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/refresh": {
+            "post": {
+                "description": "Принимает refresh токен, проверяет его валидность и возвращает новую пару токенов.\nКаждый refresh токен одноразовый. Повторное предъявление уже обменянного токена\nотзывает всю сессию и возвращает 401 с Error = \"refresh_token_reused\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Обновление access и refresh токенов",
+                "parameters": [
+                    {
+                        "description": "Refresh токен",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Успешное обновление токенов",
+                        "schema": {
+                            "$ref": "#/definitions/models.RefreshTokenResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Refresh токен уже был использован, сессия отозвана",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Невалидный или просроченный refresh токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении/генерации токенов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/register": {
+            "post": {
+                "description": "Регистрирует нового пользователя на основе временного токена, выданного после проверки студенческого билета.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Регистрация пользователя по токену",
+                "parameters": [
+                    {
+                        "description": "Почта, пароль и регистрационный токен",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Успешная регистрация",
+                        "schema": {
+                            "$ref": "#/definitions/models.RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный формат JSON или токен истёк",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Студент с таким номером студенческого не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Пользователь с такой почтой уже существует",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка сервера (база данных, хеширование, транзакция)",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/resend_verification": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отправляет новый токен подтверждения на текущую почту пользователя. Предыдущий токен перестает действовать.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Повторная отправка письма подтверждения",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Письмо отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Нет токена или токен невалиден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Почта уже подтверждена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много запросов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отправке письма",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/reset_password": {
+            "post": {
+                "description": "Отправляет на почту пользователя ссылку для сброса пароля.\nЕсли пользователь с указанной почтой существует — ему придёт письмо с временной ссылкой на установку нового пароля.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Запрос на сброс пароля",
+                "parameters": [
+                    {
+                        "description": "Почта пользователя",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResetPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Инструкция отправлена на почту",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Почта пользователя не подтверждена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь с такой почтой не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Слишком много запросов с этого IP или для этой почты",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при поиске пользователя или отправке письма",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/set_new_password": {
+            "post": {
+                "description": "Устанавливает новый пароль по токену из письма. Токен действителен ограниченное время (15 минут).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Установка нового пароля",
+                "parameters": [
+                    {
+                        "description": "Токен и новый пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SetNewPasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль успешно обновлён",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Невалидный или истекший токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при обновлении пароля",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/auth/verify_email": {
+            "post": {
+                "description": "Подтверждает владение почтой по токену из письма, отправленного при регистрации или через /auth/resend_verification.\nТокен действителен 24 часа и только для той почты, на которую был отправлен.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Подтверждение почты",
+                "parameters": [
+                    {
+                        "description": "Токен из письма",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.VerifyEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Почта подтверждена",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Невалидный или истекший токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при подтверждении почты",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/event_types": {
+            "get": {
+                "description": "Возвращает все типы мероприятий с иконками и цветами, упорядоченные по коду.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "events"
+                ],
+                "summary": "Типы мероприятий",
+                "responses": {
+                    "200": {
+                        "description": "Типы мероприятий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EventType"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении типов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает предстоящие (по возрастанию даты) или прошедшие (начиная с последних) события.\nУ каждого события указано, засчитано ли оно текущему пользователю, и число участников.\nСледующая страница запрашивается с cursor из next_cursor и тем же when.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Каталог событий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "upcoming",
+                            "past",
+                            "all"
+                        ],
+                        "type": "string",
+                        "default": "upcoming",
+                        "description": "Какие события показать",
+                        "name": "when",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Тип мероприятия",
+                        "name": "event_type_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница каталога",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventCatalogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении событий",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Полнотекстовый поиск по опубликованным событиям (морфология русского языка) с фильтрами и сортировкой.\nУ каждого события указано, засчитано ли оно текущему пользователю, и число участников.\nВыдача постраничная: следующая страница запрашивается с cursor из next_cursor и теми же sort и order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Поиск по каталогу событий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Поисковый запрос",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Тип мероприятия",
+                        "name": "event_type_code",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата события от (RFC3339)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Дата события до, не включая (RFC3339)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Баллы от",
+                        "name": "points_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Баллы до (включительно)",
+                        "name": "points_max",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "date",
+                            "points"
+                        ],
+                        "type": "string",
+                        "default": "date",
+                        "description": "Сортировка",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "Порядок",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Курсор следующей страницы",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Размер страницы (до 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Страница событий",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventCatalogResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректные параметры поиска",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при поиске событий",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает событие с отметкой, засчитано ли оно текущему пользователю, и числом участников.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Событие из каталога",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.CatalogEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/get_suggests": {
+            "get": {
+                "description": "Возвращает список рекомендаций для событий. Если произошла ошибка при получении данных, возвращается код ошибки 500.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получить список рекомендаций для событий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен авторизации. Формат: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список рекомендаций событий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Event"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении рекомендаций",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/institutes": {
+            "get": {
+                "description": "Возвращает институты с направлениями подготовки и группами, а также группы, которым еще не назначено направление.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "institutes"
+                ],
+                "summary": "Структура институтов",
+                "responses": {
+                    "200": {
+                        "description": "Структура институтов",
+                        "schema": {
+                            "$ref": "#/definitions/models.InstitutesTree"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении структуры",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/leaderboard": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает список пользователей, отсортированный по количеству набранных очков.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Лидерборд пользователей",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество пользователей в выдаче",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только студенты института",
+                        "name": "institute_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только студенты направления",
+                        "name": "study_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Только студенты группы",
+                        "name": "student_group_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список пользователей с их количеством очков",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.UserWithPoints"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении лидерборда",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Показывает, включена ли двухфакторная аутентификация, обязательна ли она для роли пользователя\nи сколько осталось кодов восстановления.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Состояние 2FA",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Состояние 2FA",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorStatus"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении данных",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/disable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отключает 2FA. Требует текущий пароль и код из приложения или код восстановления.\nПользователи с ролью администратора и выше без 2FA теряют доступ к /admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отключить 2FA",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Пароль и код",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorDisableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "2FA отключена",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неправильный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA не включена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отключении 2FA",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/enable": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Проверяет код из приложения-аутентификатора и включает 2FA. Возвращает одноразовые коды восстановления —\nони показываются только один раз. Остальные сессии пользователя завершаются.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Подтвердить и включить 2FA",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Код из приложения",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Коды восстановления",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorRecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена или секрет не запрошен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при включении 2FA",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/recovery_codes": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Выдает новый набор кодов восстановления, старые перестают действовать.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Новые коды восстановления",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Код из приложения или код восстановления",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Новые коды восстановления",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorRecoveryCodesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или неверный код",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA не включена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при генерации кодов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/2fa/setup": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Генерирует новый TOTP секрет и otpauth:// URI для QR кода. 2FA включится после подтверждения кодом (/me/2fa/enable).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получить секрет для приложения-аутентификатора",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Секрет и URI для QR кода",
+                        "schema": {
+                            "$ref": "#/definitions/models.TwoFactorSetupResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "2FA уже включена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при генерации секрета",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/avatar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загружает аватар (PNG, JPEG, WebP или GIF до 2 МБ) и сразу ставит его в профиль.\nТип файла определяется по содержимому.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Загрузка аватара",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Изображение",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный профиль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не передан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Недопустимый тип файла",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при загрузке файла",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/checkin": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Принимает отсканированный токен и засчитывает событие текущему пользователю с начислением баллов.\nОтметиться можно один раз и только в окно отметки. На события с ограничением мест - только получившим место.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отметка на событии по QR коду",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Токен из QR кода",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckinRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Событие засчитано",
+                        "schema": {
+                            "$ref": "#/definitions/models.CheckinResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный или недействительный токен",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Пользователь не записан на событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Вне окна отметки или событие уже засчитано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "410": {
+                        "description": "Срок действия токена истек",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отметке на событии",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/claims": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает заявки текущего пользователя со статусами и причинами отклонения, новые первыми.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Мои заявки",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Заявки",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Claim"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении заявок",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Студент сообщает о выполненном мероприятии: комментарий и до 10 подтверждений (ссылки http/https или файлы, загруженные через /me/uploads).\nЗаявка попадает в очередь модерации, баллы начисляются после одобрения.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Подать заявку на баллы",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Заявка",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateClaimRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Созданная заявка",
+                        "schema": {
+                            "$ref": "#/definitions/models.Claim"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректная заявка",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено, удалено или еще не опубликовано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Событие уже выполнено или заявка уже ждет рассмотрения",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при создании заявки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/claims/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отзывает заявку, пока она не рассмотрена.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отозвать заявку",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID заявки",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Заявка отозвана"
+                    },
+                    "400": {
+                        "description": "Некорректный ID заявки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Заявка не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Заявка уже рассмотрена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отзыве заявки",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/completed_events": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает список выполненных пользователем событий, а также статистику по категориям:\n- Хакатоны (type = 1)\n- Статьи (type = 2)\n- Олимпиады (type = 3)\n- Проекты (type = 4)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получить выполненные события пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен в формате: Bearer {token}",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CompletedEventsFullResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении данных",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Планирует удаление аккаунта через 14 дней. Требует пароль. До этого срока удаление можно отменить. После срока персональные данные стираются, а выполненные события и баллы остаются в общей статистике без привязки к личности.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Удаление аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Текущий пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteAccountRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Удаление запланировано",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeleteAccountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неправильный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Удаление уже запланировано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при удалении аккаунта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет запланированное удаление аккаунта, если срок еще не истек.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отмена удаления аккаунта",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Удаление отменено",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Удаление не запланировано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отмене удаления",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/email": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет почту пользователя. Требует текущий пароль. Новая почта считается неподтвержденной\nдо ввода токена из письма в /auth/verify_email.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Смена почты",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Пароль и новая почта",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangeEmailRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Почта изменена, письмо с подтверждением отправлено",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или почта",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неправильный пароль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Почта занята другим пользователем",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при смене почты",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/events/{id}/register": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает статус записи текущего пользователя и место в листе ожидания.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Моя запись на событие",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Запись на событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventRegistration"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено или пользователь не записан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении записи",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Записывает текущего пользователя на событие. Если мест нет, пользователь попадает в лист ожидания\nи получает место автоматически, когда оно освободится.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Запись на событие",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Запись или место в листе ожидания",
+                        "schema": {
+                            "$ref": "#/definitions/models.EventRegistration"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Запись закрыта, уже записан или событие уже засчитано",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при записи на событие",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/events/{id}/unregister": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Отменяет запись текущего пользователя (или убирает из листа ожидания).\nОсвободившееся место получает первый из листа ожидания. После отметки посещения отменить запись нельзя.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Отмена записи на событие",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID события",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "Запись отменена"
+                    },
+                    "400": {
+                        "description": "Некорректный ID события",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Событие не найдено или пользователь не записан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Посещение уже отмечено",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при отмене записи",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все данные, которые сервис хранит о пользователе: профиль, выполненные события, историю баллов и сессии. При format=zip данные отдаются архивом с отдельным JSON файлом на каждый раздел.",
+                "produces": [
+                    "application/json",
+                    "application/zip"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Выгрузка персональных данных",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Формат выгрузки: json (по умолчанию) или zip",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные пользователя",
+                        "schema": {
+                            "$ref": "#/definitions/models.AccountExport"
+                        }
+                    },
+                    "400": {
+                        "description": "Неизвестный формат",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Пользователь не найден",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при выгрузке данных",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/password": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет пароль пользователя. Требует текущий пароль. Все сессии, кроме текущей, завершаются,\nвыданные access токены отзываются: текущая сессия получает новый через /auth/refresh.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Смена пароля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Текущий и новый пароль",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ChangePasswordRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Пароль изменен",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON или слабый пароль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Неправильный текущий пароль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при смене пароля",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/points": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает сумму баллов и записи журнала баллов, новые первыми: начисления за выполненные события,\nсписания при снятии отметки, пересчет при изменении баллов события и ручные изменения с комментарием.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Баллы и история начислений",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Максимальное количество записей (до 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Сколько записей пропустить",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Баллы и история",
+                        "schema": {
+                            "$ref": "#/definitions/models.PointsHistoryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении баллов",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает данные о пользователе",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Получение профиля пользователя",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Данные о пользователе",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProfileResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при запросе или чтении данных",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Меняет поля профиля, доступные пользователю (аватар). ФИО, группа и номер книжки меняются только администратором.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Изменение профиля",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Новые значения полей",
+                        "name": "input",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Обновленный профиль",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProfileResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный JSON",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при изменении профиля",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/sessions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Возвращает все активные сессии (устройства) текущего пользователя.\nСессия, с которой выполнен запрос, помечена полем current.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Список активных сессий",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Список сессий",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Session"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при получении сессий",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет refresh токены всех сессий пользователя, включая текущую.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Завершить все сессии",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессии завершены",
+                        "schema": {
+                            "$ref": "#/definitions/models.RevokeSessionsResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при завершении сессий",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/sessions/{id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Удаляет refresh токен выбранной сессии. Устройство будет разлогинено после истечения access токена.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Завершить сессию",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "ID сессии",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Сессия завершена",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Некорректный ID сессии",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Сессия не найдена",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при завершении сессии",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/me/uploads": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Загружает скан, фото или сертификат (PNG, JPEG, WebP или PDF до 10 МБ).\nПолученный url передается в attachments при подаче заявки на /me/claims.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Загрузка подтверждения к заявке",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "default": "Bearer",
+                        "description": "Bearer токен",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Файл",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Загруженный файл",
+                        "schema": {
+                            "$ref": "#/definitions/models.UploadedFile"
+                        }
+                    },
+                    "400": {
+                        "description": "Файл не передан",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Файл слишком большой",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Недопустимый тип файла",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Ошибка при загрузке файла",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "helpers.JWK": {
+            "type": "object",
+            "properties": {
+                "alg": {
+                    "type": "string"
+                },
+                "crv": {
+                    "type": "string"
+                },
+                "e": {
+                    "type": "string"
+                },
+                "kid": {
+                    "type": "string"
+                },
+                "kty": {
+                    "type": "string"
+                },
+                "n": {
+                    "type": "string"
+                },
+                "use": {
+                    "type": "string"
+                },
+                "x": {
+                    "type": "string"
+                }
+            }
+        },
+        "helpers.JWKSet": {
+            "type": "object",
+            "properties": {
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/helpers.JWK"
+                    }
+                }
+            }
+        },
+        "models.AccountExport": {
+            "type": "object",
+            "properties": {
+                "completed_events": {
+                    "$ref": "#/definitions/models.CompletedEventsFullResponse"
+                },
+                "exported_at": {
+                    "type": "string"
+                },
+                "points_history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointsHistoryEntry"
+                    }
+                },
+                "profile": {
+                    "$ref": "#/definitions/models.ProfileResponse"
+                },
+                "sessions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Session"
+                    }
+                }
+            }
+        },
+        "models.AdjustPointsRequest": {
+            "type": "object",
+            "required": [
+                "comment",
+                "delta"
+            ],
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Attendee": {
+            "type": "object",
+            "properties": {
+                "attended_at": {
+                    "type": "string"
+                },
+                "completed": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "registered",
+                        "waitlisted"
+                    ]
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "waitlist_position": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "actor_id": {
+                    "type": "integer"
+                },
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "target_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthBookRequest": {
+            "type": "object",
+            "properties": {
+                "book_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuthLock": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "key_type": {
+                    "type": "string"
+                },
+                "locked_until": {
+                    "type": "string"
+                },
+                "lockouts": {
+                    "type": "integer"
+                },
+                "scope": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthStatus": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string"
+                },
+                "link_token": {
+                    "type": "string"
+                },
+                "link_token_ttl_sec": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "student_group": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuthTokens": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CatalogEvent": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "completed": {
+                    "description": "Completed - событие уже засчитано текущему пользователю",
+                    "type": "boolean"
+                },
+                "completed_at": {
+                    "type": "string"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type_code": {
+                    "type": "integer"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "participants_count": {
+                    "description": "ParticipantsCount - скольким студентам засчитано событие",
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "registration_closes_at": {
+                    "type": "string"
+                },
+                "registration_opens_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangeEmailRequest": {
+            "type": "object",
+            "required": [
+                "new_email",
+                "password"
+            ],
+            "properties": {
+                "new_email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChangePasswordRequest": {
+            "type": "object",
+            "required": [
+                "current_password",
+                "new_password"
+            ],
+            "properties": {
+                "current_password": {
+                    "type": "string"
+                },
+                "new_password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckinRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckinResponse": {
+            "type": "object",
+            "properties": {
+                "checked_in_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckinSettings": {
+            "type": "object",
+            "properties": {
+                "checked_in_count": {
+                    "description": "CheckedInCount - сколько студентов уже отметились",
+                    "type": "integer"
+                },
+                "closes_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "opens_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CheckinTokenResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Claim": {
+            "type": "object",
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClaimAttachment"
+                    }
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_points": {
+                    "type": "integer"
+                },
+                "event_title": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reject_reason": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "approved",
+                        "rejected",
+                        "withdrawn"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "user_name": {
+                    "type": "string"
+                },
+                "user_surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClaimAttachment": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClaimAttachmentInput": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://github.com/user/project"
+                }
+            }
+        },
+        "models.CompleteUserEventRequest": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompletedEvent": {
+            "type": "object",
+            "properties": {
+                "completed_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CompletedEventsFullResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.UserCompletedEvent"
+                    }
+                },
+                "stats": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CompletedEventsStat"
+                    }
+                }
+            }
+        },
+        "models.CompletedEventsStat": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string",
+                    "example": "#3F51B5"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateClaimRequest": {
+            "type": "object",
+            "required": [
+                "event_id"
+            ],
+            "properties": {
+                "attachments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ClaimAttachmentInput"
+                    }
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateEventRequest": {
+            "type": "object",
+            "required": [
+                "title"
+            ],
+            "properties": {
+                "capacity": {
+                    "description": "Capacity - число мест, без него запись не ограничена",
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_type_code": {
+                    "type": "integer"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "registration_closes_at": {
+                    "type": "string"
+                },
+                "registration_opens_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "Status - draft или published, по умолчанию published",
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published"
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateEventResponse": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type_code": {
+                    "type": "integer"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "registration_closes_at": {
+                    "type": "string"
+                },
+                "registration_opens_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateEventTypeRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string",
+                    "example": "#3F51B5"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "study_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateRoleRequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "level",
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateStudentRequest": {
+            "type": "object",
+            "required": [
+                "birth_date",
+                "book_id",
+                "name",
+                "student_group",
+                "surname"
+            ],
+            "properties": {
+                "birth_date": {
+                    "type": "string",
+                    "example": "2003-02-01"
+                },
+                "book_id": {
+                    "type": "integer"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "student_group": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateStudyRequest": {
+            "type": "object",
+            "required": [
+                "institute_id",
+                "name"
+            ],
+            "properties": {
+                "institute_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSuggestRequest": {
+            "type": "object",
+            "required": [
+                "event_id"
+            ],
+            "properties": {
+                "event_id": {
+                    "type": "integer"
+                },
+                "expires_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeleteAccountRequest": {
+            "type": "object",
+            "required": [
+                "password"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeleteAccountResponse": {
+            "type": "object",
+            "properties": {
+                "deletion_scheduled_at": {
+                    "type": "string"
+                },
+                "successful": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DeleteCompletedEventResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer"
+                },
+                "successful": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeleteEventResponse": {
+            "type": "object",
+            "properties": {
+                "event_id": {
+                    "type": "integer"
+                },
+                "successful": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.DeleteUserResponse": {
+            "type": "object",
+            "properties": {
+                "successful": {
+                    "type": "boolean"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Event": {
+            "type": "object",
+            "properties": {
+                "capacity": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "event_date": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_type_code": {
+                    "type": "integer"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "integer"
+                },
+                "registration_closes_at": {
+                    "type": "string"
+                },
+                "registration_opens_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "draft",
+                        "published",
+                        "archived"
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventCatalogResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CatalogEvent"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventRegistration": {
+            "type": "object",
+            "properties": {
+                "attended_at": {
+                    "type": "string"
+                },
+                "capacity": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "registered_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "registered",
+                        "waitlisted"
+                    ]
+                },
+                "waitlist_position": {
+                    "description": "WaitlistPosition - место в листе ожидания, начиная с 1. У записанных - 0",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EventSearchResponse": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Event"
+                    }
+                },
+                "next_cursor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventType": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "color": {
+                    "type": "string",
+                    "example": "#3F51B5"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GroupMapping": {
+            "type": "object",
+            "required": [
+                "from",
+                "to"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string",
+                    "example": "ИВТ-21"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "ИВТ-31"
+                }
+            }
+        },
+        "models.ImpersonationResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "exp_unix": {
+                    "type": "integer"
+                },
+                "session": {
+                    "$ref": "#/definitions/models.ImpersonationSession"
+                }
+            }
+        },
+        "models.ImpersonationSession": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "ended_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "read_only": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Institute": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "studies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Study"
+                    }
+                }
+            }
+        },
+        "models.InstituteRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InstitutesTree": {
+            "type": "object",
+            "properties": {
+                "institutes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Institute"
+                    }
+                },
+                "unassigned_groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentGroup"
+                    }
+                }
+            }
+        },
+        "models.LoginMFARequest": {
+            "type": "object",
+            "required": [
+                "code",
+                "mfa_token"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "device_name": {
+                    "type": "string"
+                },
+                "mfa_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "device_name": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "$ref": "#/definitions/models.AuthTokens"
+                },
+                "mfa_expires_in_sec": {
+                    "type": "integer"
+                },
+                "mfa_required": {
+                    "type": "boolean"
+                },
+                "mfa_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.UserSubstructure"
+                }
+            }
+        },
+        "models.MarkAttendanceRequest": {
+            "type": "object",
+            "properties": {
+                "all": {
+                    "type": "boolean"
+                },
+                "user_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.MarkAttendanceResponse": {
+            "type": "object",
+            "properties": {
+                "already_completed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "completed": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "not_registered": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "models.Permission": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PointsDrift": {
+            "type": "object",
+            "properties": {
+                "actual": {
+                    "type": "integer"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "expected": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PointsHistoryEntry": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer"
+                },
+                "event_id": {
+                    "type": "integer"
+                },
+                "event_title": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "event_completed",
+                        "event_revoked",
+                        "event_points_changed",
+                        "manual",
+                        "reconcile"
+                    ]
+                },
+                "total_points": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PointsHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "total_points": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointsTransaction"
+                    }
+                }
+            }
+        },
+        "models.PointsReconcileReport": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "event_drift": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointsDrift"
+                    }
+                },
+                "total_drift": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PointsDrift"
+                    }
+                }
+            }
+        },
+        "models.PointsTransaction": {
             "type": "object",
             "properties": {
-                "display_name": {
+                "actor_id": {
+                    "type": "integer"
+                },
+                "comment": {
                     "type": "string"
                 },
-                "link_token": {
+                "created_at": {
                     "type": "string"
                 },
-                "link_token_ttl_sec": {
+                "delta": {
                     "type": "integer"
                 },
-                "status": {
-                    "type": "string"
+                "event_id": {
+                    "type": "integer"
                 },
-                "student_group": {
+                "event_title": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string",
+                    "enum": [
+                        "event_completed",
+                        "event_revoked",
+                        "event_points_changed",
+                        "manual",
+                        "reconcile"
+                    ]
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "models.AuthTokens": {
+        "models.ProfileResponse": {
             "type": "object",
             "properties": {
-                "access_token": {
+                "archived_at": {
+                    "description": "ArchivedAt - когда пользователь выпустился; выпускники не участвуют в лидерборде",
                     "type": "string"
                 },
-                "expires_at": {
+                "avatar": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "book_id": {
                     "type": "integer"
                 },
-                "refresh_token": {
+                "deletion_scheduled_at": {
+                    "description": "DeletionScheduledAt - когда аккаунт будет удален, если пользователь не отменит удаление",
                     "type": "string"
-                }
-            }
-        },
-        "models.CompleteUserEventRequest": {
-            "type": "object",
-            "properties": {
-                "event_id": {
+                },
+                "email": {
+                    "type": "string"
+                },
+                "email_verified": {
+                    "type": "boolean"
+                },
+                "institute": {
+                    "type": "string"
+                },
+                "institute_id": {
                     "type": "integer"
                 },
-                "user_id": {
+                "middle_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role_level": {
                     "type": "integer"
-                }
-            }
-        },
-        "models.CompletedEvent": {
-            "type": "object",
-            "properties": {
-                "completed_at": {
+                },
+                "student_group": {
                     "type": "string"
                 },
-                "event_id": {
+                "student_group_id": {
+                    "description": "место в структуре университета, 0 и пустая строка - не задано",
                     "type": "integer"
                 },
-                "user_id": {
+                "study": {
+                    "type": "string"
+                },
+                "study_id": {
+                    "type": "integer"
+                },
+                "surname": {
+                    "type": "string"
+                },
+                "total_points": {
                     "type": "integer"
                 }
             }
         },
-        "models.CompletedEventsFullResponse": {
+        "models.RefreshTokenRequest": {
             "type": "object",
             "properties": {
-                "events": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.UserCompletedEvent"
-                    }
+                "device_name": {
+                    "type": "string"
                 },
-                "stats": {
-                    "$ref": "#/definitions/models.CompletedEventsStats"
+                "refresh_token": {
+                    "type": "string"
                 }
             }
         },
-        "models.CompletedEventsStats": {
+        "models.RefreshTokenResponse": {
             "type": "object",
             "properties": {
-                "articles": {
-                    "type": "integer"
-                },
-                "hackathons": {
-                    "type": "integer"
-                },
-                "olympiads": {
-                    "type": "integer"
+                "auth": {
+                    "$ref": "#/definitions/models.AuthTokens"
                 },
-                "projects": {
+                "user_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.CreateEventRequest": {
+        "models.RegisterRequest": {
             "type": "object",
             "required": [
-                "title"
+                "token"
             ],
             "properties": {
-                "description": {
+                "device_name": {
                     "type": "string"
                 },
-                "event_date": {
+                "email": {
                     "type": "string"
                 },
-                "event_type_code": {
-                    "type": "integer"
-                },
-                "icon_url": {
+                "password": {
                     "type": "string"
                 },
-                "link": {
+                "token": {
                     "type": "string"
+                }
+            }
+        },
+        "models.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "auth": {
+                    "$ref": "#/definitions/models.AuthTokens"
                 },
-                "points": {
-                    "type": "integer"
-                },
-                "title": {
-                    "type": "string"
+                "user": {
+                    "$ref": "#/definitions/models.UserSubstructure"
                 }
             }
         },
-        "models.CreateEventResponse": {
+        "models.RejectClaimRequest": {
             "type": "object",
+            "required": [
+                "reason"
+            ],
             "properties": {
-                "created": {
+                "reason": {
                     "type": "string"
-                },
-                "description": {
+                }
+            }
+        },
+        "models.ResetPasswordRequest": {
+            "type": "object",
+            "required": [
+                "email"
+            ],
+            "properties": {
+                "email": {
                     "type": "string"
+                }
+            }
+        },
+        "models.RevokeSessionsResponse": {
+            "type": "object",
+            "properties": {
+                "revoked": {
+                    "type": "integer"
                 },
-                "event_date": {
+                "successful": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "code": {
                     "type": "string"
                 },
-                "event_id": {
+                "id": {
                     "type": "integer"
                 },
-                "event_type_code": {
+                "level": {
                     "type": "integer"
                 },
-                "icon_url": {
+                "name": {
                     "type": "string"
                 },
-                "link": {
-                    "type": "string"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RolloverGraduation": {
+            "type": "object",
+            "properties": {
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "points": {
+                "students": {
                     "type": "integer"
                 },
-                "title": {
-                    "type": "string"
+                "users": {
+                    "type": "integer"
                 }
             }
         },
-        "models.CreateSuggestRequest": {
+        "models.RolloverMappingResult": {
             "type": "object",
-            "required": [
-                "event_id"
-            ],
             "properties": {
-                "event_id": {
+                "created_group": {
+                    "description": "группы To не было, она создана на том же направлении, что и From",
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "students": {
                     "type": "integer"
                 },
-                "expires_at": {
+                "to": {
+                    "type": "string"
+                },
+                "users": {
                     "type": "integer"
                 }
             }
         },
-        "models.DeleteCompletedEventResponse": {
+        "models.RolloverReport": {
             "type": "object",
             "properties": {
-                "event_id": {
-                    "type": "integer"
+                "applied": {
+                    "type": "boolean"
                 },
-                "successful": {
+                "dry_run": {
                     "type": "boolean"
                 },
-                "user_id": {
-                    "type": "integer"
+                "graduation": {
+                    "$ref": "#/definitions/models.RolloverGraduation"
+                },
+                "mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RolloverMappingResult"
+                    }
                 }
             }
         },
-        "models.DeleteEventResponse": {
+        "models.RolloverRequest": {
             "type": "object",
             "properties": {
-                "event_id": {
-                    "type": "integer"
+                "graduating_groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "successful": {
-                    "type": "boolean"
+                "mappings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GroupMapping"
+                    }
                 }
             }
         },
-        "models.DeleteUserResponse": {
+        "models.RosterImportReport": {
             "type": "object",
             "properties": {
-                "successful": {
+                "applied": {
                     "type": "boolean"
                 },
-                "user_id": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RosterRow"
+                    }
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.RosterImportSummary"
+                }
+            }
+        },
+        "models.RosterImportSummary": {
+            "type": "object",
+            "properties": {
+                "conflicts": {
+                    "type": "integer"
+                },
+                "inserted": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unchanged": {
+                    "type": "integer"
+                },
+                "updated": {
                     "type": "integer"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.RosterRow": {
             "type": "object",
             "properties": {
-                "error": {
-                    "type": "string"
+                "book_id": {
+                    "type": "integer"
+                },
+                "changes": {
+                    "description": "поля, которые изменятся при обновлении",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "description": "номер строки в файле, начиная с 1 (заголовок - строка 1)",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "insert",
+                        "update",
+                        "unchanged",
+                        "conflict",
+                        "invalid"
+                    ]
                 },
-                "message": {
-                    "type": "string"
+                "student": {
+                    "$ref": "#/definitions/models.Student"
                 }
             }
         },
-        "models.Event": {
+        "models.Session": {
             "type": "object",
             "properties": {
-                "created": {
+                "created_at": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "current": {
+                    "type": "boolean"
                 },
-                "event_date": {
+                "device_name": {
                     "type": "string"
                 },
-                "event_id": {
-                    "type": "integer"
+                "expires_at": {
+                    "type": "string"
                 },
-                "event_type_code": {
+                "id": {
                     "type": "integer"
                 },
-                "icon_url": {
+                "ip": {
                     "type": "string"
                 },
-                "link": {
+                "last_used_at": {
                     "type": "string"
                 },
-                "points": {
-                    "type": "integer"
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SetCheckinSettingsRequest": {
+            "type": "object",
+            "required": [
+                "closes_at",
+                "opens_at"
+            ],
+            "properties": {
+                "closes_at": {
+                    "type": "string"
                 },
-                "title": {
+                "opens_at": {
                     "type": "string"
                 }
             }
         },
-        "models.LoginRequest": {
+        "models.SetNewPasswordRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "new_password",
+                "token"
             ],
             "properties": {
-                "email": {
+                "new_password": {
                     "type": "string"
                 },
-                "password": {
+                "token": {
                     "type": "string"
                 }
             }
         },
-        "models.LoginResponse": {
+        "models.SetRolePermissionsRequest": {
             "type": "object",
             "properties": {
-                "auth": {
-                    "$ref": "#/definitions/models.AuthTokens"
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.StartImpersonationRequest": {
+            "type": "object",
+            "required": [
+                "reason"
+            ],
+            "properties": {
+                "read_only": {
+                    "description": "ReadOnly - по умолчанию true; false разрешает изменения от имени пользователя",
+                    "type": "boolean"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserSubstructure"
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "models.ProfileResponse": {
+        "models.Student": {
             "type": "object",
             "properties": {
-                "avatar": {
+                "archived_at": {
+                    "description": "ArchivedAt - когда студент выпустился, nil у действующих студентов",
                     "type": "string"
                 },
                 "birth_date": {
@@ -1565,8 +8353,8 @@ const docTemplate = `{
                 "book_id": {
                     "type": "integer"
                 },
-                "email": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
                 "middle_name": {
                     "type": "string"
@@ -1574,127 +8362,133 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
-                "role_level": {
-                    "type": "integer"
-                },
                 "student_group": {
                     "type": "string"
                 },
+                "student_group_id": {
+                    "description": "StudentGroupId - ссылка на student_groups, StudentGroup - название группы",
+                    "type": "integer"
+                },
                 "surname": {
                     "type": "string"
-                },
-                "total_points": {
-                    "type": "integer"
                 }
             }
         },
-        "models.RefreshTokenRequest": {
+        "models.StudentGroup": {
             "type": "object",
             "properties": {
-                "refresh_token": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
-                }
-            }
-        },
-        "models.RefreshTokenResponse": {
-            "type": "object",
-            "properties": {
-                "auth": {
-                    "$ref": "#/definitions/models.AuthTokens"
                 },
-                "user_id": {
+                "study_id": {
                     "type": "integer"
                 }
             }
         },
-        "models.RegisterRequest": {
+        "models.Study": {
             "type": "object",
-            "required": [
-                "token"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StudentGroup"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "id": {
+                    "type": "integer"
                 },
-                "token": {
+                "institute_id": {
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "models.RegisterResponse": {
+        "models.SuccessResponse": {
             "type": "object",
             "properties": {
-                "auth": {
-                    "$ref": "#/definitions/models.AuthTokens"
+                "message": {
+                    "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.UserSubstructure"
+                "successful": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.ResetPasswordRequest": {
+        "models.TwoFactorCodeRequest": {
             "type": "object",
             "required": [
-                "email"
+                "code"
             ],
             "properties": {
-                "email": {
+                "code": {
                     "type": "string"
                 }
             }
         },
-        "models.SetNewPasswordRequest": {
+        "models.TwoFactorDisableRequest": {
             "type": "object",
             "required": [
-                "new_password",
-                "token"
+                "code",
+                "password"
             ],
             "properties": {
-                "new_password": {
+                "code": {
                     "type": "string"
                 },
-                "token": {
+                "password": {
                     "type": "string"
                 }
             }
         },
-        "models.Student": {
+        "models.TwoFactorRecoveryCodesResponse": {
             "type": "object",
             "properties": {
-                "birth_date": {
-                    "type": "string"
-                },
-                "book_id": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "integer"
-                },
-                "middle_name": {
+                "recovery_codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.TwoFactorSetupResponse": {
+            "type": "object",
+            "properties": {
+                "provisioning_uri": {
                     "type": "string"
                 },
-                "name": {
+                "secret": {
                     "type": "string"
+                }
+            }
+        },
+        "models.TwoFactorStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
                 },
-                "student_group": {
-                    "type": "string"
+                "recovery_codes_left": {
+                    "type": "integer"
                 },
-                "surname": {
-                    "type": "string"
+                "required": {
+                    "type": "boolean"
                 }
             }
         },
-        "models.SuccessResponse": {
+        "models.UnlockRequest": {
             "type": "object",
+            "required": [
+                "key"
+            ],
             "properties": {
-                "message": {
+                "key": {
                     "type": "string"
-                },
-                "successful": {
-                    "type": "boolean"
                 }
             }
         },
@@ -1707,6 +8501,10 @@ const docTemplate = `{
                 "new_data": {
                     "type": "object",
                     "properties": {
+                        "capacity": {
+                            "description": "Capacity 0 снимает ограничение мест. При увеличении мест ожидающие записываются автоматически",
+                            "type": "integer"
+                        },
                         "description": {
                             "type": "string"
                         },
@@ -1725,6 +8523,12 @@ const docTemplate = `{
                         "points": {
                             "type": "integer"
                         },
+                        "registration_closes_at": {
+                            "type": "string"
+                        },
+                        "registration_opens_at": {
+                            "type": "string"
+                        },
                         "title": {
                             "type": "string"
                         }
@@ -1732,6 +8536,78 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateEventTypeRequest": {
+            "type": "object",
+            "properties": {
+                "color": {
+                    "type": "string",
+                    "example": "#3F51B5"
+                },
+                "icon_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateGroupRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "study_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.UpdateProfileRequest": {
+            "type": "object",
+            "required": [
+                "avatar"
+            ],
+            "properties": {
+                "avatar": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateStudentRequest": {
+            "type": "object",
+            "properties": {
+                "birth_date": {
+                    "type": "string",
+                    "example": "2003-02-01"
+                },
+                "book_id": {
+                    "type": "integer"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "student_group": {
+                    "type": "string"
+                },
+                "surname": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateStudyRequest": {
+            "type": "object",
+            "properties": {
+                "institute_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateUserRequest": {
             "type": "object",
             "properties": {
@@ -1757,6 +8633,27 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UploadedFile": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "type": "string",
+                    "example": "application/pdf"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "certificate.pdf"
+                },
+                "size": {
+                    "type": "integer",
+                    "example": 183204
+                },
+                "url": {
+                    "type": "string",
+                    "example": "/uploads/evidence/3f2a9c1e.pdf"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -1772,6 +8669,9 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "type": "boolean"
+                },
                 "id": {
                     "type": "integer"
                 },
@@ -1793,6 +8693,9 @@ const docTemplate = `{
                 "student_group": {
                     "type": "string"
                 },
+                "student_group_id": {
+                    "type": "integer"
+                },
                 "surname": {
                     "type": "string"
                 }
@@ -1845,6 +8748,9 @@ const docTemplate = `{
                 "email": {
                     "type": "string"
                 },
+                "email_verified": {
+                    "type": "boolean"
+                },
                 "first_name": {
                     "type": "string"
                 },
@@ -1883,6 +8789,10 @@ const docTemplate = `{
                 "student_group": {
                     "type": "string"
                 },
+                "student_group_id": {
+                    "description": "StudentGroupId - группа по id; если задано только название StudentGroup, группа ищется по нему",
+                    "type": "integer"
+                },
                 "surname": {
                     "type": "string"
                 }
@@ -1891,6 +8801,9 @@ const docTemplate = `{
         "models.UserWithPoints": {
             "type": "object",
             "properties": {
+                "avatar": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
@@ -1907,6 +8820,17 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "models.VerifyEmailRequest": {
+            "type": "object",
+            "required": [
+                "token"
+            ],
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,91 @@
+package audit
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuditLog Журнал действий администраторов
+// @Summary      Журнал действий администраторов
+// @Description  Возвращает записи журнала: кто, когда и что изменил, с состоянием объекта до и после изменения.
+// @Description  Например, начисления баллов студенту: action=completed_event.add, target_type=user, target_id={user_id}.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        actor_id       query   int     false  "Кто выполнил действие"
+// @Param        action         query   string  false  "Действие (event.update, completed_event.add, user.delete, ...)"
+// @Param        target_type    query   string  false  "Тип объекта (event, user, role, auth_lock)"
+// @Param        target_id      query   string  false  "Идентификатор объекта"
+// @Param        from           query   string  false  "Начало периода (RFC3339)"
+// @Param        to             query   string  false  "Конец периода, не включая (RFC3339)"
+// @Param        limit          query   int     false  "Максимальное количество записей"  default(50)
+// @Param        offset         query   int     false  "Сколько записей пропустить"  default(0)
+// @Success      200  {array}   models.AuditEntry     "Записи журнала, новые первыми"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные параметры фильтра"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении журнала"
+// @Router       /admin/audit [get]
+func GetAuditLog(service *services.AuditService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		filter := models.AuditFilter{
+			Action:     c.Query("action"),
+			TargetType: c.Query("target_type"),
+			TargetId:   c.Query("target_id"),
+		}
+
+		var err error
+		if v := c.Query("actor_id"); v != "" {
+			if filter.ActorId, err = strconv.ParseInt(v, 10, 64); err != nil {
+				badFilter(c, err, "Некорректный actor_id")
+				return
+			}
+		}
+		if v := c.Query("from"); v != "" {
+			from, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				badFilter(c, err, "Некорректная дата from, ожидается RFC3339")
+				return
+			}
+			filter.From = &from
+		}
+		if v := c.Query("to"); v != "" {
+			to, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				badFilter(c, err, "Некорректная дата to, ожидается RFC3339")
+				return
+			}
+			filter.To = &to
+		}
+		filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+		filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		entries, err := service.GetEntries(ctx, filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении журнала",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, entries)
+	}
+}
+
+func badFilter(c *gin.Context, err error, message string) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:   err.Error(),
+		Message: message,
+	})
+}
@@ -23,6 +23,7 @@ import (
 // @Failure      400  {object}  models.ErrorResponse "Некорректный запрос — ошибка парсинга JSON"
 // @Failure      404  {object}  models.ErrorResponse "Студенческий не найден в базе"
 // @Failure      409  {object}  models.ErrorResponse "Пользователь с таким номером уже зарегистрирован"
+// @Failure      429  {object}  models.ErrorResponse "Слишком много запросов с этого IP или для этого номера"
 // @Failure      500  {object}  models.ErrorResponse "Ошибка при работе с базой данных"
 // @Failure      500  {object}  models.ErrorResponse "Ошибка при генерации или сохранении токена"
 // @Router       /auth/check [post]
@@ -43,10 +44,14 @@ func CheckStudent(service *services.StudentsService) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		resp, err := service.CheckStudent(ctx, body.BookId)
+		resp, err := service.CheckStudent(ctx, body.BookId, c.ClientIP())
 
 		// Обработка ошибок
 		if err != nil {
+			if respondThrottled(c, err) {
+				return
+			}
+
 			switch {
 			case errors.Is(err, services.ErrStudentByBookIdNotFound):
 				c.JSON(http.StatusNotFound, models.ErrorResponse{
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetAuthLocks Получение действующих блокировок входа
+// @Summary      Список блокировок
+// @Description  Возвращает IP адреса и аккаунты, заблокированные из-за большого числа попыток входа,
+// @Description  проверки студенческого или сброса пароля.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        limit          query   int     false  "Максимальное количество блокировок в выдаче"  default(50)
+// @Success      200  {array}   models.AuthLock       "Список блокировок"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении блокировок"
+// @Router       /admin/auth_locks [get]
+func GetAuthLocks(service *services.AuthThrottleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		limitStr := c.DefaultQuery("limit", "50")
+		limit, _ := strconv.Atoi(limitStr)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		locks, err := service.GetActiveLocks(ctx, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении блокировок",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, locks)
+	}
+}
+
+// Unlock Снятие блокировки
+// @Summary      Разблокировать аккаунт или IP
+// @Description  Снимает блокировки и сбрасывает счетчики попыток для почты, номера студенческого или IP адреса.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.UnlockRequest  true  "Почта, номер студенческого или IP"
+// @Success      200  {object}  models.SuccessResponse  "Блокировка снята"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный JSON"
+// @Failure      404  {object}  models.ErrorResponse    "Блокировок для этого ключа нет"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при снятии блокировки"
+// @Router       /admin/unlock [post]
+func Unlock(service *services.AuthThrottleService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.UnlockRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		err := service.Unlock(ctx, payload.Sub, body.Key)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrLockNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Блокировок для этого ключа нет",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при снятии блокировки",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Блокировка снята",
+		})
+	}
+}
@@ -14,13 +14,16 @@ import (
 // RefreshToken Обновление access и refresh токенов
 // @Summary      Обновление access и refresh токенов
 // @Description  Принимает refresh токен, проверяет его валидность и возвращает новую пару токенов.
+// @Description  Каждый refresh токен одноразовый. Повторное предъявление уже обменянного токена
+// @Description  отзывает всю сессию и возвращает 401 с Error = "refresh_token_reused".
 // @Tags         auth
 // @Accept       json
 // @Produce      json
 // @Param        input  body  models.RefreshTokenRequest  true  "Refresh токен"
 // @Success      200  {object}  models.RefreshTokenResponse  "Успешное обновление токенов"
 // @Failure      400  {object}  models.ErrorResponse          "Некорректный JSON"
-// @Failure      401  {object}  models.ErrorResponse          "Невалидный или просроченный refresh токен"
+// @Failure      401  {object}  models.ErrorResponse          "Refresh токен уже был использован, сессия отозвана"
+// @Failure      404  {object}  models.ErrorResponse          "Невалидный или просроченный refresh токен"
 // @Failure      500  {object}  models.ErrorResponse          "Ошибка при обновлении/генерации токенов"
 // @Router       /auth/refresh [post]
 func RefreshToken(service *services.RefreshTokensService) gin.HandlerFunc {
@@ -40,7 +43,11 @@ func RefreshToken(service *services.RefreshTokensService) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 		defer cancel()
 
-		resp, err := service.RefreshToken(ctx, body.RefreshToken)
+		resp, err := service.RefreshToken(ctx, body.RefreshToken, models.SessionMeta{
+			DeviceName: body.DeviceName,
+			UserAgent:  c.Request.UserAgent(),
+			IP:         c.ClientIP(),
+		})
 
 		// Обработка ошибок
 		if err != nil {
@@ -50,6 +57,11 @@ func RefreshToken(service *services.RefreshTokensService) gin.HandlerFunc {
 					Error:   services.ErrNoTokensFound.Error(),
 					Message: "Refresh token не найден или просрочен",
 				})
+			case errors.Is(err, services.ErrRefreshTokenReused):
+				c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+					Error:   "refresh_token_reused",
+					Message: "Refresh token уже был использован. Сессия завершена, войдите заново",
+				})
 			case errors.Is(err, services.ErrNoRowsAffected):
 				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 					Error:   services.ErrNoRowsAffected.Error(),
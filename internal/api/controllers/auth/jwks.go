@@ -0,0 +1,23 @@
+package auth
+
+import (
+	"bobri/pkg/helpers"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JWKS Публичные ключи для проверки access токенов
+// @Summary      Набор публичных ключей (JWKS)
+// @Description  Возвращает публичные ключи, которыми можно проверить подпись access токенов.
+// @Description  Ключ выбирается по заголовку kid токена. Во время ротации в наборе присутствуют и старые, и новые ключи.
+// @Tags         auth
+// @Produce      json
+// @Success      200  {object}  helpers.JWKSet  "Набор публичных ключей"
+// @Router       /.well-known/jwks.json [get]
+func JWKS(accessJwtMaker *helpers.JWTMaker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(http.StatusOK, accessJwtMaker.JWKS())
+	}
+}
@@ -13,6 +13,8 @@ import (
 // Login Авторизация пользователя
 // @Summary      Авторизация пользователя
 // @Description  Проверяет почту и пароль пользователя. При успешной авторизации выдает пару access и refresh токенов.
+// @Description  Если у администратора включена 2FA, токены не выдаются: ответ содержит mfa_required = true и mfa_token,
+// @Description  который вместе с кодом нужно отправить на /auth/login/mfa.
 // @Tags         auth
 // @Accept       json
 // @Produce      json
@@ -21,6 +23,7 @@ import (
 // @Failure      400  {object}  models.ErrorResponse  "Некорректный запрос — ошибка парсинга JSON"
 // @Failure      401  {object}  models.ErrorResponse  "Неправильный пароль"
 // @Failure      404  {object}  models.ErrorResponse  "Пользователь с такой почтой не найден"
+// @Failure      429  {object}  models.ErrorResponse  "Слишком много неудачных попыток: Error = too_many_attempts (по IP) или account_locked (аккаунт заблокирован)"
 // @Failure      500  {object}  models.ErrorResponse  "Ошибка сервера при работе с базой данных или токенами"
 // @Router       /auth/login [post]
 func Login(loginService *services.LoginService) gin.HandlerFunc {
@@ -40,8 +43,15 @@ func Login(loginService *services.LoginService) gin.HandlerFunc {
 		defer cancel()
 
 		// Вызываем сервис логина
-		resp, err := loginService.Login(ctx, req.Email, req.Password)
+		resp, err := loginService.Login(ctx, req.Email, req.Password, models.SessionMeta{
+			DeviceName: req.DeviceName,
+			UserAgent:  c.Request.UserAgent(),
+			IP:         c.ClientIP(),
+		})
 		if err != nil {
+			if respondThrottled(c, err) {
+				return
+			}
 
 			switch {
 			case errors.Is(err, services.ErrUserNotFound):
@@ -0,0 +1,76 @@
+package auth
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoginMFA Подтверждение входа кодом двухфакторной аутентификации
+// @Summary      Второй шаг входа (2FA)
+// @Description  Принимает mfa_token, выданный /auth/login с mfa_required = true, и код из приложения-аутентификатора
+// @Description  или одноразовый код восстановления. При успехе выдает пару access и refresh токенов.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body  models.LoginMFARequest  true  "Токен челленджа и код"
+// @Success      200  {object}  models.LoginResponse  "Успешная авторизация — возвращается пользователь и пара токенов"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный запрос — ошибка парсинга JSON"
+// @Failure      401  {object}  models.ErrorResponse  "Неверный код или челлендж истек"
+// @Failure      429  {object}  models.ErrorResponse  "Слишком много неверных кодов: Error = too_many_attempts (по IP) или account_locked (аккаунт заблокирован)"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка сервера при работе с базой данных или токенами"
+// @Router       /auth/login/mfa [post]
+func LoginMFA(loginService *services.LoginService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var req models.LoginMFARequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный формат запроса",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		resp, err := loginService.CompleteMFA(ctx, req.MFAToken, req.Code, models.SessionMeta{
+			DeviceName: req.DeviceName,
+			UserAgent:  c.Request.UserAgent(),
+			IP:         c.ClientIP(),
+		})
+		if err != nil {
+			if respondThrottled(c, err) {
+				return
+			}
+
+			switch {
+			case errors.Is(err, services.ErrInvalidMFACode):
+				c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Неверный код подтверждения",
+				})
+			case errors.Is(err, services.ErrInvalidMFAChallenge), errors.Is(err, services.ErrMFANotEnabled):
+				c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Сессия входа истекла, войдите заново",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Внутренняя ошибка сервера",
+				})
+			}
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Logout Выход из текущей сессии
+// @Summary      Выход из аккаунта
+// @Description  Завершает сессию, из которой выдан access токен: удаляет её refresh токены.
+// @Description  Access токен этой сессии перестает приниматься сразу, не дожидаясь истечения срока.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {object}  models.SuccessResponse  "Сессия завершена"
+// @Failure      401  {object}  models.ErrorResponse    "Невалидный или отозванный access токен"
+// @Failure      404  {object}  models.ErrorResponse    "Сессия уже завершена"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при завершении сессии"
+// @Router       /auth/logout [post]
+func Logout(service *services.SessionsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		err := service.RevokeSession(ctx, payload.Sub, payload.Sid)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrSessionNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Сессия уже завершена",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при завершении сессии",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Вы вышли из аккаунта",
+		})
+	}
+}
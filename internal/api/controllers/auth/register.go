@@ -60,7 +60,11 @@ func RegisterByToken(service *services.RegisterService) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		resp, err := service.RegisterUser(ctx, body.Email, body.Password, body.Token)
+		resp, err := service.RegisterUser(ctx, body.Email, body.Password, body.Token, models.SessionMeta{
+			DeviceName: body.DeviceName,
+			UserAgent:  c.Request.UserAgent(),
+			IP:         c.ClientIP(),
+		})
 		if err != nil {
 
 			switch {
@@ -19,6 +19,9 @@ import (
 // @Param        input  body  models.ResetPasswordRequest  true  "Почта пользователя"
 // @Success      200  {object}  map[string]string  "Инструкция отправлена на почту"
 // @Failure      400  {object}  models.ErrorResponse  "Некорректный JSON"
+// @Failure      403  {object}  models.ErrorResponse  "Почта пользователя не подтверждена"
+// @Failure      404  {object}  models.ErrorResponse  "Пользователь с такой почтой не найден"
+// @Failure      429  {object}  models.ErrorResponse  "Слишком много запросов с этого IP или для этой почты"
 // @Failure      500  {object}  models.ErrorResponse  "Ошибка при поиске пользователя или отправке письма"
 // @Router       /auth/reset_password [post]
 func ResetPassword(service *services.ResetPasswordService) gin.HandlerFunc {
@@ -35,14 +38,23 @@ func ResetPassword(service *services.ResetPasswordService) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err := service.ResetPassword(ctx, body.Email)
+		err := service.ResetPassword(ctx, body.Email, c.ClientIP())
 		if err != nil {
+			if respondThrottled(c, err) {
+				return
+			}
+
 			switch {
 			case errors.Is(err, services.ErrUserNotFound):
 				c.JSON(404, models.ErrorResponse{
 					Error:   err.Error(),
 					Message: "Пользователь с такой почтой не найден",
 				})
+			case errors.Is(err, services.ErrEmailNotVerified):
+				c.JSON(403, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Почта не подтверждена, сброс пароля на нее недоступен",
+				})
 			default:
 				c.JSON(500, models.ErrorResponse{
 					Error:   err.Error(),
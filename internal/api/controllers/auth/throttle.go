@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondThrottled отвечает 429, если err - ошибка превышения числа попыток.
+// Возвращает true, если ответ уже отправлен.
+func respondThrottled(c *gin.Context, err error) bool {
+	var throttleErr *services.ThrottleError
+	if !errors.As(err, &throttleErr) {
+		return false
+	}
+
+	retryAfter := int64(math.Ceil(time.Until(throttleErr.LockedUntil).Seconds()))
+	if retryAfter < 1 {
+		retryAfter = 1
+	}
+	c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+
+	if errors.Is(err, services.ErrAccountLocked) {
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+			Error:   "account_locked",
+			Message: "Аккаунт временно заблокирован из-за большого числа попыток. Повторите позже или обратитесь к администратору",
+		})
+		return true
+	}
+
+	c.JSON(http.StatusTooManyRequests, models.ErrorResponse{
+		Error:   "too_many_attempts",
+		Message: "Слишком много попыток. Повторите позже",
+	})
+	return true
+}
@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyEmail Подтверждение почты
+// @Summary      Подтверждение почты
+// @Description  Подтверждает владение почтой по токену из письма, отправленного при регистрации или через /auth/resend_verification.
+// @Description  Токен действителен 24 часа и только для той почты, на которую был отправлен.
+// @Tags         auth
+// @Accept       json
+// @Produce      json
+// @Param        input  body  models.VerifyEmailRequest  true  "Токен из письма"
+// @Success      200  {object}  models.SuccessResponse  "Почта подтверждена"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный JSON"
+// @Failure      401  {object}  models.ErrorResponse  "Невалидный или истекший токен"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при подтверждении почты"
+// @Router       /auth/verify_email [post]
+func VerifyEmail(service *services.EmailVerificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.VerifyEmailRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.VerifyEmail(ctx, body.Token); err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidVerificationToken):
+				c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Невалидный или истёкший токен",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при подтверждении почты",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Почта подтверждена",
+		})
+	}
+}
+
+// ResendVerification Повторная отправка письма подтверждения
+// @Summary      Повторная отправка письма подтверждения
+// @Description  Отправляет новый токен подтверждения на текущую почту пользователя. Предыдущий токен перестает действовать.
+// @Tags         auth
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {object}  models.SuccessResponse  "Письмо отправлено"
+// @Failure      401  {object}  models.ErrorResponse  "Нет токена или токен невалиден"
+// @Failure      404  {object}  models.ErrorResponse  "Пользователь не найден"
+// @Failure      409  {object}  models.ErrorResponse  "Почта уже подтверждена"
+// @Failure      429  {object}  models.ErrorResponse  "Слишком много запросов"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при отправке письма"
+// @Router       /auth/resend_verification [post]
+func ResendVerification(service *services.EmailVerificationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+		defer cancel()
+
+		if err := service.ResendVerification(ctx, payload.Sub, c.ClientIP()); err != nil {
+			if respondThrottled(c, err) {
+				return
+			}
+
+			switch {
+			case errors.Is(err, services.ErrUserNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Пользователь не найден",
+				})
+			case errors.Is(err, services.ErrEmailAlreadyVerified):
+				c.JSON(http.StatusConflict, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Почта уже подтверждена",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при отправке письма",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Письмо с подтверждением отправлено",
+		})
+	}
+}
@@ -0,0 +1,323 @@
+package claims
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondClaimError отвечает на ошибки работы с заявками.
+func respondClaimError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrClaimNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Заявка не найдена",
+		})
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие не найдено",
+		})
+	case errors.Is(err, services.ErrClaimNotPending):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Заявка уже рассмотрена или отозвана",
+		})
+	case errors.Is(err, services.ErrClaimAlreadyPending):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Заявка на это событие уже ждет рассмотрения",
+		})
+	case errors.Is(err, services.ErrAlreadyCompleted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие уже отмечено выполненным",
+		})
+	case errors.Is(err, services.ErrInvalidReference):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Студент или событие заявки больше не существуют",
+		})
+	case errors.Is(err, services.ErrInvalidClaim):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректная заявка",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при работе с заявками",
+		})
+	}
+}
+
+// parseClaimId читает id заявки из пути, при ошибке отвечает 400.
+func parseClaimId(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный ID заявки",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// CreateClaim Подать заявку на баллы
+// @Summary      Подать заявку на баллы
+// @Description  Студент сообщает о выполненном мероприятии: комментарий и до 10 подтверждений (ссылки http/https или файлы, загруженные через /me/uploads).
+// @Description  Заявка попадает в очередь модерации, баллы начисляются после одобрения.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CreateClaimRequest  true  "Заявка"
+// @Success      201  {object}  models.Claim          "Созданная заявка"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректная заявка"
+// @Failure      404  {object}  models.ErrorResponse  "Событие не найдено, удалено или еще не опубликовано"
+// @Failure      409  {object}  models.ErrorResponse  "Событие уже выполнено или заявка уже ждет рассмотрения"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при создании заявки"
+// @Router       /me/claims [post]
+func CreateClaim(service *services.ClaimsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.CreateClaimRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		claim, err := service.Submit(ctx, payload.Sub, body)
+		if err != nil {
+			respondClaimError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, claim)
+	}
+}
+
+// GetMyClaims Мои заявки
+// @Summary      Мои заявки
+// @Description  Возвращает заявки текущего пользователя со статусами и причинами отклонения, новые первыми.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {array}   models.Claim          "Заявки"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении заявок"
+// @Router       /me/claims [get]
+func GetMyClaims(service *services.ClaimsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		claims, err := service.GetMyClaims(ctx, payload.Sub)
+		if err != nil {
+			respondClaimError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, claims)
+	}
+}
+
+// WithdrawClaim Отозвать заявку
+// @Summary      Отозвать заявку
+// @Description  Отзывает заявку, пока она не рассмотрена.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID заявки"
+// @Success      204  "Заявка отозвана"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный ID заявки"
+// @Failure      404  {object}  models.ErrorResponse  "Заявка не найдена"
+// @Failure      409  {object}  models.ErrorResponse  "Заявка уже рассмотрена"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при отзыве заявки"
+// @Router       /me/claims/{id} [delete]
+func WithdrawClaim(service *services.ClaimsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseClaimId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.Withdraw(ctx, payload.Sub, id); err != nil {
+			respondClaimError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetClaims Очередь заявок
+// @Summary      Очередь заявок
+// @Description  Возвращает заявки студентов с подтверждениями. По умолчанию - ждущие рассмотрения, старые первыми.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        status         query   string  false  "Статус заявки"  Enums(pending, approved, rejected, withdrawn)  default(pending)
+// @Param        user_id        query   int     false  "Заявки одного студента"
+// @Param        limit          query   int     false  "Максимальное количество записей"  default(50)
+// @Param        offset         query   int     false  "Сколько записей пропустить"  default(0)
+// @Success      200  {array}   models.Claim          "Заявки"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные параметры фильтра"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении заявок"
+// @Router       /admin/claims [get]
+func GetClaims(service *services.ClaimsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		filter := models.ClaimsFilter{Status: c.Query("status")}
+		switch filter.Status {
+		case "", models.ClaimStatusPending, models.ClaimStatusApproved, models.ClaimStatusRejected, models.ClaimStatusWithdrawn:
+		default:
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unknown status " + filter.Status,
+				Message: "Некорректный статус заявки",
+			})
+			return
+		}
+
+		if v := c.Query("user_id"); v != "" {
+			userId, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Некорректный user_id",
+				})
+				return
+			}
+			filter.UserId = userId
+		}
+		filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "50"))
+		filter.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		claims, err := service.GetClaims(ctx, filter)
+		if err != nil {
+			respondClaimError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, claims)
+	}
+}
+
+// ApproveClaim Одобрить заявку
+// @Summary      Одобрить заявку
+// @Description  Одобряет заявку: событие отмечается выполненным у студента и начисляются баллы.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID заявки"
+// @Success      200  {object}  models.Claim          "Одобренная заявка"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный ID заявки"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Заявка не найдена или событие удалено"
+// @Failure      409  {object}  models.ErrorResponse  "Заявка уже рассмотрена или событие уже выполнено"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при одобрении заявки"
+// @Router       /admin/claims/{id}/approve [post]
+func ApproveClaim(service *services.ClaimsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseClaimId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		claim, err := service.Approve(ctx, payload.Sub, id)
+		if err != nil {
+			respondClaimError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, claim)
+	}
+}
+
+// RejectClaim Отклонить заявку
+// @Summary      Отклонить заявку
+// @Description  Отклоняет заявку с указанием причины, которую студент увидит в /me/claims.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        id             path    int                        true  "ID заявки"
+// @Param        input          body    models.RejectClaimRequest  true  "Причина отклонения"
+// @Success      200  {object}  models.Claim          "Отклоненная заявка"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный запрос"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Заявка не найдена"
+// @Failure      409  {object}  models.ErrorResponse  "Заявка уже рассмотрена"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при отклонении заявки"
+// @Router       /admin/claims/{id}/reject [post]
+func RejectClaim(service *services.ClaimsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseClaimId(c)
+		if !ok {
+			return
+		}
+
+		var body models.RejectClaimRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		claim, err := service.Reject(ctx, payload.Sub, id, body.Reason)
+		if err != nil {
+			respondClaimError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, claim)
+	}
+}
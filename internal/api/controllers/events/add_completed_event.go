@@ -13,6 +13,7 @@ import (
 // AddCompletedEvent  Отметить событие как выполненное пользователем
 // @Summary      Отметить выполнение события
 // @Description  Добавляет запись о выполнении события конкретным пользователем. Требует прав администратора.
+// @Description  Удаленные события и черновики засчитать нельзя.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -21,6 +22,7 @@ import (
 // @Success      200  {object}  models.SuccessResponse                   "Событие отмечено как выполненное"
 // @Failure      400  {object}  models.ErrorResponse                     "Некорректные данные или пользователь/событие не существуют"
 // @Failure      401  {object}  models.ErrorResponse                     "Нет прав доступа"
+// @Failure      404  {object}  models.ErrorResponse                     "Событие не найдено, удалено или еще не опубликовано"
 // @Failure      409  {object}  models.ErrorResponse                     "Событие уже было отмечено ранее"
 // @Failure      500  {object}  models.ErrorResponse                     "Ошибка сервера при добавлении записи"
 // @Router       /admin/add_completed_event [post]
@@ -36,14 +38,15 @@ func AddCompletedEvent(service *services.CompletedEventsService) gin.HandlerFunc
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err := service.AddCompletedEvent(ctx, body.UserId, body.EventId)
+		err := service.AddCompletedEvent(ctx, payload.Sub, body.UserId, body.EventId)
 		if err != nil {
 
 			switch {
-			// TODO SQLSTATE 23505
 			case errors.Is(err, services.ErrInvalidReference):
 				c.JSON(400, models.ErrorResponse{
 					Error:   err.Error(),
@@ -51,6 +54,13 @@ func AddCompletedEvent(service *services.CompletedEventsService) gin.HandlerFunc
 				})
 				return
 
+			case errors.Is(err, services.ErrEventNotFound):
+				c.JSON(404, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Событие не найдено",
+				})
+				return
+
 			case errors.Is(err, services.ErrAlreadyCompleted):
 				c.JSON(409, models.ErrorResponse{
 					Error:   err.Error(),
@@ -0,0 +1,114 @@
+package events
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetCatalog Каталог событий
+// @Summary      Каталог событий
+// @Description  Возвращает предстоящие (по возрастанию даты) или прошедшие (начиная с последних) события.
+// @Description  У каждого события указано, засчитано ли оно текущему пользователю, и число участников.
+// @Description  Следующая страница запрашивается с cursor из next_cursor и тем же when.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization    header  string  true   "Bearer токен" default(Bearer )
+// @Param        when             query   string  false  "Какие события показать" Enums(upcoming, past, all) default(upcoming)
+// @Param        q                query   string  false  "Поисковый запрос"
+// @Param        event_type_code  query   int     false  "Тип мероприятия"
+// @Param        cursor           query   string  false  "Курсор следующей страницы"
+// @Param        limit            query   int     false  "Размер страницы (до 100)" default(20)
+// @Success      200  {object}  models.EventCatalogResponse  "Страница каталога"
+// @Failure      400  {object}  models.ErrorResponse         "Некорректные параметры"
+// @Failure      500  {object}  models.ErrorResponse         "Ошибка при получении событий"
+// @Router       /events [get]
+func GetCatalog(eventService *services.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		filter := models.EventSearchFilter{
+			Query:  c.Query("q"),
+			Cursor: c.Query("cursor"),
+		}
+
+		var err error
+		if filter.EventTypeCode, err = optionalInt(c, "event_type_code"); err != nil {
+			badSearch(c, err, "Некорректный event_type_code")
+			return
+		}
+		filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultEventSearchLimit)))
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		result, err := eventService.GetCatalog(ctx, payload.Sub, c.DefaultQuery("when", models.EventsUpcoming), filter)
+		if err != nil {
+			if errors.Is(err, services.ErrInvalidEventSearch) {
+				badSearch(c, err, "Некорректные параметры: when или курсор")
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении событий",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// GetCatalogEvent Событие из каталога
+// @Summary      Событие из каталога
+// @Description  Возвращает событие с отметкой, засчитано ли оно текущему пользователю, и числом участников.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {object}  models.CatalogEvent   "Событие"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный ID события"
+// @Failure      404  {object}  models.ErrorResponse  "Событие не найдено"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении события"
+// @Router       /events/{id} [get]
+func GetCatalogEvent(eventService *services.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		event, err := eventService.GetCatalogEvent(ctx, payload.Sub, eventId)
+		if err != nil {
+			if errors.Is(err, services.ErrEventNotFound) {
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Событие не найдено",
+				})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении события",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, event)
+	}
+}
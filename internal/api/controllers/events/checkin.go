@@ -0,0 +1,275 @@
+package events
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondCheckinError отвечает на ошибки отметки по QR коду.
+func respondCheckinError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие не найдено",
+		})
+	case errors.Is(err, services.ErrCheckinNotConfigured):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Окно отметки для события не задано",
+		})
+	case errors.Is(err, services.ErrInvalidCheckinWindow):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Конец отметки должен быть позже начала",
+		})
+	case errors.Is(err, services.ErrInvalidCheckinToken):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "QR код недействителен",
+		})
+	case errors.Is(err, services.ErrCheckinTokenExpired):
+		c.JSON(http.StatusGone, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "QR код устарел, отсканируйте новый",
+		})
+	case errors.Is(err, services.ErrCheckinNotOpen), errors.Is(err, services.ErrCheckinClosed):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Отметка на событии сейчас недоступна",
+		})
+	case errors.Is(err, services.ErrAlreadyCheckedIn), errors.Is(err, services.ErrAlreadyCompleted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие уже засчитано",
+		})
+	case errors.Is(err, services.ErrNotRegistered):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Отметиться могут только записавшиеся на событие",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при отметке на событии",
+		})
+	}
+}
+
+// GetCheckinSettings Окно отметки на событии
+// @Summary      Окно отметки на событии
+// @Description  Возвращает окно самостоятельной отметки по QR коду и число отметившихся.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {object}  models.CheckinSettings  "Окно отметки"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный ID события"
+// @Failure      403  {object}  models.ErrorResponse    "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse    "Событие не найдено или окно не задано"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при получении окна отметки"
+// @Router       /admin/events/{id}/checkin [get]
+func GetCheckinSettings(service *services.CheckinService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		settings, err := service.GetSettings(ctx, eventId)
+		if err != nil {
+			respondCheckinError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// SetCheckinSettings Задание окна отметки
+// @Summary      Задание окна отметки
+// @Description  Задает время, в которое студенты могут отметиться на событии по QR коду.
+// @Description  Изменение окна не отзывает уже выданные токены, для этого есть /checkin/rotate.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                            true  "Bearer токен" default(Bearer )
+// @Param        id             path    int                               true  "ID события"
+// @Param        input          body    models.SetCheckinSettingsRequest  true  "Окно отметки"
+// @Success      200  {object}  models.CheckinSettings  "Окно отметки"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный запрос"
+// @Failure      403  {object}  models.ErrorResponse    "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse    "Событие не найдено"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при сохранении окна отметки"
+// @Router       /admin/events/{id}/checkin [put]
+func SetCheckinSettings(service *services.CheckinService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		var body models.SetCheckinSettingsRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		settings, err := service.SetSettings(ctx, payload.Sub, eventId, body)
+		if err != nil {
+			respondCheckinError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, settings)
+	}
+}
+
+// IssueCheckinToken Токен для QR кода отметки
+// @Summary      Токен для QR кода отметки
+// @Description  Выдает подписанный токен для показа в QR коде. Токен живет ttl секунд (от 10 до 600, по умолчанию 60),
+// @Description  но не дольше окна отметки. Экран с QR кодом запрашивает новый токен до expires_at. Вне окна токен не выдается.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        id             path    int     true   "ID события"
+// @Param        ttl            query   int     false  "Время жизни токена в секундах" default(60)
+// @Success      200  {object}  models.CheckinTokenResponse  "Токен"
+// @Failure      400  {object}  models.ErrorResponse         "Некорректный запрос"
+// @Failure      403  {object}  models.ErrorResponse         "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse         "Событие не найдено или окно не задано"
+// @Failure      409  {object}  models.ErrorResponse         "Отметка еще не началась или закончилась"
+// @Failure      500  {object}  models.ErrorResponse         "Ошибка при выдаче токена"
+// @Router       /admin/events/{id}/checkin/token [post]
+func IssueCheckinToken(service *services.CheckinService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		ttl, err := strconv.Atoi(c.DefaultQuery("ttl", strconv.Itoa(int(services.DefaultCheckinTokenTTL.Seconds()))))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный ttl",
+			})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		token, err := service.IssueToken(ctx, eventId, time.Duration(ttl)*time.Second)
+		if err != nil {
+			respondCheckinError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, token)
+	}
+}
+
+// RotateCheckinSecret Отзыв токенов отметки
+// @Summary      Отзыв токенов отметки
+// @Description  Меняет ключ подписи QR кодов события: все ранее выданные токены перестают действовать.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      204  "Ключ заменен"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный ID события"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Событие не найдено или окно не задано"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при замене ключа"
+// @Router       /admin/events/{id}/checkin/rotate [post]
+func RotateCheckinSecret(service *services.CheckinService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.RotateSecret(ctx, payload.Sub, eventId); err != nil {
+			respondCheckinError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// CheckIn Отметка на событии по QR коду
+// @Summary      Отметка на событии по QR коду
+// @Description  Принимает отсканированный токен и засчитывает событие текущему пользователю с начислением баллов.
+// @Description  Отметиться можно один раз и только в окно отметки. На события с ограничением мест - только получившим место.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                 true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CheckinRequest  true  "Токен из QR кода"
+// @Success      200  {object}  models.CheckinResponse  "Событие засчитано"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный или недействительный токен"
+// @Failure      403  {object}  models.ErrorResponse    "Пользователь не записан на событие"
+// @Failure      409  {object}  models.ErrorResponse    "Вне окна отметки или событие уже засчитано"
+// @Failure      410  {object}  models.ErrorResponse    "Срок действия токена истек"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при отметке на событии"
+// @Router       /me/checkin [post]
+func CheckIn(service *services.CheckinService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.CheckinRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		result, err := service.CheckIn(ctx, payload.Sub, body.Token)
+		if err != nil {
+			respondCheckinError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -35,10 +35,12 @@ func CreateEvent(service *services.EventService) gin.HandlerFunc {
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		event, err := service.CreateEvent(ctx, body)
+		event, err := service.CreateEvent(ctx, payload.Sub, body)
 		if err != nil {
 
 			switch {
@@ -48,6 +50,24 @@ func CreateEvent(service *services.EventService) gin.HandlerFunc {
 					Message: "Событие с таким названием уже существует",
 				})
 				return
+			case errors.Is(err, services.ErrEventTypeNotFound):
+				c.JSON(400, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Такого типа мероприятия нет",
+				})
+				return
+			case errors.Is(err, services.ErrInvalidRegistration):
+				c.JSON(400, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Некорректные настройки записи на событие",
+				})
+				return
+			case errors.Is(err, services.ErrInvalidEventStatus):
+				c.JSON(400, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Новое событие может быть черновиком (draft) или опубликованным (published)",
+				})
+				return
 			default:
 				c.JSON(500, models.ErrorResponse{
 					Error:   err.Error(),
@@ -37,10 +37,12 @@ func CreateSuggest(service *services.EventService) gin.HandlerFunc {
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err := service.SuggestEvent(ctx, body.EventId, body.ExpiresAtHours)
+		err := service.SuggestEvent(ctx, payload.Sub, body.EventId, body.ExpiresAtHours)
 		if err != nil {
 			c.JSON(500, models.ErrorResponse{
 				Error:   err.Error(),
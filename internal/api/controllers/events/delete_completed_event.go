@@ -50,10 +50,12 @@ func DeleteCompletedEvent(service *services.CompletedEventsService) gin.HandlerF
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err = service.DeleteCompletedEvent(ctx, userId, eventId)
+		err = service.DeleteCompletedEvent(ctx, payload.Sub, userId, eventId)
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrCompletedEventNotFound):
@@ -14,6 +14,7 @@ import (
 // DeleteEvent  Удаление события по ID
 // @Summary      Удалить событие
 // @Description  Удаляет событие по его идентификатору. Требует прав администратора.
+// @Description  Удаление мягкое: выполненные события и баллы студентов сохраняются, событие можно восстановить.
 // @Tags         admin
 // @Accept       json
 // @Produce      json
@@ -23,6 +24,7 @@ import (
 // @Failure      400  {object}  models.ErrorResponse    "Некорректный ID события"
 // @Failure      401  {object}  models.ErrorResponse    "Нет прав доступа"
 // @Failure      404  {object}  models.ErrorResponse    "Событие с указанным ID не найдено"
+// @Failure      409  {object}  models.ErrorResponse    "Событие уже удалено"
 // @Failure      500  {object}  models.ErrorResponse    "Ошибка сервера при удалении"
 // @Router       /admin/delete_event/{id} [delete]
 func DeleteEvent(service *services.EventService) gin.HandlerFunc {
@@ -38,10 +40,12 @@ func DeleteEvent(service *services.EventService) gin.HandlerFunc {
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err = service.DeleteEvent(ctx, eventId)
+		err = service.DeleteEvent(ctx, payload.Sub, eventId)
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrEventNotFound):
@@ -49,6 +53,11 @@ func DeleteEvent(service *services.EventService) gin.HandlerFunc {
 					Error:   err.Error(),
 					Message: "Событие с таким ID не найдено",
 				})
+			case errors.Is(err, services.ErrEventDeleted):
+				c.JSON(409, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Событие уже удалено",
+				})
 			default:
 				c.JSON(500, models.ErrorResponse{
 					Error:   err.Error(),
@@ -37,10 +37,12 @@ func DeleteSuggestion(service *services.EventService) gin.HandlerFunc {
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err = service.DeleteSuggestion(ctx, eventId)
+		err = service.DeleteSuggestion(ctx, payload.Sub, eventId)
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrNoRowsAffected):
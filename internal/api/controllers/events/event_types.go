@@ -0,0 +1,211 @@
+package events
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondEventTypeError отвечает на ошибки изменения справочника типов мероприятий.
+func respondEventTypeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventTypeNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Тип мероприятия не найден",
+		})
+	case errors.Is(err, services.ErrEventTypeAlreadyExists):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Тип мероприятия с таким кодом уже существует",
+		})
+	case errors.Is(err, services.ErrEventTypeProtected):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Тип 0 используется для событий без типа и не удаляется",
+		})
+	case errors.Is(err, services.ErrInvalidEventType):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Код должен быть больше 0, название не пустым, цвет в формате #RRGGBB",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при изменении типов мероприятий",
+		})
+	}
+}
+
+// parseEventTypeCode читает код типа из пути, при ошибке отвечает 400.
+func parseEventTypeCode(c *gin.Context) (int, bool) {
+	code, err := strconv.Atoi(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный код типа",
+		})
+		return 0, false
+	}
+	return code, true
+}
+
+// GetEventTypes Типы мероприятий
+// @Summary      Типы мероприятий
+// @Description  Возвращает все типы мероприятий с иконками и цветами, упорядоченные по коду.
+// @Tags         events
+// @Produce      json
+// @Success      200  {array}   models.EventType      "Типы мероприятий"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении типов"
+// @Router       /event_types [get]
+// @Router       /admin/event_types [get]
+func GetEventTypes(service *services.EventTypesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		types, err := service.GetEventTypes(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении типов мероприятий",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, types)
+	}
+}
+
+// CreateEventType Добавление типа мероприятия
+// @Summary      Добавление типа мероприятия
+// @Description  Добавляет тип мероприятия. Новый тип сразу появляется в статистике выполненных мероприятий.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                         true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CreateEventTypeRequest  true  "Тип мероприятия"
+// @Success      201  {object}  models.EventType      "Добавленный тип"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      409  {object}  models.ErrorResponse  "Тип с таким кодом уже существует"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при добавлении типа"
+// @Router       /admin/event_types [post]
+func CreateEventType(service *services.EventTypesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.CreateEventTypeRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		eventType, err := service.CreateEventType(ctx, payload.Sub, body)
+		if err != nil {
+			respondEventTypeError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, eventType)
+	}
+}
+
+// UpdateEventType Изменение типа мероприятия
+// @Summary      Изменение типа мероприятия
+// @Description  Меняет название, иконку или цвет типа. Пустые поля не меняются, код не меняется.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                         true  "Bearer токен" default(Bearer )
+// @Param        code           path    int                            true  "Код типа"
+// @Param        input          body    models.UpdateEventTypeRequest  true  "Новые значения полей"
+// @Success      200  {object}  models.EventType      "Обновленный тип"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Тип не найден"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при изменении типа"
+// @Router       /admin/event_types/{code} [patch]
+func UpdateEventType(service *services.EventTypesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		code, ok := parseEventTypeCode(c)
+		if !ok {
+			return
+		}
+
+		var body models.UpdateEventTypeRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		eventType, err := service.UpdateEventType(ctx, payload.Sub, code, body)
+		if err != nil {
+			respondEventTypeError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, eventType)
+	}
+}
+
+// DeleteEventType Удаление типа мероприятия
+// @Summary      Удаление типа мероприятия
+// @Description  Удаляет тип мероприятия. События этого типа получают тип 0 (неизвестно), выполненные мероприятия сохраняются.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        code           path    int     true  "Код типа"
+// @Success      204  "Тип удален"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Тип не найден"
+// @Failure      409  {object}  models.ErrorResponse  "Тип 0 нельзя удалить"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при удалении типа"
+// @Router       /admin/event_types/{code} [delete]
+func DeleteEventType(service *services.EventTypesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		code, ok := parseEventTypeCode(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.DeleteEventType(ctx, payload.Sub, code); err != nil {
+			respondEventTypeError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
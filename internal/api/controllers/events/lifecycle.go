@@ -0,0 +1,120 @@
+package events
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondLifecycleError отвечает на ошибки смены статуса и восстановления события.
+func respondLifecycleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие не найдено",
+		})
+	case errors.Is(err, services.ErrEventDeleted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие удалено, сначала восстановите его",
+		})
+	case errors.Is(err, services.ErrEventNotDeleted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие не удалено",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при изменении события",
+		})
+	}
+}
+
+// changeEventHandler - общий обработчик POST /admin/events/{id}/... без тела запроса.
+func changeEventHandler(change func(ctx context.Context, actorId, eventId int64) (models.CreateEventResponse, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		event, err := change(ctx, payload.Sub, eventId)
+		if err != nil {
+			respondLifecycleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, event)
+	}
+}
+
+// PublishEvent Публикация события
+// @Summary      Публикация события
+// @Description  Публикует черновик или возвращает событие из архива: оно появляется в каталоге студентов.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {object}  models.CreateEventResponse  "Событие"
+// @Failure      400  {object}  models.ErrorResponse        "Некорректный ID события"
+// @Failure      403  {object}  models.ErrorResponse        "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse        "Событие не найдено"
+// @Failure      409  {object}  models.ErrorResponse        "Событие удалено"
+// @Failure      500  {object}  models.ErrorResponse        "Ошибка при изменении события"
+// @Router       /admin/events/{id}/publish [post]
+func PublishEvent(service *services.EventService) gin.HandlerFunc {
+	return changeEventHandler(service.PublishEvent)
+}
+
+// ArchiveEvent Архивация события
+// @Summary      Архивация события
+// @Description  Отправляет событие в архив: оно пропадает из каталога, запись и отметка по QR коду закрываются.
+// @Description  Выполненные события и баллы студентов сохраняются.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {object}  models.CreateEventResponse  "Событие"
+// @Failure      400  {object}  models.ErrorResponse        "Некорректный ID события"
+// @Failure      403  {object}  models.ErrorResponse        "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse        "Событие не найдено"
+// @Failure      409  {object}  models.ErrorResponse        "Событие удалено"
+// @Failure      500  {object}  models.ErrorResponse        "Ошибка при изменении события"
+// @Router       /admin/events/{id}/archive [post]
+func ArchiveEvent(service *services.EventService) gin.HandlerFunc {
+	return changeEventHandler(service.ArchiveEvent)
+}
+
+// RestoreEvent Восстановление удаленного события
+// @Summary      Восстановление удаленного события
+// @Description  Возвращает удаленное событие с тем статусом, который был до удаления.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {object}  models.CreateEventResponse  "Событие"
+// @Failure      400  {object}  models.ErrorResponse        "Некорректный ID события"
+// @Failure      403  {object}  models.ErrorResponse        "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse        "Событие не найдено"
+// @Failure      409  {object}  models.ErrorResponse        "Событие не удалено"
+// @Failure      500  {object}  models.ErrorResponse        "Ошибка при изменении события"
+// @Router       /admin/events/{id}/restore [post]
+func RestoreEvent(service *services.EventService) gin.HandlerFunc {
+	return changeEventHandler(service.RestoreEvent)
+}
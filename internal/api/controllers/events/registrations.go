@@ -0,0 +1,274 @@
+package events
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondRegistrationError отвечает на ошибки записи на события.
+func respondRegistrationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие не найдено",
+		})
+	case errors.Is(err, services.ErrNotRegistered):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Вы не записаны на это событие",
+		})
+	case errors.Is(err, services.ErrRegistrationNotOpen), errors.Is(err, services.ErrRegistrationClosed):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Запись на событие сейчас недоступна",
+		})
+	case errors.Is(err, services.ErrAlreadyRegistered):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Вы уже записаны на это событие",
+		})
+	case errors.Is(err, services.ErrAlreadyCompleted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Событие уже засчитано",
+		})
+	case errors.Is(err, services.ErrAttendanceMarked):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Посещение уже отмечено",
+		})
+	case errors.Is(err, services.ErrInvalidAttendance):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Укажите user_ids или all",
+		})
+	case errors.Is(err, services.ErrUserNotFound), errors.Is(err, services.ErrInvalidReference):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пользователь не найден",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при записи на событие",
+		})
+	}
+}
+
+// parseEventId читает id события из пути, при ошибке отвечает 400.
+func parseEventId(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный ID события",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// RegisterForEvent Запись на событие
+// @Summary      Запись на событие
+// @Description  Записывает текущего пользователя на событие. Если мест нет, пользователь попадает в лист ожидания
+// @Description  и получает место автоматически, когда оно освободится.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      201  {object}  models.EventRegistration  "Запись или место в листе ожидания"
+// @Failure      400  {object}  models.ErrorResponse      "Некорректный ID события"
+// @Failure      404  {object}  models.ErrorResponse      "Событие не найдено"
+// @Failure      409  {object}  models.ErrorResponse      "Запись закрыта, уже записан или событие уже засчитано"
+// @Failure      500  {object}  models.ErrorResponse      "Ошибка при записи на событие"
+// @Router       /me/events/{id}/register [post]
+func RegisterForEvent(service *services.RegistrationsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		registration, err := service.Register(ctx, payload.Sub, eventId)
+		if err != nil {
+			respondRegistrationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, registration)
+	}
+}
+
+// GetEventRegistration Моя запись на событие
+// @Summary      Моя запись на событие
+// @Description  Возвращает статус записи текущего пользователя и место в листе ожидания.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {object}  models.EventRegistration  "Запись на событие"
+// @Failure      400  {object}  models.ErrorResponse      "Некорректный ID события"
+// @Failure      404  {object}  models.ErrorResponse      "Событие не найдено или пользователь не записан"
+// @Failure      500  {object}  models.ErrorResponse      "Ошибка при получении записи"
+// @Router       /me/events/{id}/register [get]
+func GetEventRegistration(service *services.RegistrationsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		registration, err := service.GetRegistration(ctx, payload.Sub, eventId)
+		if err != nil {
+			respondRegistrationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, registration)
+	}
+}
+
+// UnregisterFromEvent Отмена записи на событие
+// @Summary      Отмена записи на событие
+// @Description  Отменяет запись текущего пользователя (или убирает из листа ожидания).
+// @Description  Освободившееся место получает первый из листа ожидания. После отметки посещения отменить запись нельзя.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      204  "Запись отменена"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный ID события"
+// @Failure      404  {object}  models.ErrorResponse  "Событие не найдено или пользователь не записан"
+// @Failure      409  {object}  models.ErrorResponse  "Посещение уже отмечено"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при отмене записи"
+// @Router       /me/events/{id}/unregister [post]
+func UnregisterFromEvent(service *services.RegistrationsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.Unregister(ctx, payload.Sub, eventId); err != nil {
+			respondRegistrationError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// GetAttendees Список записавшихся на событие
+// @Summary      Список записавшихся на событие
+// @Description  Возвращает получивших место (status=registered) и лист ожидания по порядку,
+// @Description  с отметкой посещения и признаком засчитанного события.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID события"
+// @Success      200  {array}   models.Attendee       "Записавшиеся"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный ID события"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Событие не найдено"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении списка"
+// @Router       /admin/events/{id}/attendees [get]
+func GetAttendees(service *services.RegistrationsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		attendees, err := service.GetAttendees(ctx, eventId)
+		if err != nil {
+			respondRegistrationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, attendees)
+	}
+}
+
+// MarkAttendance Отметка посещения
+// @Summary      Отметка посещения
+// @Description  Отмечает пришедших на событие и засчитывает им событие с начислением баллов одним действием.
+// @Description  all=true отмечает всех получивших место. Не записанные и ожидающие пропускаются,
+// @Description  уже выполнившим событие баллы повторно не начисляются.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                        true  "Bearer токен" default(Bearer )
+// @Param        id             path    int                           true  "ID события"
+// @Param        input          body    models.MarkAttendanceRequest  true  "Пришедшие пользователи"
+// @Success      200  {object}  models.MarkAttendanceResponse  "Итог отметки"
+// @Failure      400  {object}  models.ErrorResponse           "Некорректный запрос"
+// @Failure      403  {object}  models.ErrorResponse           "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse           "Событие не найдено"
+// @Failure      500  {object}  models.ErrorResponse           "Ошибка при отметке посещения"
+// @Router       /admin/events/{id}/attendance [post]
+func MarkAttendance(service *services.RegistrationsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		eventId, ok := parseEventId(c)
+		if !ok {
+			return
+		}
+
+		var body models.MarkAttendanceRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		result, err := service.MarkAttendance(ctx, payload.Sub, eventId, body)
+		if err != nil {
+			respondRegistrationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
@@ -0,0 +1,201 @@
+package events
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchEvents Поиск событий
+// @Summary      Поиск событий
+// @Description  Полнотекстовый поиск по названию и описанию (морфология русского языка) с фильтрами и сортировкой.
+// @Description  Выдача постраничная: следующая страница запрашивается с cursor из next_cursor и теми же sort и order.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization    header  string  true   "Bearer токен" default(Bearer )
+// @Param        q                query   string  false  "Поисковый запрос"
+// @Param        event_type_code  query   int     false  "Тип мероприятия"
+// @Param        from             query   string  false  "Дата события от (RFC3339)"
+// @Param        to               query   string  false  "Дата события до, не включая (RFC3339)"
+// @Param        points_min       query   int     false  "Баллы от"
+// @Param        points_max       query   int     false  "Баллы до (включительно)"
+// @Param        status           query   string  false  "Статусы через запятую (draft,published,archived), по умолчанию любые"
+// @Param        deleted          query   bool    false  "Искать среди удаленных событий" default(false)
+// @Param        sort             query   string  false  "Сортировка" Enums(date, points) default(date)
+// @Param        order            query   string  false  "Порядок" Enums(asc, desc) default(desc)
+// @Param        cursor           query   string  false  "Курсор следующей страницы"
+// @Param        limit            query   int     false  "Размер страницы (до 100)" default(20)
+// @Success      200  {object}  models.EventSearchResponse  "Страница событий"
+// @Failure      400  {object}  models.ErrorResponse        "Некорректные параметры поиска"
+// @Failure      403  {object}  models.ErrorResponse        "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse        "Ошибка при поиске событий"
+// @Router       /admin/events/search [get]
+func SearchEvents(eventService *services.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		filter, ok := parseSearchFilter(c)
+		if !ok {
+			return
+		}
+
+		if v := c.Query("status"); v != "" {
+			for _, status := range strings.Split(v, ",") {
+				switch status = strings.TrimSpace(status); status {
+				case models.EventStatusDraft, models.EventStatusPublished, models.EventStatusArchived:
+					filter.Statuses = append(filter.Statuses, status)
+				default:
+					badSearch(c, errors.New("invalid status"), "Некорректный status, ожидается draft, published или archived")
+					return
+				}
+			}
+		}
+		filter.Deleted, _ = strconv.ParseBool(c.DefaultQuery("deleted", "false"))
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		result, err := eventService.SearchEvents(ctx, filter)
+		if err != nil {
+			respondSearchError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// SearchCatalog Поиск по каталогу событий
+// @Summary      Поиск по каталогу событий
+// @Description  Полнотекстовый поиск по опубликованным событиям (морфология русского языка) с фильтрами и сортировкой.
+// @Description  У каждого события указано, засчитано ли оно текущему пользователю, и число участников.
+// @Description  Выдача постраничная: следующая страница запрашивается с cursor из next_cursor и теми же sort и order.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization    header  string  true   "Bearer токен" default(Bearer )
+// @Param        q                query   string  false  "Поисковый запрос"
+// @Param        event_type_code  query   int     false  "Тип мероприятия"
+// @Param        from             query   string  false  "Дата события от (RFC3339)"
+// @Param        to               query   string  false  "Дата события до, не включая (RFC3339)"
+// @Param        points_min       query   int     false  "Баллы от"
+// @Param        points_max       query   int     false  "Баллы до (включительно)"
+// @Param        sort             query   string  false  "Сортировка" Enums(date, points) default(date)
+// @Param        order            query   string  false  "Порядок" Enums(asc, desc) default(desc)
+// @Param        cursor           query   string  false  "Курсор следующей страницы"
+// @Param        limit            query   int     false  "Размер страницы (до 100)" default(20)
+// @Success      200  {object}  models.EventCatalogResponse  "Страница событий"
+// @Failure      400  {object}  models.ErrorResponse         "Некорректные параметры поиска"
+// @Failure      500  {object}  models.ErrorResponse         "Ошибка при поиске событий"
+// @Router       /events/search [get]
+func SearchCatalog(eventService *services.EventService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		filter, ok := parseSearchFilter(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		result, err := eventService.SearchCatalog(ctx, payload.Sub, filter)
+		if err != nil {
+			respondSearchError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// parseSearchFilter читает общие параметры поиска событий, при ошибке отвечает 400.
+func parseSearchFilter(c *gin.Context) (models.EventSearchFilter, bool) {
+	filter := models.EventSearchFilter{
+		Query:  c.Query("q"),
+		Sort:   c.DefaultQuery("sort", models.EventSortDate),
+		Cursor: c.Query("cursor"),
+	}
+
+	switch c.DefaultQuery("order", "desc") {
+	case "desc":
+		filter.Desc = true
+	case "asc":
+	default:
+		badSearch(c, errors.New("invalid order"), "Некорректный order, ожидается asc или desc")
+		return filter, false
+	}
+
+	var err error
+	if filter.EventTypeCode, err = optionalInt(c, "event_type_code"); err != nil {
+		badSearch(c, err, "Некорректный event_type_code")
+		return filter, false
+	}
+	if filter.PointsMin, err = optionalInt(c, "points_min"); err != nil {
+		badSearch(c, err, "Некорректный points_min")
+		return filter, false
+	}
+	if filter.PointsMax, err = optionalInt(c, "points_max"); err != nil {
+		badSearch(c, err, "Некорректный points_max")
+		return filter, false
+	}
+	if v := c.Query("from"); v != "" {
+		from, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			badSearch(c, err, "Некорректная дата from, ожидается RFC3339")
+			return filter, false
+		}
+		filter.DateFrom = &from
+	}
+	if v := c.Query("to"); v != "" {
+		to, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			badSearch(c, err, "Некорректная дата to, ожидается RFC3339")
+			return filter, false
+		}
+		filter.DateTo = &to
+	}
+	filter.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultEventSearchLimit)))
+
+	return filter, true
+}
+
+func respondSearchError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrInvalidEventSearch) {
+		badSearch(c, err, "Некорректные параметры поиска: sort, курсор или границы диапазонов")
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+		Error:   err.Error(),
+		Message: "Ошибка при поиске событий",
+	})
+}
+
+// optionalInt читает необязательный целочисленный параметр запроса.
+func optionalInt(c *gin.Context, name string) (*int, error) {
+	v := c.Query(name)
+	if v == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+func badSearch(c *gin.Context, err error, message string) {
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{
+		Error:   err.Error(),
+		Message: message,
+	})
+}
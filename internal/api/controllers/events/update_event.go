@@ -4,6 +4,7 @@ import (
 	"bobri/internal/api/services"
 	"bobri/internal/models"
 	"context"
+	"errors"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -21,6 +22,8 @@ import (
 // @Success      200  {object}  models.SuccessResponse  "Событие успешно обновлено"
 // @Failure      400  {object}  models.ErrorResponse  "Некорректный JSON или ошибка валидации"
 // @Failure      401  {object}  models.ErrorResponse  "Неавторизованный доступ — неверный или отсутствующий токен"
+// @Failure      404  {object}  models.ErrorResponse  "Событие не найдено"
+// @Failure      409  {object}  models.ErrorResponse  "Событие с таким названием уже существует или событие удалено"
 // @Failure      500  {object}  models.ErrorResponse  "Ошибка сервера при попытке обновления записи"
 // @Router       /admin/update_event [patch]
 func UpdateEvent(eventService *services.EventService) gin.HandlerFunc {
@@ -35,15 +38,45 @@ func UpdateEvent(eventService *services.EventService) gin.HandlerFunc {
 			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err := eventService.UpdateEvent(ctx, updateData)
+		err := eventService.UpdateEvent(ctx, payload.Sub, updateData)
 		if err != nil {
-			c.JSON(500, models.ErrorResponse{
-				Error:   err.Error(),
-				Message: "Ошибка при обновлении события",
-			})
+			switch {
+			case errors.Is(err, services.ErrEventNotFound):
+				c.JSON(404, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Событие не найдено",
+				})
+			case errors.Is(err, services.ErrEventAlreadyExists):
+				c.JSON(409, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Событие с таким названием уже существует",
+				})
+			case errors.Is(err, services.ErrEventTypeNotFound):
+				c.JSON(400, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Такого типа мероприятия нет",
+				})
+			case errors.Is(err, services.ErrInvalidRegistration):
+				c.JSON(400, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Некорректные настройки записи на событие",
+				})
+			case errors.Is(err, services.ErrEventDeleted):
+				c.JSON(409, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Событие удалено, сначала восстановите его",
+				})
+			default:
+				c.JSON(500, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при обновлении события",
+				})
+			}
 			return
 		}
 
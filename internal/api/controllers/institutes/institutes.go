@@ -0,0 +1,465 @@
+package institutes
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondStructureError отвечает на ошибки изменения структуры институтов.
+func respondStructureError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInstituteNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Институт не найден",
+		})
+	case errors.Is(err, services.ErrStudyNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Направление не найдено",
+		})
+	case errors.Is(err, services.ErrStudentGroupNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Группа не найдена",
+		})
+	case errors.Is(err, services.ErrStructureNameTaken):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Такое название уже используется",
+		})
+	case errors.Is(err, services.ErrStructureNotEmpty), errors.Is(err, services.ErrStudentGroupInUse):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Нельзя удалить непустой элемент структуры",
+		})
+	case errors.Is(err, services.ErrStructureNameEmpty):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Название не может быть пустым",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при изменении структуры институтов",
+		})
+	}
+}
+
+// parseIdParam читает id из пути, при ошибке отвечает 400.
+func parseIdParam(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		if err == nil {
+			err = errors.New("id must be positive")
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный id",
+		})
+		return 0, false
+	}
+	return id, true
+}
+
+// bindJSON разбирает тело запроса, при ошибке отвечает 400.
+func bindJSON(c *gin.Context, body any) bool {
+	if err := c.ShouldBindJSON(body); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный JSON",
+		})
+		return false
+	}
+	return true
+}
+
+// GetInstitutes Структура институтов
+// @Summary      Структура институтов
+// @Description  Возвращает институты с направлениями подготовки и группами, а также группы, которым еще не назначено направление.
+// @Tags         institutes
+// @Produce      json
+// @Success      200  {object}  models.InstitutesTree  "Структура институтов"
+// @Failure      500  {object}  models.ErrorResponse   "Ошибка при получении структуры"
+// @Router       /institutes [get]
+func GetInstitutes(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		tree, err := service.GetTree(ctx)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении структуры институтов",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, tree)
+	}
+}
+
+// CreateInstitute Добавление института
+// @Summary      Добавление института
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                   true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.InstituteRequest  true  "Название института"
+// @Success      201  {object}  models.Institute      "Добавленный институт"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      409  {object}  models.ErrorResponse  "Такое название уже используется"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при добавлении института"
+// @Router       /admin/institutes [post]
+func CreateInstitute(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.InstituteRequest
+		if !bindJSON(c, &body) {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		institute, err := service.CreateInstitute(ctx, payload.Sub, body.Name)
+		if err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, institute)
+	}
+}
+
+// UpdateInstitute Переименование института
+// @Summary      Переименование института
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                   true  "Bearer токен" default(Bearer )
+// @Param        id             path    int                      true  "Id института"
+// @Param        input          body    models.InstituteRequest  true  "Новое название"
+// @Success      200  {object}  models.Institute      "Обновленный институт"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Институт не найден"
+// @Failure      409  {object}  models.ErrorResponse  "Такое название уже используется"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при изменении института"
+// @Router       /admin/institutes/{id} [patch]
+func UpdateInstitute(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseIdParam(c)
+		if !ok {
+			return
+		}
+
+		var body models.InstituteRequest
+		if !bindJSON(c, &body) {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		institute, err := service.UpdateInstitute(ctx, payload.Sub, id, body.Name)
+		if err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, institute)
+	}
+}
+
+// DeleteInstitute Удаление института
+// @Summary      Удаление института
+// @Description  Удаляет институт, в котором не осталось направлений.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "Id института"
+// @Success      204  "Институт удален"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Институт не найден"
+// @Failure      409  {object}  models.ErrorResponse  "В институте есть направления"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при удалении института"
+// @Router       /admin/institutes/{id} [delete]
+func DeleteInstitute(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseIdParam(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.DeleteInstitute(ctx, payload.Sub, id); err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// CreateStudy Добавление направления
+// @Summary      Добавление направления подготовки
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CreateStudyRequest  true  "Направление"
+// @Success      201  {object}  models.Study          "Добавленное направление"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Институт не найден"
+// @Failure      409  {object}  models.ErrorResponse  "Такое название уже используется"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при добавлении направления"
+// @Router       /admin/studies [post]
+func CreateStudy(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.CreateStudyRequest
+		if !bindJSON(c, &body) {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		study, err := service.CreateStudy(ctx, payload.Sub, body)
+		if err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, study)
+	}
+}
+
+// UpdateStudy Изменение направления
+// @Summary      Изменение направления подготовки
+// @Description  Меняет название направления или переносит его в другой институт. Пустые поля не меняются.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        id             path    int                        true  "Id направления"
+// @Param        input          body    models.UpdateStudyRequest  true  "Новые значения полей"
+// @Success      200  {object}  models.Study          "Обновленное направление"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Направление или институт не найдены"
+// @Failure      409  {object}  models.ErrorResponse  "Такое название уже используется"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при изменении направления"
+// @Router       /admin/studies/{id} [patch]
+func UpdateStudy(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseIdParam(c)
+		if !ok {
+			return
+		}
+
+		var body models.UpdateStudyRequest
+		if !bindJSON(c, &body) {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		study, err := service.UpdateStudy(ctx, payload.Sub, id, body)
+		if err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, study)
+	}
+}
+
+// DeleteStudy Удаление направления
+// @Summary      Удаление направления подготовки
+// @Description  Удаляет направление, в котором не осталось групп.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "Id направления"
+// @Success      204  "Направление удалено"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Направление не найдено"
+// @Failure      409  {object}  models.ErrorResponse  "В направлении есть группы"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при удалении направления"
+// @Router       /admin/studies/{id} [delete]
+func DeleteStudy(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseIdParam(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.DeleteStudy(ctx, payload.Sub, id); err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// CreateGroup Добавление группы
+// @Summary      Добавление группы
+// @Description  Добавляет учебную группу. Без study_id группа попадает в список групп без направления.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CreateGroupRequest  true  "Группа"
+// @Success      201  {object}  models.StudentGroup   "Добавленная группа"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Направление не найдено"
+// @Failure      409  {object}  models.ErrorResponse  "Такое название уже используется"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при добавлении группы"
+// @Router       /admin/groups [post]
+func CreateGroup(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.CreateGroupRequest
+		if !bindJSON(c, &body) {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		group, err := service.CreateGroup(ctx, payload.Sub, body)
+		if err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, group)
+	}
+}
+
+// UpdateGroup Изменение группы
+// @Summary      Изменение группы
+// @Description  Меняет название группы или переносит ее на другое направление. Пустые поля не меняются. Студенты и пользователи группы сразу видят новое название.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        id             path    int                        true  "Id группы"
+// @Param        input          body    models.UpdateGroupRequest  true  "Новые значения полей"
+// @Success      200  {object}  models.StudentGroup   "Обновленная группа"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Группа или направление не найдены"
+// @Failure      409  {object}  models.ErrorResponse  "Такое название уже используется"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при изменении группы"
+// @Router       /admin/groups/{id} [patch]
+func UpdateGroup(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseIdParam(c)
+		if !ok {
+			return
+		}
+
+		var body models.UpdateGroupRequest
+		if !bindJSON(c, &body) {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		group, err := service.UpdateGroup(ctx, payload.Sub, id, body)
+		if err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, group)
+	}
+}
+
+// DeleteGroup Удаление группы
+// @Summary      Удаление группы
+// @Description  Удаляет группу, в которой нет студентов реестра и пользователей.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "Id группы"
+// @Success      204  "Группа удалена"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Группа не найдена"
+// @Failure      409  {object}  models.ErrorResponse  "В группе есть студенты или пользователи"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при удалении группы"
+// @Router       /admin/groups/{id} [delete]
+func DeleteGroup(service *services.InstitutesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id, ok := parseIdParam(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.DeleteGroup(ctx, payload.Sub, id); err != nil {
+			respondStructureError(c, err)
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,197 @@
+package uploads
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondUploadError отвечает на ошибки загрузки файлов.
+func respondUploadError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEmptyFile):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Файл пустой",
+		})
+	case errors.Is(err, services.ErrFileTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Файл слишком большой",
+		})
+	case errors.Is(err, services.ErrUnsupportedFileType):
+		c.JSON(http.StatusUnsupportedMediaType, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Недопустимый тип файла",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пользователь не найден",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при загрузке файла",
+		})
+	}
+}
+
+// readFile читает поле file из multipart формы. Тело запроса ограничено, чтобы большой файл
+// не читался целиком; точный предел для вида файла проверяет сервис. При ошибке отвечает сам.
+func readFile(c *gin.Context) (string, []byte, bool) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxUploadSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			respondUploadError(c, services.ErrFileTooLarge)
+			return "", nil, false
+		}
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ожидается multipart/form-data с полем file",
+		})
+		return "", nil, false
+	}
+	if header.Size > services.MaxUploadSize {
+		respondUploadError(c, services.ErrFileTooLarge)
+		return "", nil, false
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		respondUploadError(c, err)
+		return "", nil, false
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, services.MaxUploadSize+1))
+	if err != nil {
+		respondUploadError(c, err)
+		return "", nil, false
+	}
+
+	return header.Filename, data, true
+}
+
+// UploadAvatar Загрузка аватара
+// @Summary      Загрузка аватара
+// @Description  Загружает аватар (PNG, JPEG, WebP или GIF до 2 МБ) и сразу ставит его в профиль.
+// @Description  Тип файла определяется по содержимому.
+// @Tags         user
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header    string  true  "Bearer токен" default(Bearer )
+// @Param        file           formData  file    true  "Изображение"
+// @Success      200  {object}  models.ProfileResponse  "Обновленный профиль"
+// @Failure      400  {object}  models.ErrorResponse    "Файл не передан"
+// @Failure      413  {object}  models.ErrorResponse    "Файл слишком большой"
+// @Failure      415  {object}  models.ErrorResponse    "Недопустимый тип файла"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при загрузке файла"
+// @Router       /me/avatar [post]
+func UploadAvatar(service *services.UploadService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		name, data, ok := readFile(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		profile, err := service.UploadAvatar(ctx, payload.Sub, name, data)
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, profile)
+	}
+}
+
+// UploadEvidence Загрузка подтверждения к заявке
+// @Summary      Загрузка подтверждения к заявке
+// @Description  Загружает скан, фото или сертификат (PNG, JPEG, WebP или PDF до 10 МБ).
+// @Description  Полученный url передается в attachments при подаче заявки на /me/claims.
+// @Tags         user
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header    string  true  "Bearer токен" default(Bearer )
+// @Param        file           formData  file    true  "Файл"
+// @Success      201  {object}  models.UploadedFile   "Загруженный файл"
+// @Failure      400  {object}  models.ErrorResponse  "Файл не передан"
+// @Failure      413  {object}  models.ErrorResponse  "Файл слишком большой"
+// @Failure      415  {object}  models.ErrorResponse  "Недопустимый тип файла"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при загрузке файла"
+// @Router       /me/uploads [post]
+func UploadEvidence(service *services.UploadService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		name, data, ok := readFile(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		file, err := service.UploadEvidence(ctx, name, data)
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, file)
+	}
+}
+
+// UploadIcon Загрузка иконки
+// @Summary      Загрузка иконки
+// @Description  Загружает иконку (PNG, JPEG, WebP или GIF до 1 МБ) для события или типа мероприятия.
+// @Description  Полученный url передается в icon_url при создании или изменении.
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header    string  true  "Bearer токен" default(Bearer )
+// @Param        file           formData  file    true  "Изображение"
+// @Success      201  {object}  models.UploadedFile   "Загруженный файл"
+// @Failure      400  {object}  models.ErrorResponse  "Файл не передан"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      413  {object}  models.ErrorResponse  "Файл слишком большой"
+// @Failure      415  {object}  models.ErrorResponse  "Недопустимый тип файла"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при загрузке файла"
+// @Router       /admin/uploads/icon [post]
+func UploadIcon(service *services.UploadService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		name, data, ok := readFile(c)
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		file, err := service.UploadIcon(ctx, name, data)
+		if err != nil {
+			respondUploadError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, file)
+	}
+}
@@ -0,0 +1,187 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondAccountError отвечает на ошибки изменения аккаунта.
+func respondAccountError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidPassword):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Неправильный пароль",
+		})
+	case errors.Is(err, services.ErrWeakPassword):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пароль должен быть не менее 8 символов",
+		})
+	case errors.Is(err, services.ErrSamePassword), errors.Is(err, services.ErrSameEmail):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Новое значение совпадает с текущим",
+		})
+	case errors.Is(err, services.ErrUserWithEmailAlreadyExists):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пользователь с такой почтой уже существует",
+		})
+	case errors.Is(err, services.ErrDeletionAlreadyScheduled):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Удаление аккаунта уже запланировано",
+		})
+	case errors.Is(err, services.ErrDeletionNotScheduled):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Удаление аккаунта не запланировано",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пользователь не найден",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при изменении аккаунта",
+		})
+	}
+}
+
+// ChangePassword Смена пароля
+// @Summary      Смена пароля
+// @Description  Меняет пароль пользователя. Требует текущий пароль. Все сессии, кроме текущей, завершаются,
+// @Description  выданные access токены отзываются: текущая сессия получает новый через /auth/refresh.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                        true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.ChangePasswordRequest  true  "Текущий и новый пароль"
+// @Success      200  {object}  models.SuccessResponse  "Пароль изменен"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный JSON или слабый пароль"
+// @Failure      401  {object}  models.ErrorResponse    "Неправильный текущий пароль"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при смене пароля"
+// @Router       /me/password [post]
+func ChangePassword(service *services.AccountService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.ChangePasswordRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.ChangePassword(ctx, payload.Sub, payload.Sid, body.CurrentPassword, body.NewPassword); err != nil {
+			respondAccountError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Пароль изменен",
+		})
+	}
+}
+
+// ChangeEmail Смена почты
+// @Summary      Смена почты
+// @Description  Меняет почту пользователя. Требует текущий пароль. Новая почта считается неподтвержденной
+// @Description  до ввода токена из письма в /auth/verify_email.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                     true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.ChangeEmailRequest  true  "Пароль и новая почта"
+// @Success      200  {object}  models.SuccessResponse  "Почта изменена, письмо с подтверждением отправлено"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный JSON или почта"
+// @Failure      401  {object}  models.ErrorResponse    "Неправильный пароль"
+// @Failure      409  {object}  models.ErrorResponse    "Почта занята другим пользователем"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при смене почты"
+// @Router       /me/email [post]
+func ChangeEmail(service *services.AccountService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.ChangeEmailRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+		defer cancel()
+
+		if err := service.ChangeEmail(ctx, payload.Sub, body.Password, body.NewEmail); err != nil {
+			respondAccountError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Почта изменена, подтвердите ее по ссылке из письма",
+		})
+	}
+}
+
+// UpdateProfile Изменение профиля
+// @Summary      Изменение профиля
+// @Description  Меняет поля профиля, доступные пользователю (аватар). ФИО, группа и номер книжки меняются только администратором.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                       true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.UpdateProfileRequest  true  "Новые значения полей"
+// @Success      200  {object}  models.ProfileResponse  "Обновленный профиль"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный JSON"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при изменении профиля"
+// @Router       /me/profile [patch]
+func UpdateProfile(service *services.AccountService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.UpdateProfileRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		profile, err := service.UpdateProfile(ctx, payload.Sub, body)
+		if err != nil {
+			respondAccountError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, profile)
+	}
+}
@@ -0,0 +1,185 @@
+package users
+
+import (
+	"archive/zip"
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportAccount Выгрузка персональных данных
+// @Summary      Выгрузка персональных данных
+// @Description  Возвращает все данные, которые сервис хранит о пользователе: профиль, выполненные события, историю баллов и сессии. При format=zip данные отдаются архивом с отдельным JSON файлом на каждый раздел.
+// @Tags         user
+// @Produce      json
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        format         query   string  false  "Формат выгрузки: json (по умолчанию) или zip"
+// @Success      200  {object}  models.AccountExport   "Данные пользователя"
+// @Failure      400  {object}  models.ErrorResponse   "Неизвестный формат"
+// @Failure      404  {object}  models.ErrorResponse   "Пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse   "Ошибка при выгрузке данных"
+// @Router       /me/export [get]
+func ExportAccount(service *services.AccountService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		format := c.DefaultQuery("format", "json")
+		if format != "json" && format != "zip" {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   "unknown format " + format,
+				Message: "Формат выгрузки должен быть json или zip",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		export, err := service.Export(ctx, payload.Sub)
+		if err != nil {
+			respondAccountError(c, err)
+			return
+		}
+
+		filename := fmt.Sprintf("export-%d-%s", payload.Sub, export.ExportedAt.Format("20060102"))
+
+		if format == "json" {
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.json"`, filename))
+			c.JSON(http.StatusOK, export)
+			return
+		}
+
+		var archive bytes.Buffer
+		if err := writeExportArchive(&archive, export); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при выгрузке данных",
+			})
+			return
+		}
+
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.zip"`, filename))
+		c.Data(http.StatusOK, "application/zip", archive.Bytes())
+	}
+}
+
+// writeExportArchive пишет выгрузку в zip архив: по одному JSON файлу на раздел.
+func writeExportArchive(w io.Writer, export models.AccountExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{"profile.json", export.Profile},
+		{"completed_events.json", export.CompletedEvents},
+		{"points_history.json", export.PointsHistory},
+		{"sessions.json", export.Sessions},
+	}
+
+	for _, file := range files {
+		f, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     file.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return fmt.Errorf("could not create %s: %w", file.name, err)
+		}
+
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(file.data); err != nil {
+			return fmt.Errorf("could not write %s: %w", file.name, err)
+		}
+	}
+
+	return archive.Close()
+}
+
+// DeleteAccount Удаление аккаунта
+// @Summary      Удаление аккаунта
+// @Description  Планирует удаление аккаунта через 14 дней. Требует пароль. До этого срока удаление можно отменить. После срока персональные данные стираются, а выполненные события и баллы остаются в общей статистике без привязки к личности.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                       true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.DeleteAccountRequest  true  "Текущий пароль"
+// @Success      202  {object}  models.DeleteAccountResponse  "Удаление запланировано"
+// @Failure      400  {object}  models.ErrorResponse          "Некорректный JSON"
+// @Failure      401  {object}  models.ErrorResponse          "Неправильный пароль"
+// @Failure      409  {object}  models.ErrorResponse          "Удаление уже запланировано"
+// @Failure      500  {object}  models.ErrorResponse          "Ошибка при удалении аккаунта"
+// @Router       /me/delete [post]
+func DeleteAccount(service *services.AccountService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.DeleteAccountRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		at, err := service.RequestDeletion(ctx, payload.Sub, body.Password)
+		if err != nil {
+			respondAccountError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusAccepted, models.DeleteAccountResponse{
+			Successful:          true,
+			DeletionScheduledAt: at,
+		})
+	}
+}
+
+// CancelAccountDeletion Отмена удаления аккаунта
+// @Summary      Отмена удаления аккаунта
+// @Description  Отменяет запланированное удаление аккаунта, если срок еще не истек.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {object}  models.SuccessResponse  "Удаление отменено"
+// @Failure      404  {object}  models.ErrorResponse    "Удаление не запланировано"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при отмене удаления"
+// @Router       /me/delete [delete]
+func CancelAccountDeletion(service *services.AccountService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.CancelDeletion(ctx, payload.Sub); err != nil {
+			respondAccountError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Удаление аккаунта отменено",
+		})
+	}
+}
@@ -42,12 +42,15 @@ func DeleteUser(userService *services.UserService) gin.HandlerFunc {
 				Error:   "invalid argument",
 				Message: "Передан некорректный user_id",
 			})
+			return
 		}
 
+		payload := c.MustGet("userPayload").(*models.Payload)
+
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		err = userService.DeleteUser(ctx, userId)
+		err = userService.DeleteUser(ctx, payload.Sub, userId)
 		if err != nil {
 			switch {
 			case errors.Is(err, services.ErrUserNotFound):
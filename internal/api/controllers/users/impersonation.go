@@ -0,0 +1,172 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondImpersonationError отвечает на ошибки входа от имени пользователя.
+func respondImpersonationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrImpersonationNested):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Недостаточно прав",
+		})
+	case errors.Is(err, services.ErrImpersonateSelf), errors.Is(err, services.ErrImpersonationReasonMissing):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный запрос на вход от имени пользователя",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пользователь не найден",
+		})
+	case errors.Is(err, services.ErrImpersonationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Сессия не найдена или уже завершена",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при входе от имени пользователя",
+		})
+	}
+}
+
+// StartImpersonation Вход от имени пользователя
+// @Summary      Вход от имени пользователя
+// @Description  Выдает access токен от имени пользователя с ролью ниже своей, чтобы увидеть сервис его глазами.
+// @Description  Токен содержит claim act с id разработчика, по умолчанию разрешает только запросы на чтение и не продлевается.
+// @Description  Начало и завершение входа записываются в журнал.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                            true  "Bearer токен" default(Bearer )
+// @Param        user_id        path    int                               true  "ID пользователя"
+// @Param        input          body    models.StartImpersonationRequest  true  "Причина и режим доступа"
+// @Success      200  {object}  models.ImpersonationResponse  "Токен от имени пользователя"
+// @Failure      400  {object}  models.ErrorResponse          "Некорректный запрос"
+// @Failure      403  {object}  models.ErrorResponse          "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse          "Пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse          "Ошибка при входе от имени пользователя"
+// @Router       /admin/impersonate/{user_id} [post]
+func StartImpersonation(service *services.ImpersonationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный id пользователя",
+			})
+			return
+		}
+
+		var req models.StartImpersonationRequest
+		if err = c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		resp, err := service.Start(ctx, payload, userId, req)
+		if err != nil {
+			respondImpersonationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// EndImpersonation Завершение входа от имени пользователя
+// @Summary      Завершение входа от имени пользователя
+// @Description  Завершает сессию входа от имени пользователя до истечения токена. Выданный токен перестает приниматься.
+// @Description  Завершить можно только свою сессию, чужую - с правом impersonations:manage.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID сессии входа"
+// @Success      200  {object}  models.ImpersonationSession  "Завершенная сессия"
+// @Failure      400  {object}  models.ErrorResponse         "Некорректный id"
+// @Failure      403  {object}  models.ErrorResponse         "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse         "Сессия не найдена, уже завершена или начата другим"
+// @Failure      500  {object}  models.ErrorResponse         "Ошибка при завершении сессии"
+// @Router       /admin/impersonations/{id}/end [post]
+func EndImpersonation(service *services.ImpersonationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		sessionId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный id сессии",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		session, err := service.End(ctx, payload, sessionId)
+		if err != nil {
+			respondImpersonationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, session)
+	}
+}
+
+// GetImpersonations История входов от имени пользователей
+// @Summary      История входов от имени пользователей
+// @Description  Возвращает последние входы от имени пользователей: кто, к кому, зачем, когда начал и закончил.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        user_id        query   int     false  "Только входы от имени этого пользователя"
+// @Param        limit          query   int     false  "Максимальное количество записей"  default(50)
+// @Success      200  {array}   models.ImpersonationSession  "Сессии, новые первыми"
+// @Failure      403  {object}  models.ErrorResponse         "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse         "Ошибка при получении истории"
+// @Router       /admin/impersonations [get]
+func GetImpersonations(service *services.ImpersonationService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, _ := strconv.ParseInt(c.DefaultQuery("user_id", "0"), 10, 64)
+		limit, _ := strconv.Atoi(c.DefaultQuery("limit", "50"))
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		sessions, err := service.GetSessions(ctx, userId, limit)
+		if err != nil {
+			respondImpersonationError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, sessions)
+	}
+}
@@ -0,0 +1,117 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rosterMaxFileSize - максимальный размер файла со списком студентов.
+const rosterMaxFileSize = 10 << 20
+
+// ImportStudents Загрузка списка студентов
+// @Summary      Загрузка списка студентов
+// @Description  Загружает список студентов из CSV (разделитель "," или ";") или XLSX (первый лист) в реестр, по которому проходит регистрация.
+// @Description  Первая строка - заголовки: book_id (номер зачетной книжки), fio (ФИО) или surname, name, middle_name, birth_date (дата рождения, ДД.ММ.ГГГГ или ГГГГ-ММ-ДД), student_group (группа). Допускаются русские названия колонок.
+// @Description  При dry_run=true ничего не меняет и возвращает построчный отчет: insert, update, unchanged, conflict, invalid.
+// @Description  Иначе применяет все изменения в одной транзакции; если есть невалидные или конфликтующие строки, не применяет ничего и возвращает 422 с отчетом.
+// @Tags         admin
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header    string  true   "Bearer токен" default(Bearer )
+// @Param        file           formData  file    true   "Файл .csv или .xlsx"
+// @Param        dry_run        query     bool    false  "Только проверить файл"  default(false)
+// @Success      200  {object}  models.RosterImportReport  "Отчет об импорте"
+// @Failure      400  {object}  models.ErrorResponse       "Нет файла, неподдерживаемый формат, нет обязательных колонок или слишком много строк"
+// @Failure      403  {object}  models.ErrorResponse       "Недостаточно прав"
+// @Failure      413  {object}  models.ErrorResponse       "Слишком большой файл"
+// @Failure      422  {object}  models.RosterImportReport  "В файле есть ошибки, изменения не применены"
+// @Failure      500  {object}  models.ErrorResponse       "Ошибка при импорте"
+// @Router       /admin/students/import [post]
+func ImportStudents(service *services.RosterService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Не найден файл в поле file",
+			})
+			return
+		}
+		if fileHeader.Size > rosterMaxFileSize {
+			c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{
+				Error:   "file too large",
+				Message: "Файл должен быть не больше 10 МБ",
+			})
+			return
+		}
+
+		file, err := fileHeader.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Не удалось прочитать файл",
+			})
+			return
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, rosterMaxFileSize))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Не удалось прочитать файл",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		report, err := service.Import(ctx, payload.Sub, fileHeader.Filename, data, dryRun)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrRosterHasErrors):
+				c.JSON(http.StatusUnprocessableEntity, report)
+			case errors.Is(err, helpers.ErrUnsupportedSpreadsheet):
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Поддерживаются только файлы .csv и .xlsx",
+				})
+			case errors.Is(err, helpers.ErrEmptySpreadsheet):
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Файл пустой",
+				})
+			case errors.Is(err, services.ErrRosterMissingColumns), errors.Is(err, services.ErrRosterTooManyRows),
+				errors.Is(err, helpers.ErrSpreadsheetTooLarge):
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Некорректная структура файла",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при импорте студентов",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
@@ -0,0 +1,190 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMyPoints Баллы и история начислений текущего пользователя
+// @Summary      Баллы и история начислений
+// @Description  Возвращает сумму баллов и записи журнала баллов, новые первыми: начисления за выполненные события,
+// @Description  списания при снятии отметки, пересчет при изменении баллов события и ручные изменения с комментарием.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        limit          query   int     false  "Максимальное количество записей (до 500)"  default(50)
+// @Param        offset         query   int     false  "Сколько записей пропустить"  default(0)
+// @Success      200  {object}  models.PointsHistoryResponse  "Баллы и история"
+// @Failure      500  {object}  models.ErrorResponse          "Ошибка при получении баллов"
+// @Router       /me/points [get]
+func GetMyPoints(service *services.PointsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		getPoints(c, service, payload.Sub)
+	}
+}
+
+// GetUserPoints Баллы и история начислений пользователя
+// @Summary      Баллы и история начислений пользователя
+// @Description  Возвращает сумму баллов пользователя и записи журнала баллов, новые первыми.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        user_id        path    int     true   "ID пользователя"
+// @Param        limit          query   int     false  "Максимальное количество записей (до 500)"  default(50)
+// @Param        offset         query   int     false  "Сколько записей пропустить"  default(0)
+// @Success      200  {object}  models.PointsHistoryResponse  "Баллы и история"
+// @Failure      400  {object}  models.ErrorResponse          "Некорректный user_id"
+// @Failure      403  {object}  models.ErrorResponse          "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse          "Пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse          "Ошибка при получении баллов"
+// @Router       /admin/users/{user_id}/points [get]
+func GetUserPoints(service *services.PointsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Передан некорректный user_id",
+			})
+			return
+		}
+
+		getPoints(c, service, userId)
+	}
+}
+
+func getPoints(c *gin.Context, service *services.PointsService, userId int64) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultPointsHistoryLimit)))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	history, err := service.GetHistory(ctx, userId, limit, offset)
+	if err != nil {
+		respondPointsError(c, err, "Ошибка при получении баллов")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// AdjustUserPoints Ручное изменение баллов пользователя
+// @Summary      Ручное изменение баллов
+// @Description  Добавляет в журнал баллов запись reason=manual: начисление (delta > 0) или списание (delta < 0).
+// @Description  Комментарий обязателен и виден пользователю в истории баллов.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                      true  "Bearer токен" default(Bearer )
+// @Param        user_id        path    int                         true  "ID пользователя"
+// @Param        input          body    models.AdjustPointsRequest  true  "Изменение баллов"
+// @Success      200  {object}  models.PointsTransaction  "Запись журнала"
+// @Failure      400  {object}  models.ErrorResponse      "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse      "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse      "Пользователь не найден"
+// @Failure      500  {object}  models.ErrorResponse      "Ошибка при изменении баллов"
+// @Router       /admin/users/{user_id}/points [post]
+func AdjustUserPoints(service *services.PointsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		userId, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Передан некорректный user_id",
+			})
+			return
+		}
+
+		var body models.AdjustPointsRequest
+		if err = c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		transaction, err := service.AdjustPoints(ctx, payload.Sub, userId, body)
+		if err != nil {
+			respondPointsError(c, err, "Ошибка при изменении баллов")
+			return
+		}
+
+		c.JSON(http.StatusOK, transaction)
+	}
+}
+
+// ReconcilePoints Сверка баллов
+// @Summary      Сверка баллов
+// @Description  Сверяет журнал баллов с выполненными событиями (баллы за событие должны совпадать с текущими баллами события)
+// @Description  и суммы в user_points с журналом. Возвращает найденные расхождения; без dry_run исправляет их записями reconcile
+// @Description  и пересчетом сумм. То же можно выполнить командой go run ./cmd/reconcile.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true   "Bearer токен" default(Bearer )
+// @Param        dry_run        query   bool    false  "Только найти расхождения"  default(false)
+// @Success      200  {object}  models.PointsReconcileReport  "Отчет о сверке"
+// @Failure      403  {object}  models.ErrorResponse          "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse          "Ошибка при сверке баллов"
+// @Router       /admin/points/reconcile [post]
+func ReconcilePoints(service *services.PointsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		report, err := service.Reconcile(ctx, payload.Sub, dryRun)
+		if err != nil {
+			respondPointsError(c, err, "Ошибка при сверке баллов")
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+func respondPointsError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidPointsAdjustment):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректное изменение баллов: delta не должна быть 0, комментарий обязателен",
+		})
+	case errors.Is(err, services.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Пользователь не найден",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: message,
+		})
+	}
+}
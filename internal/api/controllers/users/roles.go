@@ -0,0 +1,195 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondRoleError отвечает на ошибки управления ролями.
+func respondRoleError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrForbidden), errors.Is(err, services.ErrPermissionEscalate):
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Недостаточно прав",
+		})
+	case errors.Is(err, services.ErrRoleNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Такой роли не существует",
+		})
+	case errors.Is(err, services.ErrRoleAlreadyExists):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Роль с таким кодом или уровнем уже существует",
+		})
+	case errors.Is(err, services.ErrUnknownPermission), errors.Is(err, services.ErrInvalidRoleLevel):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректные данные роли",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при работе с ролями",
+		})
+	}
+}
+
+// GetPermissions Список прав
+// @Summary      Список прав
+// @Description  Возвращает все права, которые можно выдать ролям.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {array}   models.Permission     "Список прав"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении прав"
+// @Router       /admin/permissions [get]
+func GetPermissions(service *services.RolesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		permissions, err := service.GetPermissions(ctx)
+		if err != nil {
+			respondRoleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, permissions)
+	}
+}
+
+// GetRoles Список ролей
+// @Summary      Список ролей
+// @Description  Возвращает все роли вместе с выданными им правами.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {array}   models.Role           "Список ролей"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении ролей"
+// @Router       /admin/roles [get]
+func GetRoles(service *services.RolesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		roles, err := service.GetRoles(ctx)
+		if err != nil {
+			respondRoleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, roles)
+	}
+}
+
+// CreateRole Создание роли
+// @Summary      Создание роли
+// @Description  Создает роль с уровнем ниже вашего. Выдать роли можно только те права, которые есть у вас самих.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                    true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CreateRoleRequest  true  "Код, название, уровень и права роли"
+// @Success      200  {object}  models.Role           "Созданная роль"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректный JSON или неизвестное право"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      409  {object}  models.ErrorResponse  "Роль с таким кодом или уровнем уже существует"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при создании роли"
+// @Router       /admin/roles [post]
+func CreateRole(service *services.RolesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var req models.CreateRoleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		role, err := service.CreateRole(ctx, payload, req)
+		if err != nil {
+			respondRoleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, role)
+	}
+}
+
+// SetRolePermissions Изменение прав роли
+// @Summary      Изменение прав роли
+// @Description  Заменяет набор прав роли с уровнем ниже вашего. Access токены пользователей с этой ролью отзываются,
+// @Description  новые права применяются после обновления токенов.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                            true  "Bearer токен" default(Bearer )
+// @Param        level          path    int                               true  "Уровень роли"
+// @Param        input          body    models.SetRolePermissionsRequest  true  "Новый набор прав"
+// @Success      200  {object}  models.SuccessResponse  "Права роли обновлены"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный JSON или неизвестное право"
+// @Failure      403  {object}  models.ErrorResponse    "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse    "Роль не найдена"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при изменении прав"
+// @Router       /admin/roles/{level}/permissions [put]
+func SetRolePermissions(service *services.RolesService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		level, err := strconv.ParseInt(c.Param("level"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный уровень роли",
+			})
+			return
+		}
+
+		var req models.SetRolePermissionsRequest
+		if err = c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err = service.SetRolePermissions(ctx, payload, level, req.Permissions); err != nil {
+			respondRoleError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Права роли обновлены",
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Rollover Переход на новый учебный год
+// @Summary      Переход на новый учебный год
+// @Description  Выпускает группы graduating_groups: студенты реестра и пользователи этих групп получают archived_at, перестают попадать в лидерборд, но сохраняют выполненные мероприятия.
+// @Description  Затем переводит действующих студентов и пользователей по таблице mappings (from -> to). Выпуск выполняется раньше перевода, поэтому выпускающаяся группа может сразу принять следующий курс.
+// @Description  Группа to, которой еще нет, создается на направлении группы from. При dry_run=true ничего не меняет и возвращает тот же отчет.
+// @Description  То же можно выполнить командой go run ./cmd/rollover -file mapping.json.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                  true   "Bearer токен" default(Bearer )
+// @Param        input          body    models.RolloverRequest  true   "Таблица перевода и выпускающиеся группы"
+// @Param        dry_run        query   bool                    false  "Только посчитать изменения"  default(false)
+// @Success      200  {object}  models.RolloverReport  "Отчет о переводе"
+// @Failure      400  {object}  models.ErrorResponse   "Некорректная таблица перевода"
+// @Failure      403  {object}  models.ErrorResponse   "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse   "Группа не найдена"
+// @Failure      500  {object}  models.ErrorResponse   "Ошибка при переводе"
+// @Router       /admin/rollover [post]
+func Rollover(service *services.RolloverService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		dryRun, _ := strconv.ParseBool(c.DefaultQuery("dry_run", "false"))
+
+		var body models.RolloverRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		report, err := service.Rollover(ctx, payload.Sub, body, dryRun)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidRollover):
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Некорректная таблица перевода групп",
+				})
+			case errors.Is(err, services.ErrStudentGroupNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Группа не найдена",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при переходе на новый учебный год",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
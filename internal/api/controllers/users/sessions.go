@@ -0,0 +1,133 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetSessions Получение списка активных сессий пользователя
+// @Summary      Список активных сессий
+// @Description  Возвращает все активные сессии (устройства) текущего пользователя.
+// @Description  Сессия, с которой выполнен запрос, помечена полем current.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {array}   models.Session        "Список сессий"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при получении сессий"
+// @Router       /me/sessions [get]
+func GetSessions(service *services.SessionsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		sessions, err := service.GetSessions(ctx, payload.Sub, payload.Sid)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при получении сессий",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, sessions)
+	}
+}
+
+// RevokeSession Завершение одной сессии пользователя
+// @Summary      Завершить сессию
+// @Description  Удаляет refresh токен выбранной сессии. Устройство будет разлогинено после истечения access токена.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        id             path    int     true  "ID сессии"
+// @Success      200  {object}  models.SuccessResponse  "Сессия завершена"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный ID сессии"
+// @Failure      404  {object}  models.ErrorResponse    "Сессия не найдена"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при завершении сессии"
+// @Router       /me/sessions/{id} [delete]
+func RevokeSession(service *services.SessionsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		sessionId, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный формат ID сессии",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		err = service.RevokeSession(ctx, payload.Sub, sessionId)
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrSessionNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Сессия не найдена",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при завершении сессии",
+				})
+			}
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Сессия завершена",
+		})
+	}
+}
+
+// RevokeAllSessions Завершение всех сессий пользователя
+// @Summary      Завершить все сессии
+// @Description  Удаляет refresh токены всех сессий пользователя, включая текущую.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {object}  models.RevokeSessionsResponse  "Сессии завершены"
+// @Failure      500  {object}  models.ErrorResponse           "Ошибка при завершении сессий"
+// @Router       /me/sessions [delete]
+func RevokeAllSessions(service *services.SessionsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		revoked, err := service.RevokeAllSessions(ctx, payload.Sub)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Ошибка при завершении сессий",
+			})
+			return
+		}
+
+		c.JSON(http.StatusOK, models.RevokeSessionsResponse{
+			Successful: true,
+			Revoked:    revoked,
+		})
+	}
+}
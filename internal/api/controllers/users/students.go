@@ -0,0 +1,189 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondStudentError отвечает на ошибки изменения реестра студентов.
+func respondStudentError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrStudentNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Студент не найден",
+		})
+	case errors.Is(err, services.ErrStudentAlreadyExists):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Студент с таким номером зачетки уже существует",
+		})
+	case errors.Is(err, services.ErrStudentGroupNotFound):
+		c.JSON(http.StatusUnprocessableEntity, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Такой группы нет, сначала добавьте ее в структуру институтов",
+		})
+	case errors.Is(err, services.ErrInvalidStudentData):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректные данные студента",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при изменении реестра студентов",
+		})
+	}
+}
+
+// parseBookIdParam читает номер зачетки из пути, при ошибке отвечает 400.
+func parseBookIdParam(c *gin.Context) (int64, bool) {
+	bookId, err := strconv.ParseInt(c.Param("book_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Некорректный номер зачетки",
+		})
+		return 0, false
+	}
+	return bookId, true
+}
+
+// CreateStudent Добавление студента
+// @Summary      Добавление студента
+// @Description  Добавляет студента в реестр, после чего он может зарегистрироваться через /auth/check.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                       true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.CreateStudentRequest  true  "Данные студента"
+// @Success      201  {object}  models.Student        "Добавленный студент"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      409  {object}  models.ErrorResponse  "Студент с таким номером зачетки уже существует"
+// @Failure      422  {object}  models.ErrorResponse  "Группа не найдена"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при добавлении студента"
+// @Router       /admin/students [post]
+func CreateStudent(service *services.StudentsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.CreateStudentRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		student, err := service.CreateStudent(ctx, payload.Sub, body)
+		if err != nil {
+			respondStudentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusCreated, student)
+	}
+}
+
+// UpdateStudent Изменение студента
+// @Summary      Изменение студента
+// @Description  Меняет данные студента в реестре. Пустые поля не меняются. Аккаунт, зарегистрированный по этой зачетке, получает те же ФИО, дату рождения, группу и номер зачетки.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                       true  "Bearer токен" default(Bearer )
+// @Param        book_id        path    int                          true  "Номер зачетной книжки"
+// @Param        input          body    models.UpdateStudentRequest  true  "Новые значения полей"
+// @Success      200  {object}  models.Student        "Обновленный студент"
+// @Failure      400  {object}  models.ErrorResponse  "Некорректные данные"
+// @Failure      403  {object}  models.ErrorResponse  "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse  "Студент не найден"
+// @Failure      409  {object}  models.ErrorResponse  "Новый номер зачетки уже занят"
+// @Failure      422  {object}  models.ErrorResponse  "Группа не найдена"
+// @Failure      500  {object}  models.ErrorResponse  "Ошибка при изменении студента"
+// @Router       /admin/students/{book_id} [patch]
+func UpdateStudent(service *services.StudentsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		bookId, ok := parseBookIdParam(c)
+		if !ok {
+			return
+		}
+
+		var body models.UpdateStudentRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		student, err := service.UpdateStudent(ctx, payload.Sub, bookId, body)
+		if err != nil {
+			respondStudentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, student)
+	}
+}
+
+// DeleteStudent Удаление студента
+// @Summary      Удаление студента
+// @Description  Удаляет студента из реестра. Аккаунт, зарегистрированный по его зачетке, сохраняется вместе с выполненными событиями, но отвязывается от зачетки, а его сессии завершаются.
+// @Tags         admin
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Param        book_id        path    int     true  "Номер зачетной книжки"
+// @Success      200  {object}  models.SuccessResponse  "Студент удален"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный номер зачетки"
+// @Failure      403  {object}  models.ErrorResponse    "Недостаточно прав"
+// @Failure      404  {object}  models.ErrorResponse    "Студент не найден"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при удалении студента"
+// @Router       /admin/students/{book_id} [delete]
+func DeleteStudent(service *services.StudentsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		bookId, ok := parseBookIdParam(c)
+		if !ok {
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.DeleteStudent(ctx, payload.Sub, bookId); err != nil {
+			respondStudentError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Студент удален",
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package users
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondTwoFactorError отвечает на ошибки управления двухфакторной аутентификацией.
+func respondTwoFactorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Неверный код подтверждения",
+		})
+	case errors.Is(err, services.ErrInvalidPassword):
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Неправильный пароль",
+		})
+	case errors.Is(err, services.ErrMFAAlreadyEnabled):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Двухфакторная аутентификация уже включена",
+		})
+	case errors.Is(err, services.ErrMFANotEnabled), errors.Is(err, services.ErrMFASetupNotStarted):
+		c.JSON(http.StatusConflict, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Двухфакторная аутентификация не настроена",
+		})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+			Error:   err.Error(),
+			Message: "Ошибка при работе с двухфакторной аутентификацией",
+		})
+	}
+}
+
+// GetTwoFactorStatus Состояние двухфакторной аутентификации
+// @Summary      Состояние 2FA
+// @Description  Показывает, включена ли двухфакторная аутентификация, обязательна ли она для роли пользователя
+// @Description  и сколько осталось кодов восстановления.
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {object}  models.TwoFactorStatus  "Состояние 2FA"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при получении данных"
+// @Router       /me/2fa [get]
+func GetTwoFactorStatus(service *services.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		status, err := service.GetStatus(ctx, payload.Sub, payload.RoleLevel)
+		if err != nil {
+			respondTwoFactorError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// SetupTwoFactor Начало подключения двухфакторной аутентификации
+// @Summary      Получить секрет для приложения-аутентификатора
+// @Description  Генерирует новый TOTP секрет и otpauth:// URI для QR кода. 2FA включится после подтверждения кодом (/me/2fa/enable).
+// @Tags         user
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string  true  "Bearer токен" default(Bearer )
+// @Success      200  {object}  models.TwoFactorSetupResponse  "Секрет и URI для QR кода"
+// @Failure      409  {object}  models.ErrorResponse           "2FA уже включена"
+// @Failure      500  {object}  models.ErrorResponse           "Ошибка при генерации секрета"
+// @Router       /me/2fa/setup [post]
+func SetupTwoFactor(service *services.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		resp, err := service.Setup(ctx, payload.Sub)
+		if err != nil {
+			respondTwoFactorError(c, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// EnableTwoFactor Включение двухфакторной аутентификации
+// @Summary      Подтвердить и включить 2FA
+// @Description  Проверяет код из приложения-аутентификатора и включает 2FA. Возвращает одноразовые коды восстановления —
+// @Description  они показываются только один раз. Остальные сессии пользователя завершаются.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                       true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.TwoFactorCodeRequest  true  "Код из приложения"
+// @Success      200  {object}  models.TwoFactorRecoveryCodesResponse  "Коды восстановления"
+// @Failure      400  {object}  models.ErrorResponse                   "Некорректный JSON или неверный код"
+// @Failure      409  {object}  models.ErrorResponse                   "2FA уже включена или секрет не запрошен"
+// @Failure      500  {object}  models.ErrorResponse                   "Ошибка при включении 2FA"
+// @Router       /me/2fa/enable [post]
+func EnableTwoFactor(service *services.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.TwoFactorCodeRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		resp, err := service.Enable(ctx, payload.Sub, payload.Sid, body.Code)
+		if err != nil {
+			respondTwoFactorError(c, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, resp)
+	}
+}
+
+// DisableTwoFactor Отключение двухфакторной аутентификации
+// @Summary      Отключить 2FA
+// @Description  Отключает 2FA. Требует текущий пароль и код из приложения или код восстановления.
+// @Description  Пользователи с ролью администратора и выше без 2FA теряют доступ к /admin.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                          true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.TwoFactorDisableRequest  true  "Пароль и код"
+// @Success      200  {object}  models.SuccessResponse  "2FA отключена"
+// @Failure      400  {object}  models.ErrorResponse    "Некорректный JSON или неверный код"
+// @Failure      401  {object}  models.ErrorResponse    "Неправильный пароль"
+// @Failure      409  {object}  models.ErrorResponse    "2FA не включена"
+// @Failure      500  {object}  models.ErrorResponse    "Ошибка при отключении 2FA"
+// @Router       /me/2fa/disable [post]
+func DisableTwoFactor(service *services.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.TwoFactorDisableRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := service.Disable(ctx, payload.Sub, body.Password, body.Code); err != nil {
+			respondTwoFactorError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, models.SuccessResponse{
+			Successful: true,
+			Message:    "Двухфакторная аутентификация отключена",
+		})
+	}
+}
+
+// RegenerateRecoveryCodes Перевыпуск кодов восстановления
+// @Summary      Новые коды восстановления
+// @Description  Выдает новый набор кодов восстановления, старые перестают действовать.
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        Authorization  header  string                       true  "Bearer токен" default(Bearer )
+// @Param        input          body    models.TwoFactorCodeRequest  true  "Код из приложения или код восстановления"
+// @Success      200  {object}  models.TwoFactorRecoveryCodesResponse  "Новые коды восстановления"
+// @Failure      400  {object}  models.ErrorResponse                   "Некорректный JSON или неверный код"
+// @Failure      409  {object}  models.ErrorResponse                   "2FA не включена"
+// @Failure      500  {object}  models.ErrorResponse                   "Ошибка при генерации кодов"
+// @Router       /me/2fa/recovery_codes [post]
+func RegenerateRecoveryCodes(service *services.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var body models.TwoFactorCodeRequest
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Некорректный JSON",
+			})
+			return
+		}
+
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		resp, err := service.RegenerateRecoveryCodes(ctx, payload.Sub, body.Code)
+		if err != nil {
+			respondTwoFactorError(c, err)
+			return
+		}
+
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, resp)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bobri/internal/api/services"
 	"bobri/internal/models"
 	"context"
+	"errors"
 	"net/http"
 	"time"
 
@@ -13,6 +14,7 @@ import (
 // UpdateUser обновляет данные пользователя
 // @Summary Обновление данных пользователя
 // @Description Обновляет данные пользователя с проверкой прав доступа. Только пользователи с более высоким уровнем прав могут изменять данные пользователей с более низким уровнем прав.
+// @Description Требует право users:write, для смены роли - дополнительно users:manage_roles.
 // @Tags admin
 // @Accept json
 // @Produce json
@@ -21,6 +23,7 @@ import (
 // @Success 200 {object} models.UpdateUserResponse "Успешное обновление данных пользователя"
 // @Failure 400 {object} models.ErrorResponse "Ошибка в формате JSON"
 // @Failure 403 {object} models.ErrorResponse "Недостаточно прав"
+// @Failure 404 {object} models.ErrorResponse "Такой роли или группы не существует"
 // @Failure 500 {object} models.ErrorResponse "Внутренняя ошибка сервера"
 // @Router /admin/update_user [patch]
 func UpdateUser(service *services.UserService) gin.HandlerFunc {
@@ -49,20 +52,30 @@ func UpdateUser(service *services.UserService) gin.HandlerFunc {
 		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 		defer cancel()
 
-		resp, err := service.UpdateUser(ctx, payload.RoleLevel, req) // TODO redactor admin
+		resp, err := service.UpdateUser(ctx, payload, req)
 		if err != nil {
-			if err.Error() == "недостаточно прав" {
+			switch {
+			case errors.Is(err, services.ErrForbidden):
 				c.JSON(http.StatusForbidden, models.ErrorResponse{
 					Error:   err.Error(),
 					Message: "Недостаточно прав",
 				})
-				return
+			case errors.Is(err, services.ErrRoleNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Такой роли не существует",
+				})
+			case errors.Is(err, services.ErrStudentGroupNotFound):
+				c.JSON(http.StatusNotFound, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Такой группы не существует",
+				})
+			default:
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Ошибка при обновлении пользователя",
+				})
 			}
-
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-				Error:   err.Error(),
-				Message: "Ошибка при обновлении пользователя",
-			})
 			return
 		}
 
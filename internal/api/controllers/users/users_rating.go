@@ -17,6 +17,9 @@ import (
 // @Produce      json
 // @Security     BearerAuth
 // @Param        limit   query    int    false   "Максимальное количество пользователей в выдаче"  default(50)
+// @Param        institute_id      query  int  false  "Только студенты института"
+// @Param        study_id          query  int  false  "Только студенты направления"
+// @Param        student_group_id  query  int  false  "Только студенты группы"
 // @Success      200     {array}  models.UserWithPoints   "Список пользователей с их количеством очков"
 // @Failure      500     {object} models.ErrorResponse    "Ошибка при получении лидерборда"
 // @Router       /leaderboard [get]
@@ -25,10 +28,15 @@ func GetLeaderboard(service *services.UserService) gin.HandlerFunc {
 		limitStr := c.DefaultQuery("limit", "50")
 		limit, _ := strconv.Atoi(limitStr)
 
+		filter := models.LeaderboardFilter{Limit: limit}
+		filter.InstituteId, _ = strconv.ParseInt(c.Query("institute_id"), 10, 64)
+		filter.StudyId, _ = strconv.ParseInt(c.Query("study_id"), 10, 64)
+		filter.StudentGroupId, _ = strconv.ParseInt(c.Query("student_group_id"), 10, 64)
+
 		ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 		defer cancel()
 
-		users, err := service.GetLeaderboard(ctx, limit)
+		users, err := service.GetLeaderboard(ctx, filter)
 		if err != nil {
 			c.JSON(500, models.ErrorResponse{
 				Error:   err.Error(),
@@ -0,0 +1,86 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// AuditRepository отвечает за журнал действий администраторов (audit_log).
+// Записи добавляются в той же транзакции, что и само действие.
+type AuditRepository struct {
+	db DBTX
+}
+
+// NewAuditRepository создает новый экземпляр AuditRepository.
+func NewAuditRepository(db DBTX) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *AuditRepository) WithDB(db DBTX) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// CreateEntry добавляет запись в журнал. before и after - JSON, nil сохраняется как NULL.
+func (r *AuditRepository) CreateEntry(ctx context.Context, actorId int64, action, targetType, targetId string, before, after []byte) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO audit_log (actor_id, action, target_type, target_id, before, after)
+         VALUES ($1, $2, $3, $4, $5, $6)`,
+		actorId,
+		action,
+		targetType,
+		targetId,
+		before,
+		after,
+	)
+	if err != nil {
+		return fmt.Errorf("could not create audit entry: %w", err)
+	}
+
+	return nil
+}
+
+// GetEntries возвращает записи журнала по фильтру, новые первыми.
+func (r *AuditRepository) GetEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	builder := sq.Select("id", "actor_id", "action", "target_type", "target_id", "before", "after", "created_at").
+		From("audit_log")
+
+	if filter.ActorId != 0 {
+		builder = builder.Where(sq.Eq{"actor_id": filter.ActorId})
+	}
+	if filter.Action != "" {
+		builder = builder.Where(sq.Eq{"action": filter.Action})
+	}
+	if filter.TargetType != "" {
+		builder = builder.Where(sq.Eq{"target_type": filter.TargetType})
+	}
+	if filter.TargetId != "" {
+		builder = builder.Where(sq.Eq{"target_id": filter.TargetId})
+	}
+	if filter.From != nil {
+		builder = builder.Where(sq.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		builder = builder.Where(sq.Lt{"created_at": *filter.To})
+	}
+
+	builder = builder.OrderBy("created_at DESC", "id DESC").
+		Limit(uint64(filter.Limit)).
+		Offset(uint64(filter.Offset))
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not convert to sql query: %w", err)
+	}
+
+	entries := []models.AuditEntry{}
+	if err = pgxscan.Select(ctx, r.db, &entries, query, args...); err != nil {
+		return nil, fmt.Errorf("could not get audit entries: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// AuthThrottleRepository отвечает за счетчики попыток входа и блокировки (таблица auth_throttle).
+type AuthThrottleRepository struct {
+	db DBTX
+}
+
+// NewAuthThrottleRepository создает новый экземпляр AuthThrottleRepository.
+func NewAuthThrottleRepository(db DBTX) *AuthThrottleRepository {
+	return &AuthThrottleRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую указанный DBTX (tx или pool).
+func (r *AuthThrottleRepository) WithDB(db DBTX) *AuthThrottleRepository {
+	return &AuthThrottleRepository{db: db}
+}
+
+// GetLockedUntil возвращает время окончания действующей блокировки ключа.
+// Если блокировки нет - возвращает нулевое время.
+func (r *AuthThrottleRepository) GetLockedUntil(ctx context.Context, scope, keyType, key string) (time.Time, error) {
+	var lockedUntil time.Time
+
+	err := r.db.QueryRow(ctx,
+		`SELECT locked_until
+         FROM auth_throttle
+         WHERE scope = $1 AND key_type = $2 AND key = $3 AND locked_until > NOW()`,
+		scope, keyType, key,
+	).Scan(&lockedUntil)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, nil
+		}
+		return time.Time{}, fmt.Errorf("could not get lock: %w", err)
+	}
+
+	return lockedUntil, nil
+}
+
+// RegisterAttempt увеличивает счетчик попыток ключа в текущем окне.
+// Если окно истекло, счетчик начинается заново; если ключ сутки не блокировался,
+// сбрасывается и счетчик блокировок.
+func (r *AuthThrottleRepository) RegisterAttempt(ctx context.Context, scope, keyType, key string, window time.Duration) (models.ThrottleState, error) {
+	var state models.ThrottleState
+
+	err := pgxscan.Get(ctx, r.db, &state,
+		`INSERT INTO auth_throttle (scope, key_type, key, failures, window_started_at, updated_at)
+         VALUES ($1, $2, $3, 1, NOW(), NOW())
+         ON CONFLICT (scope, key_type, key)
+         DO UPDATE SET
+             failures = CASE WHEN auth_throttle.window_started_at < NOW() - $4 * INTERVAL '1 second'
+                             THEN 1 ELSE auth_throttle.failures + 1 END,
+             window_started_at = CASE WHEN auth_throttle.window_started_at < NOW() - $4 * INTERVAL '1 second'
+                                      THEN NOW() ELSE auth_throttle.window_started_at END,
+             lockouts = CASE WHEN auth_throttle.updated_at < NOW() - INTERVAL '1 day'
+                             THEN 0 ELSE auth_throttle.lockouts END,
+             updated_at = NOW()
+         RETURNING failures, lockouts, locked_until`,
+		scope, keyType, key, window.Seconds(),
+	)
+	if err != nil {
+		return state, fmt.Errorf("could not register attempt: %w", err)
+	}
+
+	return state, nil
+}
+
+// Lock блокирует ключ до указанного времени и начинает новое окно попыток.
+func (r *AuthThrottleRepository) Lock(ctx context.Context, scope, keyType, key string, until time.Time) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE auth_throttle
+         SET locked_until = $4, lockouts = lockouts + 1, failures = 0, window_started_at = NOW()
+         WHERE scope = $1 AND key_type = $2 AND key = $3`,
+		scope, keyType, key, until,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not lock key: %w", err)
+	}
+
+	return nil
+}
+
+// Reset удаляет счетчик ключа (например, после успешного входа).
+func (r *AuthThrottleRepository) Reset(ctx context.Context, scope, keyType, key string) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM auth_throttle
+         WHERE scope = $1 AND key_type = $2 AND key = $3`,
+		scope, keyType, key,
+	)
+	if err != nil {
+		return fmt.Errorf("could not reset attempts: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteByKey снимает все блокировки и счетчики ключа во всех сценариях.
+func (r *AuthThrottleRepository) DeleteByKey(ctx context.Context, key string) (pgconn.CommandTag, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM auth_throttle WHERE key = $1`,
+		key,
+	)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("could not delete locks: %w", err)
+	}
+
+	return tag, nil
+}
+
+// GetActiveLocks возвращает действующие блокировки, начиная с самых долгих.
+func (r *AuthThrottleRepository) GetActiveLocks(ctx context.Context, limit int) ([]models.AuthLock, error) {
+	var locks []models.AuthLock
+
+	err := pgxscan.Select(ctx, r.db, &locks,
+		`SELECT scope, key_type, key, lockouts, locked_until
+         FROM auth_throttle
+         WHERE locked_until > NOW()
+         ORDER BY locked_until DESC
+         LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get locks: %w", err)
+	}
+
+	return locks, nil
+}
@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// CheckinRepository отвечает за окна самостоятельной отметки на событиях и сами отметки.
+type CheckinRepository struct {
+	db DBTX
+}
+
+// NewCheckinRepository создает новый экземпляр CheckinRepository.
+func NewCheckinRepository(db DBTX) *CheckinRepository {
+	return &CheckinRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *CheckinRepository) WithDB(db DBTX) *CheckinRepository {
+	return &CheckinRepository{db: db}
+}
+
+// GetSettings возвращает окно отметки и число отметившихся. Если окно не задано - pgx.ErrNoRows.
+func (r *CheckinRepository) GetSettings(ctx context.Context, eventId int64) (models.CheckinSettings, error) {
+	var settings models.CheckinSettings
+
+	err := pgxscan.Get(ctx, r.db, &settings,
+		`SELECT s.event_id, s.opens_at, s.closes_at,
+		        (SELECT COUNT(*) FROM event_checkins c WHERE c.event_id = s.event_id) AS checked_in_count
+		 FROM event_checkin_settings s WHERE s.event_id = $1`,
+		eventId,
+	)
+	if err != nil {
+		return settings, fmt.Errorf("could not get checkin settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// GetSecret возвращает окно отметки вместе с ключом подписи. Если окно не задано - pgx.ErrNoRows.
+func (r *CheckinRepository) GetSecret(ctx context.Context, eventId int64) (models.CheckinSecret, error) {
+	var secret models.CheckinSecret
+
+	err := pgxscan.Get(ctx, r.db, &secret,
+		`SELECT opens_at, closes_at, secret FROM event_checkin_settings WHERE event_id = $1`,
+		eventId,
+	)
+	if err != nil {
+		return secret, fmt.Errorf("could not get checkin secret: %w", err)
+	}
+
+	return secret, nil
+}
+
+// UpsertSettings задает окно отметки. Ключ подписи сохраняется только при создании,
+// у существующего окна он не меняется, чтобы выданные токены продолжали работать.
+func (r *CheckinRepository) UpsertSettings(ctx context.Context, eventId int64, opensAt, closesAt time.Time, secret []byte) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO event_checkin_settings (event_id, opens_at, closes_at, secret)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (event_id) DO UPDATE
+		 SET opens_at = EXCLUDED.opens_at, closes_at = EXCLUDED.closes_at, updated_at = now()`,
+		eventId, opensAt, closesAt, secret,
+	)
+	if err != nil {
+		return fmt.Errorf("could not save checkin settings: %w", err)
+	}
+
+	return nil
+}
+
+// RotateSecret меняет ключ подписи. Возвращает false, если окно отметки не задано.
+func (r *CheckinRepository) RotateSecret(ctx context.Context, eventId int64, secret []byte) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE event_checkin_settings SET secret = $2, updated_at = now() WHERE event_id = $1`,
+		eventId, secret,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not rotate checkin secret: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// CreateCheckin сохраняет отметку и возвращает ее время. Повторная отметка нарушает первичный ключ (23505).
+func (r *CheckinRepository) CreateCheckin(ctx context.Context, eventId, userId int64, nonce string) (time.Time, error) {
+	var checkedInAt time.Time
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO event_checkins (event_id, user_id, token_nonce) VALUES ($1, $2, $3)
+		 RETURNING checked_in_at`,
+		eventId, userId, nonce,
+	).Scan(&checkedInAt)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("could not create checkin: %w", err)
+	}
+
+	return checkedInAt, nil
+}
+
+// IsCheckedIn проверяет, отмечался ли пользователь на событии.
+func (r *CheckinRepository) IsCheckedIn(ctx context.Context, eventId, userId int64) (bool, error) {
+	var exists bool
+
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM event_checkins WHERE event_id = $1 AND user_id = $2)`,
+		eventId, userId,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("could not check checkin: %w", err)
+	}
+
+	return exists, nil
+}
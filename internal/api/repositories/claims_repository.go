@@ -0,0 +1,177 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// ClaimsRepository отвечает за заявки студентов на баллы (claims) и подтверждения к ним.
+type ClaimsRepository struct {
+	db DBTX
+}
+
+// NewClaimsRepository создает новый экземпляр ClaimsRepository.
+func NewClaimsRepository(db DBTX) *ClaimsRepository {
+	return &ClaimsRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *ClaimsRepository) WithDB(db DBTX) *ClaimsRepository {
+	return &ClaimsRepository{db: db}
+}
+
+// claimColumns - колонки заявки вместе с именем студента и данными события.
+var claimColumns = []string{
+	"c.id", "c.user_id", "u.name as user_name", "u.surname as user_surname",
+	"c.event_id", "e.title as event_title", "e.points as event_points",
+	"c.comment", "c.status", "c.reject_reason", "c.reviewed_by", "c.reviewed_at", "c.created_at",
+}
+
+func claimsSelect() sq.SelectBuilder {
+	return sq.Select(claimColumns...).
+		From("claims c").
+		Join("users u ON u.id = c.user_id").
+		Join("events e ON e.id = c.event_id")
+}
+
+// CreateClaim добавляет заявку на рассмотрение и возвращает ее id.
+// Повторная заявка на то же событие, пока первая не рассмотрена, нарушает claims_pending_uq (23505).
+// На удаленное событие или черновик заявку подать нельзя - pgx.ErrNoRows.
+func (r *ClaimsRepository) CreateClaim(ctx context.Context, userId, eventId int64, comment string) (int64, error) {
+	var id int64
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO claims (user_id, event_id, comment)
+         SELECT $1, id, $3 FROM events
+         WHERE id = $2 AND deleted_at IS NULL AND status <> 'draft'
+         RETURNING id`,
+		userId, eventId, comment,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("could not create claim: %w", err)
+	}
+
+	return id, nil
+}
+
+// AddAttachments добавляет подтверждения к заявке.
+func (r *ClaimsRepository) AddAttachments(ctx context.Context, claimId int64, attachments []models.ClaimAttachmentInput) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+
+	urls := make([]string, len(attachments))
+	names := make([]string, len(attachments))
+	for i, a := range attachments {
+		urls[i], names[i] = a.Url, a.Name
+	}
+
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO claim_attachments (claim_id, url, name)
+         SELECT $1, a.url, a.name FROM unnest($2::text[], $3::text[]) AS a(url, name)`,
+		claimId, urls, names,
+	)
+	if err != nil {
+		return fmt.Errorf("could not add claim attachments: %w", err)
+	}
+
+	return nil
+}
+
+// GetClaims возвращает заявки по фильтру. Заявки одного студента - новые первыми,
+// очередь модерации (без UserId) - старые первыми.
+func (r *ClaimsRepository) GetClaims(ctx context.Context, filter models.ClaimsFilter) ([]models.Claim, error) {
+	builder := claimsSelect()
+
+	if filter.Status != "" {
+		builder = builder.Where(sq.Eq{"c.status": filter.Status})
+	}
+	if filter.UserId != 0 {
+		builder = builder.Where(sq.Eq{"c.user_id": filter.UserId}).OrderBy("c.created_at DESC", "c.id DESC")
+	} else {
+		builder = builder.OrderBy("c.created_at", "c.id")
+	}
+	builder = builder.Limit(uint64(filter.Limit)).Offset(uint64(filter.Offset))
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not convert to sql query: %w", err)
+	}
+
+	claims := []models.Claim{}
+	if err = pgxscan.Select(ctx, r.db, &claims, query, args...); err != nil {
+		return nil, fmt.Errorf("could not get claims: %w", err)
+	}
+
+	return claims, nil
+}
+
+// GetClaim возвращает заявку по id. forUpdate блокирует строку до конца транзакции.
+// Если заявки нет - pgx.ErrNoRows.
+func (r *ClaimsRepository) GetClaim(ctx context.Context, id int64, forUpdate bool) (models.Claim, error) {
+	var claim models.Claim
+
+	builder := claimsSelect().Where(sq.Eq{"c.id": id})
+	if forUpdate {
+		builder = builder.Suffix("FOR UPDATE OF c")
+	}
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return claim, fmt.Errorf("could not convert to sql query: %w", err)
+	}
+
+	if err = pgxscan.Get(ctx, r.db, &claim, query, args...); err != nil {
+		return claim, fmt.Errorf("could not get claim: %w", err)
+	}
+
+	return claim, nil
+}
+
+// GetAttachments возвращает подтверждения указанных заявок.
+func (r *ClaimsRepository) GetAttachments(ctx context.Context, claimIds []int64) ([]models.ClaimAttachment, error) {
+	var attachments []models.ClaimAttachment
+
+	err := pgxscan.Select(ctx, r.db, &attachments,
+		`SELECT id, claim_id, url, name FROM claim_attachments WHERE claim_id = ANY($1) ORDER BY id`,
+		claimIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get claim attachments: %w", err)
+	}
+
+	return attachments, nil
+}
+
+// Review переводит заявку на рассмотрении в статус status. Возвращает false, если заявка уже не на рассмотрении.
+func (r *ClaimsRepository) Review(ctx context.Context, id int64, status string, reviewerId int64, reason *string) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE claims
+         SET status = $2, reviewed_by = $3, reviewed_at = now(), reject_reason = $4
+         WHERE id = $1 AND status = 'pending'`,
+		id, status, reviewerId, reason,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not review claim: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Withdraw отзывает заявку студента, пока она на рассмотрении. Возвращает false, если отзывать нечего.
+func (r *ClaimsRepository) Withdraw(ctx context.Context, id, userId int64) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE claims SET status = 'withdrawn'
+         WHERE id = $1 AND user_id = $2 AND status = 'pending'`,
+		id, userId,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not withdraw claim: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
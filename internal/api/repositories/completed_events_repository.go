@@ -25,18 +25,26 @@ func (r *CompletedEventsRepository) WithDB(db DBTX) *CompletedEventsRepository {
 	return &CompletedEventsRepository{db: db}
 }
 
-// AddCompletedEvent — добавляет событие + обновляет очки.
-func (r *CompletedEventsRepository) AddCompletedEvent(ctx context.Context, userId, eventId int64) error {
+// AddCompletedEvent — добавляет выполненное событие и возвращает баллы события.
+// Баллы пользователю начисляются отдельно, записью в журнале баллов.
+// Удаленные события и черновики засчитать нельзя - pgx.ErrNoRows. Строка события
+// блокируется от изменения до конца транзакции, чтобы его не удалили одновременно с начислением.
+func (r *CompletedEventsRepository) AddCompletedEvent(ctx context.Context, userId, eventId int64) (int, error) {
 
 	//Получаем очки события
 	var points int
-	err := r.db.QueryRow(ctx, `SELECT points FROM events WHERE id = $1`, eventId).Scan(&points)
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE(points, 0) FROM events
+         WHERE id = $1 AND deleted_at IS NULL AND status <> 'draft'
+         FOR SHARE`,
+		eventId,
+	).Scan(&points)
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return fmt.Errorf("event points not found for eventId %d: %w", eventId, err)
+			return 0, fmt.Errorf("event points not found for eventId %d: %w", eventId, err)
 		}
-		return err
+		return 0, err
 	}
 
 	// 2. Добавляем выполненное событие
@@ -44,21 +52,28 @@ func (r *CompletedEventsRepository) AddCompletedEvent(ctx context.Context, userI
 		`INSERT INTO completed_events (user_id, event_id) VALUES ($1, $2)`, userId, eventId,
 	)
 	if err != nil || tag.RowsAffected() == 0 {
-		return fmt.Errorf("could not insert completed event: %w", err)
+		return 0, fmt.Errorf("could not insert completed event: %w", err)
 	}
-	// 3. Обновляем очки пользователя
-	tag, err = r.db.Exec(ctx,
-		`INSERT INTO user_points (user_id, total_points) VALUES ($1, $2) ON CONFLICT (user_id)
-         DO UPDATE SET total_points = user_points.total_points + EXCLUDED.total_points`, userId, points,
-	)
-	if err != nil || tag.RowsAffected() == 0 {
-		return fmt.Errorf("could not insert update user points: %w", err)
+
+	return points, nil
+}
+
+// IsCompleted проверяет, отмечено ли событие выполненным у пользователя.
+func (r *CompletedEventsRepository) IsCompleted(ctx context.Context, userId, eventId int64) (bool, error) {
+	var exists bool
+
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM completed_events WHERE user_id = $1 AND event_id = $2)`,
+		userId, eventId,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("could not check completed event: %w", err)
 	}
 
-	return nil
+	return exists, nil
 }
 
-// DeleteCompletedEvent удаляет связь user_id + event_id
+// DeleteCompletedEvent удаляет связь user_id + event_id. Баллы списываются отдельно, записью в журнале баллов.
 func (r *CompletedEventsRepository) DeleteCompletedEvent(ctx context.Context, userId, eventId int64) (pgconn.CommandTag, error) {
 	tag, err := r.db.Exec(ctx,
 		`DELETE FROM completed_events 
@@ -69,26 +84,25 @@ func (r *CompletedEventsRepository) DeleteCompletedEvent(ctx context.Context, us
 		return pgconn.CommandTag{}, fmt.Errorf("could not delete completed event: %w", err)
 	}
 
-	var points int
-	err = r.db.QueryRow(ctx, `SELECT points FROM events WHERE id = $1`, eventId).Scan(&points)
+	return tag, nil
+}
 
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return pgconn.CommandTag{}, fmt.Errorf("event points not found for eventId %d: %w", eventId, err)
-		}
-		return pgconn.CommandTag{}, err
-	}
+// GetCompletedEventDetails Получить выполненное событие пользователя вместе с названием и баллами события
+func (r *CompletedEventsRepository) GetCompletedEventDetails(ctx context.Context, userId, eventId int64) (models.AuditCompletedEvent, error) {
+	var result models.AuditCompletedEvent
 
-	tag, err = r.db.Exec(ctx,
-		`INSERT INTO user_points (user_id, total_points) VALUES ($1, $2) ON CONFLICT (user_id)
-         DO UPDATE SET total_points = user_points.total_points - EXCLUDED.total_points`, userId, points,
+	err := pgxscan.Get(ctx, r.db, &result,
+		`SELECT ce.user_id, ce.event_id, e.title, e.points, ce.completed_at
+         FROM completed_events ce
+         JOIN events e ON e.id = ce.event_id
+         WHERE ce.user_id = $1 AND ce.event_id = $2`,
+		userId, eventId,
 	)
-	if err != nil || tag.RowsAffected() == 0 {
-		return pgconn.CommandTag{}, fmt.Errorf("could not insert update user points: %w", err)
+	if err != nil {
+		return result, fmt.Errorf("could not get completed event: %w", err)
 	}
 
-	return tag, nil
-
+	return result, nil
 }
 
 // GetAllCompletedEvents Получить все события
@@ -126,39 +140,20 @@ func (r *CompletedEventsRepository) GetCompletedEventsWithStats(ctx context.Cont
 		return resp, fmt.Errorf("could not get completed events: %w", err)
 	}
 
-	// 2. Получаем статистику по типам
-	var rows []struct {
-		EventType int `db:"event_type_code"`
-		Count     int `db:"count"`
-	}
-
-	err = pgxscan.Select(ctx, r.db, &rows,
-		`SELECT e.event_type_code, COUNT(*) 
-         FROM completed_events ce
-         JOIN events e ON ce.event_id = e.id
-         WHERE ce.user_id = $1
-         GROUP BY e.event_type_code`,
+	// 2. Получаем статистику по всем типам, включая типы без выполненных мероприятий
+	err = pgxscan.Select(ctx, r.db, &resp.Stats,
+		`SELECT t.id, t.code, t.name, t.icon_url, t.color, COUNT(ce.event_id) as count
+         FROM events_types t
+         LEFT JOIN events e ON e.event_type_code = t.code
+         LEFT JOIN completed_events ce ON ce.event_id = e.id
+                                      AND ce.user_id = $1
+                                      AND ce.completed_at IS NOT NULL
+         GROUP BY t.id
+         ORDER BY t.code`,
 		userId,
 	)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return resp, fmt.Errorf("completed events not found: %w", err)
-		}
-		return resp, fmt.Errorf("could not get completed events: %w", err)
-	}
-
-	// распределяем статистику
-	for _, row := range rows {
-		switch row.EventType {
-		case 1:
-			resp.Stats.Hackathons = row.Count
-		case 2:
-			resp.Stats.Articles = row.Count
-		case 3:
-			resp.Stats.Olympiads = row.Count
-		case 4:
-			resp.Stats.Projects = row.Count
-		}
+		return resp, fmt.Errorf("could not get completed events stats: %w", err)
 	}
 
 	return resp, nil
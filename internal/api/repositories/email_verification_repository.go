@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// EmailVerificationRepository отвечает за подтверждение почты пользователей
+// и токены подтверждения.
+type EmailVerificationRepository struct {
+	db DBTX
+}
+
+// NewEmailVerificationRepository создает новый репозиторий.
+func NewEmailVerificationRepository(db DBTX) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, привязанную к новому DBTX (tx или pool).
+func (r *EmailVerificationRepository) WithDB(db DBTX) *EmailVerificationRepository {
+	return &EmailVerificationRepository{db: db}
+}
+
+// UpsertVerificationToken создает или заменяет токен подтверждения почты пользователя.
+// Предыдущий токен перестает действовать.
+func (r *EmailVerificationRepository) UpsertVerificationToken(ctx context.Context, userId int64, email string, tokenHash []byte, expiresAt time.Time) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO email_verification_tokens (user_id, email, token_hash, expires_at, created_at)
+         VALUES ($1, $2, $3, $4, NOW())
+         ON CONFLICT (user_id)
+         DO UPDATE SET email = EXCLUDED.email,
+                       token_hash = EXCLUDED.token_hash,
+                       expires_at = EXCLUDED.expires_at,
+                       created_at = NOW()`,
+		userId,
+		email,
+		tokenHash,
+		expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("could not create verification token: %w", err)
+	}
+
+	return nil
+}
+
+// GetVerificationToken возвращает пользователя и почту по хешу токена, если токен еще не истек.
+func (r *EmailVerificationRepository) GetVerificationToken(ctx context.Context, tokenHash []byte) (int64, string, error) {
+	var userId int64
+	var email string
+
+	err := r.db.QueryRow(ctx,
+		`SELECT user_id, email
+         FROM email_verification_tokens
+         WHERE token_hash = $1 AND expires_at > NOW()`,
+		tokenHash,
+	).Scan(&userId, &email)
+	if err != nil {
+		return 0, "", fmt.Errorf("could not get verification token: %w", err)
+	}
+
+	return userId, email, nil
+}
+
+// DeleteVerificationToken удаляет токен подтверждения пользователя.
+func (r *EmailVerificationRepository) DeleteVerificationToken(ctx context.Context, userId int64) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM email_verification_tokens WHERE user_id = $1`,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not delete verification token: %w", err)
+	}
+
+	return nil
+}
+
+// MarkEmailVerified отмечает почту пользователя подтвержденной, если она не поменялась
+// с момента выдачи токена. Возвращает false, если почта у пользователя уже другая.
+func (r *EmailVerificationRepository) MarkEmailVerified(ctx context.Context, userId int64, email string) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users
+         SET email_verified_at = COALESCE(email_verified_at, NOW())
+         WHERE id = $1 AND email = $2`,
+		userId,
+		email,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not mark email verified: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// IsEmailVerified проверяет, подтвердил ли пользователь свою почту.
+func (r *EmailVerificationRepository) IsEmailVerified(ctx context.Context, userId int64) (bool, error) {
+	var verified bool
+
+	err := r.db.QueryRow(ctx,
+		`SELECT email_verified_at IS NOT NULL FROM users WHERE id = $1`,
+		userId,
+	).Scan(&verified)
+	if err != nil {
+		return false, fmt.Errorf("could not check email verification: %w", err)
+	}
+
+	return verified, nil
+}
@@ -60,6 +60,22 @@ func (r *EventRepository) CreateEvent(ctx context.Context, data models.CreateEve
 		columns = append(columns, "link")
 		values = append(values, data.Link)
 	}
+	if data.Status != "" {
+		columns = append(columns, "status")
+		values = append(values, data.Status)
+	}
+	if data.Capacity != nil {
+		columns = append(columns, "capacity")
+		values = append(values, data.Capacity)
+	}
+	if data.RegistrationOpensAt != nil {
+		columns = append(columns, "registration_opens_at")
+		values = append(values, data.RegistrationOpensAt)
+	}
+	if data.RegistrationClosesAt != nil {
+		columns = append(columns, "registration_closes_at")
+		values = append(values, data.RegistrationClosesAt)
+	}
 
 	builder = builder.Columns(columns...).Values(values...).Suffix("RETURNING id")
 
@@ -83,7 +99,8 @@ func (r *EventRepository) GetEventById(ctx context.Context, id int64) (models.Cr
 
 	err := pgxscan.Get(ctx, r.db, &result,
 		`SELECT id, title, description, event_type_code, points,
-		        icon_url, event_date, link, created_at
+		        icon_url, event_date, link, created_at, status, deleted_at,
+		        capacity, registration_opens_at, registration_closes_at
          FROM events WHERE id = $1`,
 		id,
 	)
@@ -97,10 +114,11 @@ func (r *EventRepository) GetEventById(ctx context.Context, id int64) (models.Cr
 	return result, nil
 }
 
-// DeleteEvent Удалить событие
+// DeleteEvent Удалить событие. Удаление мягкое: строка остается, а выполненные события
+// и начисленные за них баллы сохраняются. Событие можно восстановить через RestoreEvent.
 func (r *EventRepository) DeleteEvent(ctx context.Context, eventId int64) (pgconn.CommandTag, error) {
 	tag, err := r.db.Exec(ctx,
-		`DELETE FROM events WHERE id = $1`,
+		`UPDATE events SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL`,
 		eventId,
 	)
 	if err != nil || tag.RowsAffected() == 0 {
@@ -109,20 +127,135 @@ func (r *EventRepository) DeleteEvent(ctx context.Context, eventId int64) (pgcon
 	return tag, nil
 }
 
+// RestoreEvent Восстановить удаленное событие
+func (r *EventRepository) RestoreEvent(ctx context.Context, eventId int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE events SET deleted_at = NULL WHERE id = $1`,
+		eventId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not restore event: %w", err)
+	}
+	return nil
+}
+
+// SetStatus Изменить статус события
+func (r *EventRepository) SetStatus(ctx context.Context, eventId int64, status string) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE events SET status = $2 WHERE id = $1`,
+		eventId, status,
+	)
+	if err != nil {
+		return fmt.Errorf("could not set event status: %w", err)
+	}
+	return nil
+}
+
 // GetEvents Получить список всех событий
 func (r *EventRepository) GetEvents(ctx context.Context, limit int) ([]models.Event, error) {
 	var events []models.Event
 
 	err := pgxscan.Select(ctx, r.db, &events,
 		`SELECT id, title, description, event_type_code, points,
-		        icon_url, event_date, link, created_at
-		 FROM events limit $1`, limit)
+		        icon_url, event_date, link, created_at, status, deleted_at,
+		        capacity, registration_opens_at, registration_closes_at
+		 FROM events WHERE deleted_at IS NULL
+		 ORDER BY id limit $1`, limit)
 	if err != nil {
 		return nil, fmt.Errorf("could not found events: %w", err)
 	}
 	return events, nil
 }
 
+// SearchEvents ищет события по фильтру с постраничной выдачей по курсору (keyset):
+// следующая страница начинается после filter.After в порядке (поле сортировки, id).
+// Поля сортировки (event_date, points) объявлены NOT NULL, поэтому сравнение курсора всегда определено.
+func (r *EventRepository) SearchEvents(ctx context.Context, filter models.EventSearchFilter) ([]models.Event, error) {
+	builder := sq.Select("id", "title", "description", "event_type_code", "points",
+		"icon_url", "event_date", "link", "created_at", "status", "deleted_at",
+		"capacity", "registration_opens_at", "registration_closes_at").
+		From("events")
+
+	if filter.Deleted {
+		builder = builder.Where("deleted_at IS NOT NULL")
+	} else {
+		builder = builder.Where("deleted_at IS NULL")
+	}
+	if len(filter.Statuses) > 0 {
+		builder = builder.Where(sq.Eq{"status": filter.Statuses})
+	}
+
+	if filter.Query != "" {
+		builder = builder.Where("search_vector @@ websearch_to_tsquery('russian', ?)", filter.Query)
+	}
+	if filter.EventTypeCode != nil {
+		builder = builder.Where(sq.Eq{"event_type_code": *filter.EventTypeCode})
+	}
+	if filter.DateFrom != nil {
+		builder = builder.Where(sq.GtOrEq{"event_date": *filter.DateFrom})
+	}
+	if filter.DateTo != nil {
+		builder = builder.Where(sq.Lt{"event_date": *filter.DateTo})
+	}
+	if filter.PointsMin != nil {
+		builder = builder.Where(sq.GtOrEq{"points": *filter.PointsMin})
+	}
+	if filter.PointsMax != nil {
+		builder = builder.Where(sq.LtOrEq{"points": *filter.PointsMax})
+	}
+
+	column := "event_date"
+	if filter.Sort == models.EventSortPoints {
+		column = "points"
+	}
+	direction, compare := "ASC", ">"
+	if filter.Desc {
+		direction, compare = "DESC", "<"
+	}
+
+	if filter.After != nil {
+		var value any = filter.After.EventDate
+		if filter.Sort == models.EventSortPoints {
+			value = filter.After.Points
+		}
+		builder = builder.Where(fmt.Sprintf("(%s, id) %s (?, ?)", column, compare), value, filter.After.EventId)
+	}
+
+	builder = builder.OrderBy(column+" "+direction, "id "+direction).
+		Limit(uint64(filter.Limit))
+
+	query, args, err := builder.PlaceholderFormat(sq.Dollar).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not convert to sql query: %w", err)
+	}
+
+	events := []models.Event{}
+	if err = pgxscan.Select(ctx, r.db, &events, query, args...); err != nil {
+		return nil, fmt.Errorf("could not search events: %w", err)
+	}
+
+	return events, nil
+}
+
+// GetEventUserStats возвращает для событий из списка, выполнил ли их пользователь, и число участников.
+func (r *EventRepository) GetEventUserStats(ctx context.Context, userId int64, eventIds []int64) ([]models.EventUserStats, error) {
+	stats := []models.EventUserStats{}
+
+	err := pgxscan.Select(ctx, r.db, &stats,
+		`SELECT e.id AS event_id, ce.completed_at,
+		        (SELECT COUNT(*) FROM completed_events c WHERE c.event_id = e.id) AS participants_count
+		 FROM events e
+		 LEFT JOIN completed_events ce ON ce.event_id = e.id AND ce.user_id = $1
+		 WHERE e.id = ANY($2)`,
+		userId, eventIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get event stats: %w", err)
+	}
+
+	return stats, nil
+}
+
 // UpdateEvent Обновить данные события
 func (r *EventRepository) UpdateEvent(ctx context.Context, req models.UpdateEventRequest) error {
 	builder := sq.Update("events")
@@ -148,6 +281,20 @@ func (r *EventRepository) UpdateEvent(ctx context.Context, req models.UpdateEven
 	if req.NewData.EventTypeCode != 0 {
 		builder = builder.Set("event_type_code", req.NewData.EventTypeCode)
 	}
+	if req.NewData.Capacity != nil {
+		// 0 снимает ограничение мест
+		if *req.NewData.Capacity == 0 {
+			builder = builder.Set("capacity", nil)
+		} else {
+			builder = builder.Set("capacity", *req.NewData.Capacity)
+		}
+	}
+	if req.NewData.RegistrationOpensAt != nil {
+		builder = builder.Set("registration_opens_at", req.NewData.RegistrationOpensAt)
+	}
+	if req.NewData.RegistrationClosesAt != nil {
+		builder = builder.Set("registration_closes_at", req.NewData.RegistrationClosesAt)
+	}
 
 	builder = builder.Where(sq.Eq{"id": req.EventId})
 
@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// EventTypesRepository отвечает за работу с таблицей events_types.
+type EventTypesRepository struct {
+	db DBTX
+}
+
+// NewEventTypesRepository создает новый экземпляр EventTypesRepository.
+func NewEventTypesRepository(db DBTX) *EventTypesRepository {
+	return &EventTypesRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *EventTypesRepository) WithDB(db DBTX) *EventTypesRepository {
+	return &EventTypesRepository{db: db}
+}
+
+// GetEventTypes возвращает все типы мероприятий, упорядоченные по коду.
+func (r *EventTypesRepository) GetEventTypes(ctx context.Context) ([]models.EventType, error) {
+	var types []models.EventType
+
+	err := pgxscan.Select(ctx, r.db, &types,
+		`SELECT id, code, name, icon_url, color FROM events_types ORDER BY code`)
+	if err != nil {
+		return nil, fmt.Errorf("could not get event types: %w", err)
+	}
+
+	return types, nil
+}
+
+// GetEventType возвращает тип мероприятия по коду. Если типа нет - pgx.ErrNoRows.
+func (r *EventTypesRepository) GetEventType(ctx context.Context, code int) (models.EventType, error) {
+	var eventType models.EventType
+
+	err := pgxscan.Get(ctx, r.db, &eventType,
+		`SELECT id, code, name, icon_url, color FROM events_types WHERE code = $1`, code)
+	if err != nil {
+		return eventType, fmt.Errorf("could not get event type: %w", err)
+	}
+
+	return eventType, nil
+}
+
+// CreateEventType добавляет тип мероприятия.
+func (r *EventTypesRepository) CreateEventType(ctx context.Context, eventType models.EventType) (models.EventType, error) {
+	var created models.EventType
+
+	err := pgxscan.Get(ctx, r.db, &created,
+		`INSERT INTO events_types (code, name, icon_url, color) VALUES ($1, $2, $3, $4)
+         RETURNING id, code, name, icon_url, color`,
+		eventType.Code, eventType.Name, eventType.IconUrl, eventType.Color,
+	)
+	if err != nil {
+		return created, fmt.Errorf("could not create event type: %w", err)
+	}
+
+	return created, nil
+}
+
+// UpdateEventType перезаписывает название, иконку и цвет типа. Если типа нет - pgx.ErrNoRows.
+func (r *EventTypesRepository) UpdateEventType(ctx context.Context, eventType models.EventType) (models.EventType, error) {
+	var updated models.EventType
+
+	err := pgxscan.Get(ctx, r.db, &updated,
+		`UPDATE events_types SET name = $2, icon_url = $3, color = $4 WHERE code = $1
+         RETURNING id, code, name, icon_url, color`,
+		eventType.Code, eventType.Name, eventType.IconUrl, eventType.Color,
+	)
+	if err != nil {
+		return updated, fmt.Errorf("could not update event type: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteEventType удаляет тип мероприятия. События этого типа переходят на тип 0 (ON DELETE SET DEFAULT).
+func (r *EventTypesRepository) DeleteEventType(ctx context.Context, code int) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM events_types WHERE code = $1`, code)
+	if err != nil {
+		return fmt.Errorf("could not delete event type: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not delete event type: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
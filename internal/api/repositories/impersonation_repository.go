@@ -0,0 +1,110 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// ImpersonationRepository отвечает за записи входа от имени пользователя (impersonation_sessions).
+type ImpersonationRepository struct {
+	db DBTX
+}
+
+// NewImpersonationRepository создает новый экземпляр ImpersonationRepository.
+func NewImpersonationRepository(db DBTX) *ImpersonationRepository {
+	return &ImpersonationRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *ImpersonationRepository) WithDB(db DBTX) *ImpersonationRepository {
+	return &ImpersonationRepository{db: db}
+}
+
+// CreateSession записывает начало входа от имени пользователя.
+func (r *ImpersonationRepository) CreateSession(ctx context.Context, actorId, userId int64, reason string, readOnly bool, expiresAt time.Time) (models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+
+	err := pgxscan.Get(ctx, r.db, &session,
+		`INSERT INTO impersonation_sessions (actor_id, user_id, reason, read_only, expires_at)
+         VALUES ($1, $2, $3, $4, $5)
+         RETURNING id, actor_id, user_id, reason, read_only, started_at, expires_at, ended_at`,
+		actorId,
+		userId,
+		reason,
+		readOnly,
+		expiresAt,
+	)
+	if err != nil {
+		return models.ImpersonationSession{}, fmt.Errorf("could not create impersonation session: %w", err)
+	}
+
+	return session, nil
+}
+
+// EndSession завершает активную сессию, начатую actorId (actorId = 0 - любую).
+// Если сессия не найдена, уже завершена или начата другим - возвращает pgx.ErrNoRows.
+func (r *ImpersonationRepository) EndSession(ctx context.Context, id, actorId int64) (models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+
+	err := pgxscan.Get(ctx, r.db, &session,
+		`UPDATE impersonation_sessions
+         SET ended_at = NOW()
+         WHERE id = $1 AND ended_at IS NULL AND expires_at > NOW()
+           AND ($2 = 0 OR actor_id = $2)
+         RETURNING id, actor_id, user_id, reason, read_only, started_at, expires_at, ended_at`,
+		id,
+		actorId,
+	)
+	if err != nil {
+		return models.ImpersonationSession{}, fmt.Errorf("could not end impersonation session: %w", err)
+	}
+
+	return session, nil
+}
+
+// GetSessions возвращает последние входы от имени пользователей. userId = 0 - по всем пользователям.
+func (r *ImpersonationRepository) GetSessions(ctx context.Context, userId int64, limit int) ([]models.ImpersonationSession, error) {
+	var sessions []models.ImpersonationSession
+
+	err := pgxscan.Select(ctx, r.db, &sessions,
+		`SELECT id, actor_id, user_id, reason, read_only, started_at, expires_at, ended_at
+         FROM impersonation_sessions
+         WHERE $1 = 0 OR user_id = $1
+         ORDER BY started_at DESC
+         LIMIT $2`,
+		userId,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get impersonation sessions: %w", err)
+	}
+
+	return sessions, nil
+}
+
+// GetAccessState возвращает текущую версию токенов пользователя и то, активна ли еще сессия входа от его имени.
+// Если пользователь удален - возвращает pgx.ErrNoRows.
+func (r *ImpersonationRepository) GetAccessState(ctx context.Context, userId, sessionId int64) (models.ImpersonationState, error) {
+	var state models.ImpersonationState
+
+	err := r.db.QueryRow(ctx,
+		`SELECT u.token_version,
+                EXISTS(SELECT 1
+                       FROM impersonation_sessions s
+                       WHERE s.id = $2 AND s.user_id = u.id
+                         AND s.ended_at IS NULL AND s.expires_at > NOW())
+         FROM users u
+         WHERE u.id = $1`,
+		userId,
+		sessionId,
+	).Scan(&state.TokenVersion, &state.Active)
+	if err != nil {
+		return models.ImpersonationState{}, fmt.Errorf("could not get impersonation access state: %w", err)
+	}
+
+	return state, nil
+}
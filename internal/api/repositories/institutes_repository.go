@@ -0,0 +1,284 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// InstitutesRepository отвечает за структуру университета: institutes, studies и student_groups.
+type InstitutesRepository struct {
+	db DBTX
+}
+
+// NewInstitutesRepository создает новый экземпляр InstitutesRepository.
+func NewInstitutesRepository(db DBTX) *InstitutesRepository {
+	return &InstitutesRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *InstitutesRepository) WithDB(db DBTX) *InstitutesRepository {
+	return &InstitutesRepository{db: db}
+}
+
+// GetInstitutes возвращает все институты.
+func (r *InstitutesRepository) GetInstitutes(ctx context.Context) ([]models.Institute, error) {
+	var institutes []models.Institute
+
+	err := pgxscan.Select(ctx, r.db, &institutes,
+		`SELECT id, name FROM institutes ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("could not get institutes: %w", err)
+	}
+
+	return institutes, nil
+}
+
+// GetStudies возвращает все направления.
+func (r *InstitutesRepository) GetStudies(ctx context.Context) ([]models.Study, error) {
+	var studies []models.Study
+
+	err := pgxscan.Select(ctx, r.db, &studies,
+		`SELECT id, name, COALESCE(institute_id, 0) as institute_id FROM studies ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("could not get studies: %w", err)
+	}
+
+	return studies, nil
+}
+
+// GetGroups возвращает все группы.
+func (r *InstitutesRepository) GetGroups(ctx context.Context) ([]models.StudentGroup, error) {
+	var groups []models.StudentGroup
+
+	err := pgxscan.Select(ctx, r.db, &groups,
+		`SELECT id, name, COALESCE(studies_id, 0) as study_id FROM student_groups ORDER BY name`)
+	if err != nil {
+		return nil, fmt.Errorf("could not get groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetGroupsByNames возвращает группы с указанными названиями.
+func (r *InstitutesRepository) GetGroupsByNames(ctx context.Context, names []string) ([]models.StudentGroup, error) {
+	var groups []models.StudentGroup
+
+	err := pgxscan.Select(ctx, r.db, &groups,
+		`SELECT id, name, COALESCE(studies_id, 0) as study_id FROM student_groups WHERE name = ANY($1)`,
+		names,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get groups: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GetGroupByName возвращает группу по названию. Если группы нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) GetGroupByName(ctx context.Context, name string) (models.StudentGroup, error) {
+	var group models.StudentGroup
+
+	err := pgxscan.Get(ctx, r.db, &group,
+		`SELECT id, name, COALESCE(studies_id, 0) as study_id FROM student_groups WHERE name = $1`,
+		name,
+	)
+	if err != nil {
+		return group, fmt.Errorf("could not get group: %w", err)
+	}
+
+	return group, nil
+}
+
+// GroupExists проверяет, существует ли группа с указанным id.
+func (r *InstitutesRepository) GroupExists(ctx context.Context, id int64) (bool, error) {
+	var exists bool
+
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM student_groups WHERE id = $1)`,
+		id,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("could not check group existence: %w", err)
+	}
+
+	return exists, nil
+}
+
+// CreateInstitute добавляет институт.
+func (r *InstitutesRepository) CreateInstitute(ctx context.Context, name string) (models.Institute, error) {
+	var institute models.Institute
+
+	err := pgxscan.Get(ctx, r.db, &institute,
+		`INSERT INTO institutes (name) VALUES ($1) RETURNING id, name`,
+		name,
+	)
+	if err != nil {
+		return institute, fmt.Errorf("could not create institute: %w", err)
+	}
+
+	return institute, nil
+}
+
+// UpdateInstitute переименовывает институт. Если института нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) UpdateInstitute(ctx context.Context, id int64, name string) (models.Institute, error) {
+	var institute models.Institute
+
+	err := pgxscan.Get(ctx, r.db, &institute,
+		`UPDATE institutes SET name = $2 WHERE id = $1 RETURNING id, name`,
+		id, name,
+	)
+	if err != nil {
+		return institute, fmt.Errorf("could not update institute: %w", err)
+	}
+
+	return institute, nil
+}
+
+// GetInstitute возвращает институт по id. Если института нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) GetInstitute(ctx context.Context, id int64) (models.Institute, error) {
+	var institute models.Institute
+
+	err := pgxscan.Get(ctx, r.db, &institute,
+		`SELECT id, name FROM institutes WHERE id = $1`, id)
+	if err != nil {
+		return institute, fmt.Errorf("could not get institute: %w", err)
+	}
+
+	return institute, nil
+}
+
+// DeleteInstitute удаляет институт, если в нем нет направлений. Возвращает false, если направления остались.
+func (r *InstitutesRepository) DeleteInstitute(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM institutes
+         WHERE id = $1 AND NOT EXISTS(SELECT 1 FROM studies WHERE institute_id = $1)`,
+		id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not delete institute: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetStudy возвращает направление по id. Если направления нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) GetStudy(ctx context.Context, id int64) (models.Study, error) {
+	var study models.Study
+
+	err := pgxscan.Get(ctx, r.db, &study,
+		`SELECT id, name, COALESCE(institute_id, 0) as institute_id FROM studies WHERE id = $1`, id)
+	if err != nil {
+		return study, fmt.Errorf("could not get study: %w", err)
+	}
+
+	return study, nil
+}
+
+// CreateStudy добавляет направление в институт.
+func (r *InstitutesRepository) CreateStudy(ctx context.Context, name string, instituteId int64) (models.Study, error) {
+	var study models.Study
+
+	err := pgxscan.Get(ctx, r.db, &study,
+		`INSERT INTO studies (name, institute_id) VALUES ($1, $2)
+         RETURNING id, name, institute_id`,
+		name, instituteId,
+	)
+	if err != nil {
+		return study, fmt.Errorf("could not create study: %w", err)
+	}
+
+	return study, nil
+}
+
+// UpdateStudy перезаписывает название и институт направления. Если направления нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) UpdateStudy(ctx context.Context, study models.Study) (models.Study, error) {
+	var updated models.Study
+
+	err := pgxscan.Get(ctx, r.db, &updated,
+		`UPDATE studies SET name = $2, institute_id = $3 WHERE id = $1
+         RETURNING id, name, institute_id`,
+		study.Id, study.Name, study.InstituteId,
+	)
+	if err != nil {
+		return updated, fmt.Errorf("could not update study: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteStudy удаляет направление, если в нем нет групп. Возвращает false, если группы остались.
+func (r *InstitutesRepository) DeleteStudy(ctx context.Context, id int64) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM studies
+         WHERE id = $1 AND NOT EXISTS(SELECT 1 FROM student_groups WHERE studies_id = $1)`,
+		id,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not delete study: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetGroup возвращает группу по id. Если группы нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) GetGroup(ctx context.Context, id int64) (models.StudentGroup, error) {
+	var group models.StudentGroup
+
+	err := pgxscan.Get(ctx, r.db, &group,
+		`SELECT id, name, COALESCE(studies_id, 0) as study_id FROM student_groups WHERE id = $1`, id)
+	if err != nil {
+		return group, fmt.Errorf("could not get group: %w", err)
+	}
+
+	return group, nil
+}
+
+// CreateGroup добавляет группу. studyId = 0 - группа без направления.
+func (r *InstitutesRepository) CreateGroup(ctx context.Context, name string, studyId int64) (models.StudentGroup, error) {
+	var group models.StudentGroup
+
+	err := pgxscan.Get(ctx, r.db, &group,
+		`INSERT INTO student_groups (name, studies_id) VALUES ($1, NULLIF($2::int, 0))
+         RETURNING id, name, COALESCE(studies_id, 0) as study_id`,
+		name, studyId,
+	)
+	if err != nil {
+		return group, fmt.Errorf("could not create group: %w", err)
+	}
+
+	return group, nil
+}
+
+// UpdateGroup перезаписывает название и направление группы. Если группы нет - pgx.ErrNoRows.
+func (r *InstitutesRepository) UpdateGroup(ctx context.Context, group models.StudentGroup) (models.StudentGroup, error) {
+	var updated models.StudentGroup
+
+	err := pgxscan.Get(ctx, r.db, &updated,
+		`UPDATE student_groups SET name = $2, studies_id = NULLIF($3::int, 0) WHERE id = $1
+         RETURNING id, name, COALESCE(studies_id, 0) as study_id`,
+		group.Id, group.Name, group.StudyId,
+	)
+	if err != nil {
+		return updated, fmt.Errorf("could not update group: %w", err)
+	}
+
+	return updated, nil
+}
+
+// DeleteGroup удаляет группу. Если на нее ссылаются студенты или пользователи,
+// база вернет нарушение внешнего ключа (23503).
+func (r *InstitutesRepository) DeleteGroup(ctx context.Context, id int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM student_groups WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("could not delete group: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not delete group: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
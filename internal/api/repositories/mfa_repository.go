@@ -0,0 +1,250 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// MFARepository отвечает за TOTP секреты, коды восстановления и MFA челленджи при входе.
+type MFARepository struct {
+	db DBTX
+}
+
+// NewMFARepository создает новый экземпляр MFARepository.
+func NewMFARepository(db DBTX) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую указанный DBTX (tx или pool).
+func (r *MFARepository) WithDB(db DBTX) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// GetTOTP возвращает TOTP пользователя (включенный или ожидающий подтверждения).
+func (r *MFARepository) GetTOTP(ctx context.Context, userId int64) (models.UserTOTP, error) {
+	var totp models.UserTOTP
+
+	err := pgxscan.Get(ctx, r.db, &totp,
+		`SELECT user_id, secret, enabled_at, last_used_step
+         FROM user_totp
+         WHERE user_id = $1`,
+		userId,
+	)
+	if err != nil {
+		return totp, fmt.Errorf("could not get totp: %w", err)
+	}
+
+	return totp, nil
+}
+
+// IsTOTPEnabled проверяет, включена ли у пользователя двухфакторная аутентификация.
+func (r *MFARepository) IsTOTPEnabled(ctx context.Context, userId int64) (bool, error) {
+	var enabled bool
+
+	err := r.db.QueryRow(ctx,
+		`SELECT EXISTS(SELECT 1 FROM user_totp WHERE user_id = $1 AND enabled_at IS NOT NULL)`,
+		userId,
+	).Scan(&enabled)
+	if err != nil {
+		return false, fmt.Errorf("could not check totp: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// UpsertPendingTOTP сохраняет новый неподтвержденный секрет. Включенный TOTP не перезаписывается.
+func (r *MFARepository) UpsertPendingTOTP(ctx context.Context, userId int64, secret string) (pgconn.CommandTag, error) {
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO user_totp (user_id, secret)
+         VALUES ($1, $2)
+         ON CONFLICT (user_id)
+         DO UPDATE SET secret = EXCLUDED.secret, last_used_step = 0, created_at = NOW()
+         WHERE user_totp.enabled_at IS NULL`,
+		userId,
+		secret,
+	)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("could not save totp secret: %w", err)
+	}
+
+	return tag, nil
+}
+
+// EnableTOTP подтверждает привязку TOTP.
+func (r *MFARepository) EnableTOTP(ctx context.Context, userId int64) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE user_totp SET enabled_at = NOW()
+         WHERE user_id = $1 AND enabled_at IS NULL`,
+		userId,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not enable totp: %w", err)
+	}
+
+	return nil
+}
+
+// UseTOTPStep запоминает принятый интервал. Если код этого или более позднего интервала
+// уже принимался, ничего не меняет (RowsAffected = 0) - значит, код используется повторно.
+func (r *MFARepository) UseTOTPStep(ctx context.Context, userId, step int64) (pgconn.CommandTag, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE user_totp SET last_used_step = $2
+         WHERE user_id = $1 AND last_used_step < $2`,
+		userId,
+		step,
+	)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("could not update totp step: %w", err)
+	}
+
+	return tag, nil
+}
+
+// DeleteTOTP отключает двухфакторную аутентификацию и удаляет коды восстановления.
+func (r *MFARepository) DeleteTOTP(ctx context.Context, userId int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("could not delete recovery codes: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx, `DELETE FROM user_totp WHERE user_id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("could not delete totp: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceRecoveryCodes заменяет все коды восстановления пользователя новыми (обычно внутри транзакции).
+func (r *MFARepository) ReplaceRecoveryCodes(ctx context.Context, userId int64, codeHashes [][]byte) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM totp_recovery_codes WHERE user_id = $1`, userId)
+	if err != nil {
+		return fmt.Errorf("could not delete recovery codes: %w", err)
+	}
+
+	for _, hash := range codeHashes {
+		_, err = r.db.Exec(ctx,
+			`INSERT INTO totp_recovery_codes (user_id, code_hash) VALUES ($1, $2)`,
+			userId, hash,
+		)
+		if err != nil {
+			return fmt.Errorf("could not create recovery code: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UseRecoveryCode помечает код восстановления использованным. RowsAffected = 0 - код не найден или уже использован.
+func (r *MFARepository) UseRecoveryCode(ctx context.Context, userId int64, codeHash []byte) (pgconn.CommandTag, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE totp_recovery_codes SET used_at = NOW()
+         WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userId,
+		codeHash,
+	)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("could not use recovery code: %w", err)
+	}
+
+	return tag, nil
+}
+
+// CountRecoveryCodes возвращает количество неиспользованных кодов восстановления.
+func (r *MFARepository) CountRecoveryCodes(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM totp_recovery_codes WHERE user_id = $1 AND used_at IS NULL`,
+		userId,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("could not count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateChallenge сохраняет MFA челлендж, выданный после успешной проверки пароля.
+func (r *MFARepository) CreateChallenge(ctx context.Context, tokenHash []byte, userId int64, expiresAt time.Time) error {
+	tag, err := r.db.Exec(ctx,
+		`INSERT INTO mfa_challenges (token_hash, user_id, expires_at)
+         VALUES ($1, $2, $3)`,
+		tokenHash,
+		userId,
+		expiresAt,
+	)
+	if err != nil || tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not create mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// GetChallenge возвращает действующий челлендж без блокировки.
+func (r *MFARepository) GetChallenge(ctx context.Context, tokenHash []byte) (models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+
+	err := pgxscan.Get(ctx, r.db, &challenge,
+		`SELECT user_id, attempts, expires_at
+         FROM mfa_challenges
+         WHERE token_hash = $1 AND expires_at > NOW()`,
+		tokenHash,
+	)
+	if err != nil {
+		return challenge, fmt.Errorf("could not get mfa challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// GetChallengeForUpdate возвращает действующий челлендж и блокирует его до конца транзакции.
+func (r *MFARepository) GetChallengeForUpdate(ctx context.Context, tokenHash []byte) (models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+
+	err := pgxscan.Get(ctx, r.db, &challenge,
+		`SELECT user_id, attempts, expires_at
+         FROM mfa_challenges
+         WHERE token_hash = $1 AND expires_at > NOW()
+         FOR UPDATE`,
+		tokenHash,
+	)
+	if err != nil {
+		return challenge, fmt.Errorf("could not get mfa challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// IncrementChallengeAttempts увеличивает счетчик неверных кодов челленджа.
+func (r *MFARepository) IncrementChallengeAttempts(ctx context.Context, tokenHash []byte) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE mfa_challenges SET attempts = attempts + 1 WHERE token_hash = $1`,
+		tokenHash,
+	)
+	if err != nil {
+		return fmt.Errorf("could not update mfa challenge: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteChallenge удаляет челлендж, а заодно истекшие челленджи пользователя.
+func (r *MFARepository) DeleteChallenge(ctx context.Context, tokenHash []byte, userId int64) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM mfa_challenges
+         WHERE token_hash = $1 OR (user_id = $2 AND expires_at <= NOW())`,
+		tokenHash,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not delete mfa challenge: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// PermissionsRepository отвечает за роли и их права (roles, permissions, role_permissions).
+type PermissionsRepository struct {
+	db DBTX
+}
+
+// NewPermissionsRepository создает новый экземпляр PermissionsRepository.
+func NewPermissionsRepository(db DBTX) *PermissionsRepository {
+	return &PermissionsRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *PermissionsRepository) WithDB(db DBTX) *PermissionsRepository {
+	return &PermissionsRepository{db: db}
+}
+
+// GetPermissionsByRoleLevel возвращает коды прав роли.
+func (r *PermissionsRepository) GetPermissionsByRoleLevel(ctx context.Context, roleLevel int64) ([]string, error) {
+	var permissions []string
+
+	err := pgxscan.Select(ctx, r.db, &permissions,
+		`SELECT permission_code
+         FROM role_permissions
+         WHERE role_level = $1
+         ORDER BY permission_code`,
+		roleLevel,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get role permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// GetPermissions возвращает все существующие права.
+func (r *PermissionsRepository) GetPermissions(ctx context.Context) ([]models.Permission, error) {
+	var permissions []models.Permission
+
+	err := pgxscan.Select(ctx, r.db, &permissions,
+		`SELECT code, description FROM permissions ORDER BY code`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+// GetRoles возвращает все роли вместе с их правами.
+func (r *PermissionsRepository) GetRoles(ctx context.Context) ([]models.Role, error) {
+	var roles []models.Role
+
+	err := pgxscan.Select(ctx, r.db, &roles,
+		`SELECT r.id,
+		        r.code,
+		        r.name,
+		        r.level,
+		        COALESCE(array_agg(rp.permission_code ORDER BY rp.permission_code)
+		                 FILTER (WHERE rp.permission_code IS NOT NULL), '{}') as permissions
+		 FROM roles r
+		 LEFT JOIN role_permissions rp ON rp.role_level = r.level
+		 GROUP BY r.id
+		 ORDER BY r.level`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get roles: %w", err)
+	}
+
+	return roles, nil
+}
+
+// CreateRole создает роль и возвращает ее id.
+func (r *PermissionsRepository) CreateRole(ctx context.Context, role models.Role) (int64, error) {
+	var id int64
+
+	err := r.db.QueryRow(ctx,
+		`INSERT INTO roles (code, name, level)
+         VALUES ($1, $2, $3)
+         ON CONFLICT DO NOTHING
+         RETURNING id`,
+		role.Code,
+		role.Name,
+		role.Level,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("could not create role: %w", err)
+	}
+
+	return id, nil
+}
+
+// SetRolePermissions заменяет набор прав роли.
+func (r *PermissionsRepository) SetRolePermissions(ctx context.Context, roleLevel int64, permissions []string) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM role_permissions WHERE role_level = $1`,
+		roleLevel,
+	)
+	if err != nil {
+		return fmt.Errorf("could not clear role permissions: %w", err)
+	}
+
+	_, err = r.db.Exec(ctx,
+		`INSERT INTO role_permissions (role_level, permission_code)
+         SELECT $1, unnest($2::text[])
+         ON CONFLICT DO NOTHING`,
+		roleLevel,
+		permissions,
+	)
+	if err != nil {
+		return fmt.Errorf("could not set role permissions: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeRoleTokens увеличивает версию токенов всех пользователей роли,
+// чтобы новые права применились сразу, а не после истечения access токенов.
+func (r *PermissionsRepository) RevokeRoleTokens(ctx context.Context, roleLevel int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE users SET token_version = token_version + 1 WHERE role_level = $1`,
+		roleLevel,
+	)
+	if err != nil {
+		return fmt.Errorf("could not revoke role tokens: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,229 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
+)
+
+// PointsRepository отвечает за журнал баллов (points_transactions) и кэш сумм в user_points.
+// Журнал только пополняется; user_points меняется в том же запросе, что и журнал,
+// поэтому сумма в кэше совпадает с суммой журнала.
+type PointsRepository struct {
+	db DBTX
+}
+
+// NewPointsRepository создает новый экземпляр PointsRepository.
+func NewPointsRepository(db DBTX) *PointsRepository {
+	return &PointsRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *PointsRepository) WithDB(db DBTX) *PointsRepository {
+	return &PointsRepository{db: db}
+}
+
+// AddTransaction добавляет запись в журнал и меняет сумму пользователя на delta.
+// Если пользователя нет - нарушение внешнего ключа (23503).
+func (r *PointsRepository) AddTransaction(ctx context.Context, t models.NewPointsTransaction) error {
+	_, err := r.db.Exec(ctx,
+		`WITH t AS (
+             INSERT INTO points_transactions (user_id, delta, reason, event_id, actor_id, comment)
+             VALUES ($1, $2, $3, $4, $5, $6)
+             RETURNING user_id, delta
+         )
+         INSERT INTO user_points (user_id, total_points)
+         SELECT user_id, delta FROM t
+         ON CONFLICT (user_id) DO UPDATE SET total_points = user_points.total_points + EXCLUDED.total_points`,
+		t.UserId, t.Delta, t.Reason, t.EventId, t.ActorId, t.Comment,
+	)
+	if err != nil {
+		return fmt.Errorf("could not add points transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetEventBalance возвращает, сколько баллов пользователь получил за событие по журналу.
+func (r *PointsRepository) GetEventBalance(ctx context.Context, userId, eventId int64) (int, error) {
+	var balance int
+
+	err := r.db.QueryRow(ctx,
+		`SELECT COALESCE(SUM(delta), 0) FROM points_transactions WHERE user_id = $1 AND event_id = $2`,
+		userId, eventId,
+	).Scan(&balance)
+	if err != nil {
+		return 0, fmt.Errorf("could not get event balance: %w", err)
+	}
+
+	return balance, nil
+}
+
+// SetEventPoints доводит баллы за событие у всех, кому оно засчитано, до points:
+// каждому добавляется запись с разницей между points и уже полученным за событие.
+// Возвращает число затронутых пользователей.
+func (r *PointsRepository) SetEventPoints(ctx context.Context, eventId int64, points int, reason string, actorId int64, comment string) (int64, error) {
+	tag, err := r.db.Exec(ctx,
+		`WITH diff AS (
+             SELECT ce.user_id,
+                    $2::int - COALESCE((SELECT SUM(pt.delta) FROM points_transactions pt
+                                   WHERE pt.user_id = ce.user_id AND pt.event_id = ce.event_id), 0) AS delta
+             FROM completed_events ce
+             WHERE ce.event_id = $1
+         ), t AS (
+             INSERT INTO points_transactions (user_id, delta, reason, event_id, actor_id, comment)
+             SELECT user_id, delta, $3::text, $1::int, $4::int, $5::text FROM diff WHERE delta <> 0
+             RETURNING user_id, delta
+         )
+         INSERT INTO user_points (user_id, total_points)
+         SELECT user_id, delta FROM t
+         ON CONFLICT (user_id) DO UPDATE SET total_points = user_points.total_points + EXCLUDED.total_points`,
+		eventId, points, reason, actorId, comment,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not set event points: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// GetTotal возвращает сумму баллов пользователя. Если записей нет - 0.
+func (r *PointsRepository) GetTotal(ctx context.Context, userId int64) (int64, error) {
+	var total int64
+
+	err := r.db.QueryRow(ctx,
+		`SELECT total_points FROM user_points WHERE user_id = $1`,
+		userId,
+	).Scan(&total)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("could not get points total: %w", err)
+	}
+
+	return total, nil
+}
+
+// GetTransactions возвращает записи журнала пользователя, новые первыми.
+func (r *PointsRepository) GetTransactions(ctx context.Context, userId int64, limit, offset int) ([]models.PointsTransaction, error) {
+	transactions := []models.PointsTransaction{}
+
+	err := pgxscan.Select(ctx, r.db, &transactions,
+		`SELECT pt.id, pt.user_id, pt.delta, pt.reason, pt.event_id, e.title AS event_title,
+		        pt.actor_id, pt.comment, pt.created_at
+		 FROM points_transactions pt
+		 LEFT JOIN events e ON e.id = pt.event_id
+		 WHERE pt.user_id = $1
+		 ORDER BY pt.created_at DESC, pt.id DESC
+		 LIMIT $2 OFFSET $3`,
+		userId, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get points transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetAllTransactions возвращает весь журнал пользователя в порядке записи (для выгрузки данных).
+func (r *PointsRepository) GetAllTransactions(ctx context.Context, userId int64) ([]models.PointsTransaction, error) {
+	transactions := []models.PointsTransaction{}
+
+	err := pgxscan.Select(ctx, r.db, &transactions,
+		`SELECT pt.id, pt.user_id, pt.delta, pt.reason, pt.event_id, e.title AS event_title,
+		        pt.actor_id, pt.comment, pt.created_at
+		 FROM points_transactions pt
+		 LEFT JOIN events e ON e.id = pt.event_id
+		 WHERE pt.user_id = $1
+		 ORDER BY pt.created_at, pt.id`,
+		userId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get points transactions: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// GetEventDrift возвращает пары пользователь-событие, у которых баллы по журналу не совпадают
+// с баллами события (для засчитанных) или не равны 0 (для снятых отметок).
+func (r *PointsRepository) GetEventDrift(ctx context.Context) ([]models.PointsDrift, error) {
+	drift := []models.PointsDrift{}
+
+	err := pgxscan.Select(ctx, r.db, &drift,
+		`WITH expected AS (
+             SELECT ce.user_id, ce.event_id, COALESCE(e.points, 0)::bigint AS points
+             FROM completed_events ce
+             JOIN events e ON e.id = ce.event_id
+         ), actual AS (
+             SELECT user_id, event_id, SUM(delta)::bigint AS points
+             FROM points_transactions
+             WHERE event_id IS NOT NULL
+             GROUP BY user_id, event_id
+         )
+         SELECT COALESCE(x.user_id, a.user_id) AS user_id,
+                COALESCE(x.event_id, a.event_id) AS event_id,
+                COALESCE(x.points, 0) AS expected,
+                COALESCE(a.points, 0) AS actual
+         FROM expected x
+         FULL JOIN actual a ON a.user_id = x.user_id AND a.event_id = x.event_id
+         WHERE COALESCE(x.points, 0) <> COALESCE(a.points, 0)
+         ORDER BY 1, 2`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get event points drift: %w", err)
+	}
+
+	return drift, nil
+}
+
+// GetTotalDrift возвращает пользователей, у которых сумма в user_points не совпадает с суммой журнала.
+func (r *PointsRepository) GetTotalDrift(ctx context.Context) ([]models.PointsDrift, error) {
+	drift := []models.PointsDrift{}
+
+	err := pgxscan.Select(ctx, r.db, &drift,
+		`SELECT COALESCE(up.user_id, l.user_id) AS user_id,
+                COALESCE(l.points, 0) AS expected,
+                COALESCE(up.total_points, 0)::bigint AS actual
+         FROM user_points up
+         FULL JOIN (
+             SELECT user_id, SUM(delta)::bigint AS points FROM points_transactions GROUP BY user_id
+         ) l ON l.user_id = up.user_id
+         WHERE COALESCE(l.points, 0) <> COALESCE(up.total_points, 0)
+         ORDER BY 1`,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get total points drift: %w", err)
+	}
+
+	return drift, nil
+}
+
+// RecalculateTotals переписывает user_points суммами журнала. Возвращает число исправленных строк.
+func (r *PointsRepository) RecalculateTotals(ctx context.Context) (int64, error) {
+	updated, err := r.db.Exec(ctx,
+		`INSERT INTO user_points (user_id, total_points)
+         SELECT user_id, SUM(delta) FROM points_transactions GROUP BY user_id
+         ON CONFLICT (user_id) DO UPDATE SET total_points = EXCLUDED.total_points
+         WHERE user_points.total_points <> EXCLUDED.total_points`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not recalculate points totals: %w", err)
+	}
+
+	cleared, err := r.db.Exec(ctx,
+		`UPDATE user_points SET total_points = 0
+         WHERE total_points <> 0
+           AND NOT EXISTS (SELECT 1 FROM points_transactions pt WHERE pt.user_id = user_points.user_id)`,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not recalculate points totals: %w", err)
+	}
+
+	return updated.RowsAffected() + cleared.RowsAffected(), nil
+}
@@ -1,10 +1,12 @@
 package repositories
 
 import (
+	"bobri/internal/models"
 	"context"
 	"fmt"
 	"time"
 
+	"github.com/georgysavva/scany/v2/pgxscan"
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
@@ -25,24 +27,25 @@ func (r *RefreshTokensRepository) WithDB(db DBTX) *RefreshTokensRepository {
 	return &RefreshTokensRepository{db: db}
 }
 
-// GetUserIdByRefreshToken - возвращает ID пользователя по хешу refresh-токена.
-// Если запись не найдена - возвращает userId = 0 и err = nil.
-func (r *RefreshTokensRepository) GetUserIdByRefreshToken(ctx context.Context, refreshTokenHash []byte) (int64, error) {
-	var userId int64
+// GetRefreshTokenForUpdate - возвращает запись refresh-токена по его хешу (в том числе уже
+// обменянную или отозванную) и блокирует её до конца транзакции.
+func (r *RefreshTokensRepository) GetRefreshTokenForUpdate(ctx context.Context, refreshTokenHash []byte) (models.RefreshSession, error) {
+	var token models.RefreshSession
 
-	err := r.db.QueryRow(ctx,
-		`SELECT user_id
+	err := pgxscan.Get(ctx, r.db, &token,
+		`SELECT id, user_id, family_id, parent_id, expires_at, rotated_at, revoked_at
          FROM refresh_tokens
-         WHERE token_hash = $1 AND expires_at > NOW()`,
+         WHERE token_hash = $1
+         FOR UPDATE`,
 		refreshTokenHash,
-	).Scan(&userId)
+	)
 
 	// Обработка ошибок
 	if err != nil {
-		return 0, fmt.Errorf("could not get user id: %w", err)
+		return models.RefreshSession{}, fmt.Errorf("could not get refresh token: %w", err)
 	}
 
-	return userId, nil
+	return token, nil
 }
 
 // GetRoleLevelByUserId - возвращает уровень роли пользователя.
@@ -63,46 +66,203 @@ func (r *RefreshTokensRepository) GetRoleLevelByUserId(ctx context.Context, user
 	return level, nil
 }
 
-// UpdateRefreshToken - обновляет refresh-токен пользователя (обычно внутри транзакции).
-func (r *RefreshTokensRepository) UpdateRefreshToken(ctx context.Context, userId int64, newRefreshToken []byte) (pgconn.CommandTag, error) {
+// GetTokenVersion - возвращает текущую версию токенов пользователя (users.token_version).
+func (r *RefreshTokensRepository) GetTokenVersion(ctx context.Context, userId int64) (int64, error) {
+	var version int64
+
+	err := r.db.QueryRow(ctx,
+		`SELECT token_version
+         FROM users
+         WHERE id = $1`,
+		userId,
+	).Scan(&version)
+
+	if err != nil {
+		return 0, fmt.Errorf("could not get user token_version: %w", err)
+	}
+
+	return version, nil
+}
+
+// GetAccessState - возвращает данные для проверки access токена: текущую версию токенов
+// пользователя и то, активна ли еще сессия, из которой выдан токен.
+// Если пользователь удален - возвращает pgx.ErrNoRows.
+func (r *RefreshTokensRepository) GetAccessState(ctx context.Context, userId, sessionId int64) (models.AccessState, error) {
+	var state models.AccessState
+
+	err := r.db.QueryRow(ctx,
+		`SELECT u.token_version,
+                EXISTS(SELECT 1
+                       FROM refresh_tokens rt
+                       WHERE rt.user_id = u.id AND rt.family_id = $2
+                         AND rt.rotated_at IS NULL AND rt.revoked_at IS NULL
+                         AND rt.expires_at > NOW())
+         FROM users u
+         WHERE u.id = $1`,
+		userId,
+		sessionId,
+	).Scan(&state.TokenVersion, &state.SessionActive)
+
+	if err != nil {
+		return models.AccessState{}, fmt.Errorf("could not get access state: %w", err)
+	}
+
+	return state, nil
+}
+
+// RotateRefreshToken - помечает токен parent как обменянный и создаёт в том же семействе новый токен,
+// ссылающийся на него. Возвращает id нового токена (обычно внутри транзакции).
+func (r *RefreshTokensRepository) RotateRefreshToken(ctx context.Context, parentId int64, newRefreshToken []byte, expiresAt time.Time, meta models.SessionMeta) (int64, error) {
 	tag, err := r.db.Exec(ctx,
 		`UPDATE refresh_tokens
-         SET token_hash = $1, expires_at = $2
-         WHERE user_id = $3`,
-		newRefreshToken,
-		time.Now().Add(30*24*time.Hour),
-		userId,
+         SET rotated_at = NOW()
+         WHERE id = $1 AND rotated_at IS NULL AND revoked_at IS NULL`,
+		parentId,
 	)
 	if err != nil || tag.RowsAffected() == 0 {
-		return pgconn.CommandTag{}, fmt.Errorf("could not update refresh token: %w", err)
+		return 0, fmt.Errorf("could not mark refresh token as rotated: %w", err)
+	}
+
+	var id int64
+	err = r.db.QueryRow(ctx,
+		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at, created_at, family_id, parent_id,
+                                     device_name, user_agent, ip)
+         SELECT user_id, $2, $3, created_at, family_id, id,
+                COALESCE(NULLIF($4, ''), device_name), $5, $6
+         FROM refresh_tokens
+         WHERE id = $1
+         RETURNING id`,
+		parentId,
+		newRefreshToken,
+		expiresAt,
+		meta.DeviceName,
+		meta.UserAgent,
+		meta.IP,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("could not create rotated refresh token: %w", err)
+	}
+	return id, nil
+}
+
+// RevokeFamily - отзывает все токены семейства (сессии). Записи остаются, чтобы
+// повторные попытки использовать старые токены по-прежнему распознавались.
+func (r *RefreshTokensRepository) RevokeFamily(ctx context.Context, familyId int64) (pgconn.CommandTag, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE refresh_tokens
+         SET revoked_at = NOW()
+         WHERE family_id = $1 AND revoked_at IS NULL`,
+		familyId,
+	)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("could not revoke refresh token family: %w", err)
 	}
 	return tag, nil
 }
 
-// DeleteRefreshTokens - удаляет все refresh-токены пользователя (обычно внутри транзакции).
-func (r *RefreshTokensRepository) DeleteRefreshTokens(ctx context.Context, userId int64) (pgconn.CommandTag, error) {
+// DeleteRefreshTokens - удаляет все refresh-токены пользователя, т.е. завершает все его сессии.
+// Возвращает количество завершенных активных сессий.
+func (r *RefreshTokensRepository) DeleteRefreshTokens(ctx context.Context, userId int64) (int64, error) {
+	var count int64
+
+	err := r.db.QueryRow(ctx,
+		`WITH deleted AS (
+             DELETE FROM refresh_tokens
+             WHERE user_id = $1
+             RETURNING rotated_at, revoked_at, expires_at
+         )
+         SELECT COUNT(*) FROM deleted
+         WHERE rotated_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()`,
+		userId,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("could not delete refresh tokens: %w", err)
+	}
+	return count, nil
+}
+
+// DeleteOtherSessions - удаляет все сессии пользователя, кроме указанной.
+func (r *RefreshTokensRepository) DeleteOtherSessions(ctx context.Context, userId, keepSessionId int64) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM refresh_tokens
+         WHERE user_id = $1 AND family_id <> $2`,
+		userId,
+		keepSessionId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not delete other sessions: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpiredRefreshTokens - удаляет истекшие refresh-токены пользователя вместе с историей ротаций.
+func (r *RefreshTokensRepository) DeleteExpiredRefreshTokens(ctx context.Context, userId int64) error {
+	_, err := r.db.Exec(ctx,
+		`DELETE FROM refresh_tokens
+         WHERE user_id = $1 AND expires_at <= NOW()`,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not delete expired refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// DeleteSession - удаляет одну сессию пользователя (всё семейство токенов).
+func (r *RefreshTokensRepository) DeleteSession(ctx context.Context, userId, sessionId int64) (pgconn.CommandTag, error) {
 	tag, err := r.db.Exec(ctx,
 		`DELETE FROM refresh_tokens
-         WHERE user_id = $1`,
+         WHERE user_id = $1 AND family_id = $2`,
 		userId,
+		sessionId,
 	)
-	if err != nil || tag.RowsAffected() == 0 {
-		return pgconn.CommandTag{}, fmt.Errorf("could not delete refresh token: %w", err)
+	if err != nil {
+		return pgconn.CommandTag{}, fmt.Errorf("could not delete session: %w", err)
 	}
 	return tag, nil
 }
 
-// CreateRefreshToken - создаёт новый refresh-токен (обычно внутри транзакции).
-func (r *RefreshTokensRepository) CreateRefreshToken(ctx context.Context, userId int64, refreshTokenHash []byte, expiresAt time.Time) (pgconn.CommandTag, error) {
-	tag, err := r.db.Exec(ctx,
-		`INSERT INTO refresh_tokens (user_id, token_hash, expires_at)
-         VALUES ($1, $2, $3)`,
+// CreateRefreshToken - создаёт новую сессию (корень семейства токенов) и возвращает её id (обычно внутри транзакции).
+func (r *RefreshTokensRepository) CreateRefreshToken(ctx context.Context, userId int64, refreshTokenHash []byte, expiresAt time.Time, meta models.SessionMeta) (int64, error) {
+	var id int64
+
+	err := r.db.QueryRow(ctx,
+		`WITH new_token AS (
+             SELECT nextval(pg_get_serial_sequence('refresh_tokens', 'id')) AS id
+         )
+         INSERT INTO refresh_tokens (id, family_id, user_id, token_hash, expires_at, device_name, user_agent, ip)
+         SELECT id, id, $1, $2, $3, $4, $5, $6
+         FROM new_token
+         RETURNING id`,
 		userId,
 		refreshTokenHash,
 		expiresAt,
+		meta.DeviceName,
+		meta.UserAgent,
+		meta.IP,
+	).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("could not create refresh token: %w", err)
+	}
+	return id, nil
+}
+
+// GetSessions - возвращает активные сессии пользователя, начиная с последней использованной.
+// Сессия представлена последним токеном семейства, id сессии - family_id.
+func (r *RefreshTokensRepository) GetSessions(ctx context.Context, userId int64) ([]models.Session, error) {
+	var sessions []models.Session
+
+	err := pgxscan.Select(ctx, r.db, &sessions,
+		`SELECT family_id AS id, device_name, user_agent, ip, created_at, last_used_at, expires_at
+         FROM refresh_tokens
+         WHERE user_id = $1
+           AND rotated_at IS NULL AND revoked_at IS NULL
+           AND expires_at > NOW()
+         ORDER BY last_used_at DESC`,
+		userId,
 	)
-	if err != nil || tag.RowsAffected() == 0 {
-		return pgconn.CommandTag{}, fmt.Errorf("could not create refresh token: %w", err)
+	if err != nil {
+		return nil, fmt.Errorf("could not get sessions: %w", err)
 	}
-	return tag, nil
+	return sessions, nil
 }
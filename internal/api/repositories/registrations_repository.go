@@ -0,0 +1,208 @@
+package repositories
+
+import (
+	"bobri/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/georgysavva/scany/v2/pgxscan"
+)
+
+// RegistrationsRepository отвечает за запись студентов на события и лист ожидания.
+// Все изменения записей одного события выполняются после LockEvent, поэтому
+// одновременные записи не превышают число мест.
+type RegistrationsRepository struct {
+	db DBTX
+}
+
+// NewRegistrationsRepository создает новый экземпляр RegistrationsRepository.
+func NewRegistrationsRepository(db DBTX) *RegistrationsRepository {
+	return &RegistrationsRepository{db: db}
+}
+
+// WithDB возвращает копию репозитория, использующую новый DBTX (tx или pool).
+func (r *RegistrationsRepository) WithDB(db DBTX) *RegistrationsRepository {
+	return &RegistrationsRepository{db: db}
+}
+
+// LockEvent блокирует строку события до конца транзакции и возвращает его статус и настройки записи.
+// Если события нет или оно удалено - pgx.ErrNoRows.
+func (r *RegistrationsRepository) LockEvent(ctx context.Context, eventId int64) (models.EventLock, error) {
+	var settings models.EventLock
+
+	err := pgxscan.Get(ctx, r.db, &settings,
+		`SELECT status, capacity, registration_opens_at, registration_closes_at
+         FROM events WHERE id = $1 AND deleted_at IS NULL
+         FOR UPDATE`,
+		eventId,
+	)
+	if err != nil {
+		return settings, fmt.Errorf("could not lock event: %w", err)
+	}
+
+	return settings, nil
+}
+
+// CountRegistered возвращает число занятых мест на событии.
+func (r *RegistrationsRepository) CountRegistered(ctx context.Context, eventId int64) (int, error) {
+	var count int
+
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM event_registrations WHERE event_id = $1 AND status = 'registered'`,
+		eventId,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("could not count registrations: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateRegistration записывает пользователя на событие с указанным статусом.
+// Повторная запись нарушает первичный ключ (23505).
+func (r *RegistrationsRepository) CreateRegistration(ctx context.Context, eventId, userId int64, status string) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO event_registrations (event_id, user_id, status) VALUES ($1, $2, $3)`,
+		eventId, userId, status,
+	)
+	if err != nil {
+		return fmt.Errorf("could not create registration: %w", err)
+	}
+
+	return nil
+}
+
+// GetRegistration возвращает запись пользователя на событие. Если записи нет - pgx.ErrNoRows.
+func (r *RegistrationsRepository) GetRegistration(ctx context.Context, eventId, userId int64) (models.RegistrationState, error) {
+	var reg models.RegistrationState
+
+	err := pgxscan.Get(ctx, r.db, &reg,
+		`SELECT event_id, user_id, status, created_at, attended_at
+         FROM event_registrations WHERE event_id = $1 AND user_id = $2`,
+		eventId, userId,
+	)
+	if err != nil {
+		return reg, fmt.Errorf("could not get registration: %w", err)
+	}
+
+	return reg, nil
+}
+
+// WaitlistPosition возвращает место пользователя в листе ожидания, начиная с 1.
+func (r *RegistrationsRepository) WaitlistPosition(ctx context.Context, eventId, userId int64) (int, error) {
+	var position int
+
+	err := r.db.QueryRow(ctx,
+		`SELECT COUNT(*) FROM event_registrations w
+         JOIN event_registrations me ON me.event_id = w.event_id AND me.user_id = $2
+         WHERE w.event_id = $1 AND w.status = 'waitlisted'
+           AND (w.created_at, w.user_id) <= (me.created_at, me.user_id)`,
+		eventId, userId,
+	).Scan(&position)
+	if err != nil {
+		return 0, fmt.Errorf("could not get waitlist position: %w", err)
+	}
+
+	return position, nil
+}
+
+// DeleteRegistration удаляет запись пользователя. Возвращает false, если записи не было.
+func (r *RegistrationsRepository) DeleteRegistration(ctx context.Context, eventId, userId int64) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`DELETE FROM event_registrations WHERE event_id = $1 AND user_id = $2`,
+		eventId, userId,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not delete registration: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// PromoteWaitlist переводит из листа ожидания столько студентов, сколько на событии свободных мест,
+// в порядке записи. Без ограничения мест переводятся все. Возвращает id переведенных пользователей.
+func (r *RegistrationsRepository) PromoteWaitlist(ctx context.Context, eventId int64) ([]int64, error) {
+	var promoted []int64
+
+	err := pgxscan.Select(ctx, r.db, &promoted,
+		`WITH free AS (
+             SELECT CASE WHEN e.capacity IS NULL THEN NULL
+                         ELSE GREATEST(e.capacity - (SELECT COUNT(*) FROM event_registrations
+                                                     WHERE event_id = e.id AND status = 'registered'), 0)
+                    END AS places
+             FROM events e WHERE e.id = $1
+         ), promote AS (
+             SELECT user_id FROM event_registrations
+             WHERE event_id = $1 AND status = 'waitlisted'
+             ORDER BY created_at, user_id
+             LIMIT (SELECT places FROM free)
+         )
+         UPDATE event_registrations r SET status = 'registered'
+         FROM promote
+         WHERE r.event_id = $1 AND r.user_id = promote.user_id
+         RETURNING r.user_id`,
+		eventId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not promote waitlist: %w", err)
+	}
+
+	return promoted, nil
+}
+
+// GetAttendees возвращает записавшихся на событие: сначала получившие место, затем лист ожидания по порядку.
+func (r *RegistrationsRepository) GetAttendees(ctx context.Context, eventId int64) ([]models.Attendee, error) {
+	attendees := []models.Attendee{}
+
+	err := pgxscan.Select(ctx, r.db, &attendees,
+		`SELECT r.user_id, u.name, u.surname, u.email, r.status, r.created_at, r.attended_at,
+                CASE WHEN r.status = 'waitlisted'
+                     THEN ROW_NUMBER() OVER (PARTITION BY r.status ORDER BY r.created_at, r.user_id)
+                     ELSE 0 END AS waitlist_position,
+                ce.user_id IS NOT NULL AS completed
+         FROM event_registrations r
+         JOIN users u ON u.id = r.user_id
+         LEFT JOIN completed_events ce ON ce.user_id = r.user_id AND ce.event_id = r.event_id
+         WHERE r.event_id = $1
+         ORDER BY r.status, r.created_at, r.user_id`,
+		eventId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get attendees: %w", err)
+	}
+
+	return attendees, nil
+}
+
+// MarkAttended отмечает посещение у записанных (не из листа ожидания) пользователей из списка
+// и возвращает тех, у кого отметка поставлена.
+func (r *RegistrationsRepository) MarkAttended(ctx context.Context, eventId int64, userIds []int64) ([]int64, error) {
+	var marked []int64
+
+	err := pgxscan.Select(ctx, r.db, &marked,
+		`UPDATE event_registrations SET attended_at = COALESCE(attended_at, now())
+         WHERE event_id = $1 AND user_id = ANY($2) AND status = 'registered'
+         RETURNING user_id`,
+		eventId, userIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not mark attendance: %w", err)
+	}
+
+	return marked, nil
+}
+
+// GetRegisteredUserIds возвращает id всех получивших место на событии.
+func (r *RegistrationsRepository) GetRegisteredUserIds(ctx context.Context, eventId int64) ([]int64, error) {
+	var ids []int64
+
+	err := pgxscan.Select(ctx, r.db, &ids,
+		`SELECT user_id FROM event_registrations WHERE event_id = $1 AND status = 'registered' ORDER BY user_id`,
+		eventId,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get registered users: %w", err)
+	}
+
+	return ids, nil
+}
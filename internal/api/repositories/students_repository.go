@@ -33,9 +33,11 @@ func (r *StudentsRepository) GetStudentByBookId(ctx context.Context, bookId int6
 	var student models.Student
 
 	err := r.db.QueryRow(ctx,
-		`SELECT id, book_id, surname, name, middle_name, birth_date, student_group
-         FROM students
-         WHERE book_id = $1`,
+		`SELECT s.id, s.book_id, s.surname, s.name, s.middle_name, s.birth_date,
+                COALESCE(s.student_group_id, 0), COALESCE(sg.name, '')
+         FROM students s
+         LEFT JOIN student_groups sg ON sg.id = s.student_group_id
+         WHERE s.book_id = $1`,
 		bookId,
 	).Scan(
 		&student.Id,
@@ -44,6 +46,7 @@ func (r *StudentsRepository) GetStudentByBookId(ctx context.Context, bookId int6
 		&student.Name,
 		&student.MiddleName,
 		&student.BirthDate,
+		&student.StudentGroupId,
 		&student.StudentGroup,
 	)
 	if err != nil {
@@ -113,11 +116,158 @@ func (r *StudentsRepository) GetAllStudents(ctx context.Context, limit int) ([]m
 	var students []models.Student
 
 	err := pgxscan.Select(ctx, r.db, &students,
-		`SELECT id, book_id, surname, name, middle_name, birth_date, student_group
-         FROM students limit $1`, limit)
+		`SELECT s.id, s.book_id, s.surname, s.name, s.middle_name, s.birth_date,
+                COALESCE(s.student_group_id, 0) as student_group_id,
+                COALESCE(sg.name, '') as student_group,
+                s.archived_at
+         FROM students s
+         LEFT JOIN student_groups sg ON sg.id = s.student_group_id
+         limit $1`, limit)
 	if err != nil {
 		return students, fmt.Errorf("could not get students: %w", err)
 	}
 
 	return students, nil
 }
+
+// GetStudentsByBookIds возвращает студентов с указанными номерами зачетных книжек
+// вместе с признаком зарегистрированного аккаунта. Строки блокируются до конца транзакции.
+func (r *StudentsRepository) GetStudentsByBookIds(ctx context.Context, bookIds []int64) ([]models.RosterStudent, error) {
+	var students []models.RosterStudent
+
+	err := pgxscan.Select(ctx, r.db, &students,
+		`SELECT s.id, s.book_id, s.surname, s.name, s.middle_name,
+                COALESCE(s.birth_date, TO_DATE('1970-01-01','YYYY-MM-DD')) as birth_date,
+                COALESCE(s.student_group_id, 0) as student_group_id,
+                COALESCE(sg.name, '') as student_group,
+                s.birth_date IS NOT NULL as birth_date_set,
+                EXISTS(SELECT 1 FROM users u WHERE u.book_id = s.book_id) as registered
+         FROM students s
+         LEFT JOIN student_groups sg ON sg.id = s.student_group_id
+         WHERE s.book_id = ANY($1)
+         FOR UPDATE OF s`,
+		bookIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get students: %w", err)
+	}
+
+	return students, nil
+}
+
+// CreateStudent добавляет студента в реестр.
+func (r *StudentsRepository) CreateStudent(ctx context.Context, student models.Student) error {
+	_, err := r.db.Exec(ctx,
+		`INSERT INTO students (book_id, surname, name, middle_name, birth_date, student_group_id)
+         VALUES ($1, $2, $3, $4, $5, NULLIF($6::int, 0))`,
+		student.BookId,
+		student.Surname,
+		student.Name,
+		student.MiddleName,
+		student.BirthDate,
+		student.StudentGroupId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not create student: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStudent перезаписывает данные студента с идентификатором id, включая номер зачетной книжки.
+func (r *StudentsRepository) UpdateStudent(ctx context.Context, id int64, student models.Student) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE students
+         SET book_id = $2, surname = $3, name = $4, middle_name = $5, birth_date = $6,
+             student_group_id = NULLIF($7::int, 0)
+         WHERE id = $1`,
+		id,
+		student.BookId,
+		student.Surname,
+		student.Name,
+		student.MiddleName,
+		student.BirthDate,
+		student.StudentGroupId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not update student: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not update student: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// DeleteLinkTokens удаляет токены привязки студента (нужно перед сменой номера зачетной книжки).
+func (r *StudentsRepository) DeleteLinkTokens(ctx context.Context, bookId int64) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM link_tokens WHERE book_id = $1`, bookId)
+	if err != nil {
+		return fmt.Errorf("could not delete link tokens: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteStudent удаляет студента из реестра, токены привязки удаляются каскадно.
+func (r *StudentsRepository) DeleteStudent(ctx context.Context, bookId int64) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM students WHERE book_id = $1`, bookId)
+	if err != nil {
+		return fmt.Errorf("could not delete student: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not delete student: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// ApplyGroupMapping переводит действующих студентов из групп fromIds в соответствующие группы toIds
+// и возвращает количество переведенных по каждой исходной группе.
+// Перевод выполняется одним запросом, поэтому цепочки (1 -> 2, 2 -> 3) не складываются.
+func (r *StudentsRepository) ApplyGroupMapping(ctx context.Context, fromIds, toIds []int64) (map[int64]int64, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH moved AS (
+             UPDATE students s
+             SET student_group_id = m.to_id
+             FROM unnest($1::bigint[], $2::bigint[]) AS m(from_id, to_id)
+             WHERE s.student_group_id = m.from_id
+               AND s.archived_at IS NULL
+             RETURNING m.from_id
+         )
+         SELECT from_id, count(*) FROM moved GROUP BY from_id`,
+		fromIds, toIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not apply group mapping to students: %w", err)
+	}
+	defer rows.Close()
+
+	moved := make(map[int64]int64, len(fromIds))
+	for rows.Next() {
+		var fromId, count int64
+		if err = rows.Scan(&fromId, &count); err != nil {
+			return nil, fmt.Errorf("could not scan group mapping result: %w", err)
+		}
+		moved[fromId] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not apply group mapping to students: %w", err)
+	}
+
+	return moved, nil
+}
+
+// ArchiveGroups помечает действующих студентов указанных групп выпускниками.
+func (r *StudentsRepository) ArchiveGroups(ctx context.Context, groupIds []int64, at time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE students SET archived_at = $2
+         WHERE student_group_id = ANY($1) AND archived_at IS NULL`,
+		groupIds, at,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not archive students: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
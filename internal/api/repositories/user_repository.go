@@ -6,9 +6,11 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/georgysavva/scany/v2/pgxscan"
+	"github.com/jackc/pgx/v5"
 )
 
 // UserRepository отвечает за работу с таблицей users и связанными сущностями.
@@ -38,6 +40,7 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (mode
 		        middle_name,
 		        password,
 		        email,
+		        email_verified_at IS NOT NULL as email_verified,
 		        role_level,
 		        avatar
 		 FROM users
@@ -51,6 +54,35 @@ func (r *UserRepository) GetUserByEmail(ctx context.Context, email string) (mode
 	return user, err
 }
 
+// GetUserById возвращает пользователя по id.
+func (r *UserRepository) GetUserById(ctx context.Context, userId int64) (models.User, error) {
+	var user models.User
+
+	err := pgxscan.Get(ctx, r.db, &user,
+		`SELECT u.id,
+		        COALESCE(u.book_id, 0) as book_id,
+		        u.name,
+		        u.surname,
+		        u.middle_name,
+		        COALESCE(u.student_group_id, 0) as student_group_id,
+		        COALESCE(sg.name, '') as student_group,
+		        u.password,
+		        u.email,
+		        u.email_verified_at IS NOT NULL as email_verified,
+		        u.role_level,
+		        u.avatar
+		 FROM users u
+		 LEFT JOIN student_groups sg ON sg.id = u.student_group_id
+		 WHERE u.id = $1`,
+		userId,
+	)
+	if err != nil {
+		return user, fmt.Errorf("could not get user: %w", err)
+	}
+
+	return user, err
+}
+
 // GetStudentByBookId возвращает студента по номеру зачетной книжки.
 // Этот метод по смыслу не должен быть здесь, но оставляем для совместимости.
 // В StudentsRepository он уже реализован.
@@ -58,15 +90,17 @@ func (r *UserRepository) GetStudentByBookId(ctx context.Context, bookId int64) (
 	var student models.Student
 
 	err := pgxscan.Get(ctx, r.db, &student,
-		`SELECT id,
-		        book_id,
-		        surname,
-		        name,
-		        middle_name,
-		        birth_date,
-		        student_group
-		 FROM students
-		 WHERE book_id = $1`,
+		`SELECT s.id,
+		        s.book_id,
+		        s.surname,
+		        s.name,
+		        s.middle_name,
+		        s.birth_date,
+		        COALESCE(s.student_group_id, 0) as student_group_id,
+		        COALESCE(sg.name, '') as student_group
+		 FROM students s
+		 LEFT JOIN student_groups sg ON sg.id = s.student_group_id
+		 WHERE s.book_id = $1`,
 		bookId,
 	)
 	if err != nil {
@@ -76,20 +110,70 @@ func (r *UserRepository) GetStudentByBookId(ctx context.Context, bookId int64) (
 	return student, err
 }
 
+// SyncStudentData переносит данные студента из реестра в привязанный к нему аккаунт (по старому номеру зачетки).
+// Возвращает false, если аккаунт по этой зачетке не зарегистрирован.
+func (r *UserRepository) SyncStudentData(ctx context.Context, oldBookId int64, student models.Student) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users
+         SET book_id = $2, surname = $3, name = $4, middle_name = $5, birth_date = $6,
+             student_group_id = NULLIF($7::int, 0)
+         WHERE book_id = $1`,
+		oldBookId,
+		student.BookId,
+		student.Surname,
+		student.Name,
+		student.MiddleName,
+		student.BirthDate,
+		student.StudentGroupId,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not sync student data: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetUserIdByBookId возвращает id аккаунта, привязанного к зачетке. Если аккаунта нет - pgx.ErrNoRows.
+func (r *UserRepository) GetUserIdByBookId(ctx context.Context, bookId int64) (int64, error) {
+	var id int64
+
+	err := r.db.QueryRow(ctx, `SELECT id FROM users WHERE book_id = $1`, bookId).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("could not get user by book id: %w", err)
+	}
+
+	return id, nil
+}
+
+// DetachStudent отвязывает аккаунт от зачетки и отзывает выданные ему токены.
+func (r *UserRepository) DetachStudent(ctx context.Context, bookId int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE users
+         SET book_id = NULL, token_version = token_version + 1
+         WHERE book_id = $1`,
+		bookId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not detach student: %w", err)
+	}
+
+	return nil
+}
+
 // CreateUser создает нового пользователя и возвращает его id.
 func (r *UserRepository) CreateUser(ctx context.Context, user models.User) (int64, error) {
 	var id int64
 
 	err := r.db.QueryRow(ctx,
-		`INSERT INTO users (book_id, name, surname, middle_name, student_group, birth_date,
+		`INSERT INTO users (book_id, name, surname, middle_name, student_group_id, birth_date,
 		                    password, email, role_level, avatar)
-         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+         VALUES ($1, $2, $3, $4, NULLIF($5::int, 0), $6, $7, $8, $9, $10)
          RETURNING id`,
 		user.BookId,
 		user.Name,
 		user.Surname,
 		user.MiddleName,
-		user.StudentGroup,
+		user.StudentGroupId,
 		user.BirthDate,
 		user.Password,
 		user.Email,
@@ -135,19 +219,21 @@ func (r *UserRepository) GetUsersWithMaxRole(ctx context.Context, maxRole int64,
 	var users []models.User
 
 	err := pgxscan.Select(ctx, r.db, &users,
-		`SELECT id,
-		        COALESCE(book_id, 0) as book_id,
-		        surname,
-		        name,
-		        middle_name,
-		        COALESCE(birth_date, '1970-01-01'::timestamp) as birth_date,
-		        COALESCE(student_group, '') as student_group,
-		        password,
-		        email,
-		        role_level,
-		        avatar
-		 FROM users
-		 WHERE role_level <= $1 limit $2`,
+		`SELECT u.id,
+		        COALESCE(u.book_id, 0) as book_id,
+		        u.surname,
+		        u.name,
+		        u.middle_name,
+		        COALESCE(u.birth_date, '1970-01-01'::timestamp) as birth_date,
+		        COALESCE(u.student_group_id, 0) as student_group_id,
+		        COALESCE(sg.name, '') as student_group,
+		        u.password,
+		        u.email,
+		        u.role_level,
+		        u.avatar
+		 FROM users u
+		 LEFT JOIN student_groups sg ON sg.id = u.student_group_id
+		 WHERE u.role_level <= $1 limit $2`,
 		maxRole, limit)
 
 	return users, err
@@ -158,17 +244,28 @@ func (r *UserRepository) GetProfileByUserID(ctx context.Context, userID int64) (
 	var profile models.ProfileResponse
 
 	err := pgxscan.Get(ctx, r.db, &profile,
-		`SELECT COALESCE(book_id, 0) as book_id,
-		        name,
-		        surname,
-		        middle_name,
-		        COALESCE(birth_date, TO_DATE('1970-01-01','YYYY-MM-DD')) as birth_date,
-		        COALESCE(student_group, '') as student_group,
-		        email,
-		        role_level,
-		        avatar
-		 FROM users
-		 WHERE id = $1`,
+		`SELECT COALESCE(u.book_id, 0) as book_id,
+		        u.name,
+		        u.surname,
+		        u.middle_name,
+		        COALESCE(u.birth_date, TO_DATE('1970-01-01','YYYY-MM-DD')) as birth_date,
+		        COALESCE(sg.name, '') as student_group,
+		        COALESCE(sg.id, 0) as student_group_id,
+		        COALESCE(st.id, 0) as study_id,
+		        COALESCE(st.name, '') as study,
+		        COALESCE(i.id, 0) as institute_id,
+		        COALESCE(i.name, '') as institute,
+		        u.email,
+		        u.email_verified_at IS NOT NULL as email_verified,
+		        u.role_level,
+		        u.avatar,
+		        u.deletion_scheduled_at,
+		        u.archived_at
+		 FROM users u
+		 LEFT JOIN student_groups sg ON sg.id = u.student_group_id
+		 LEFT JOIN studies st ON st.id = sg.studies_id
+		 LEFT JOIN institutes i ON i.id = st.institute_id
+		 WHERE u.id = $1`,
 		userID,
 	)
 
@@ -234,14 +331,18 @@ func (r *UserRepository) UpdateUser(ctx context.Context, req models.UpdateUserRe
 	if req.NewData.MiddleName != "" {
 		builder = builder.Set("middle_name", req.NewData.MiddleName)
 	}
-	if req.NewData.StudentGroup != "" {
-		builder = builder.Set("student_group", req.NewData.StudentGroup)
+	if req.NewData.StudentGroupId != 0 {
+		builder = builder.Set("student_group_id", req.NewData.StudentGroupId)
 	}
 	if req.NewData.Email != "" {
 		builder = builder.Set("email", req.NewData.Email)
+		// новую почту нужно подтвердить заново, прежнее подтверждение остается только для той же почты
+		builder = builder.Set("email_verified_at", sq.Expr("CASE WHEN email = ? THEN email_verified_at END", req.NewData.Email))
 	}
 	if req.NewData.RoleLevel != 0 {
 		builder = builder.Set("role_level", req.NewData.RoleLevel)
+		// отзываем выданные access токены, чтобы новая роль применилась сразу
+		builder = builder.Set("token_version", sq.Expr("token_version + 1"))
 	}
 	if req.NewData.Avatar != "" {
 		builder = builder.Set("avatar", req.NewData.Avatar)
@@ -279,8 +380,9 @@ func (r *UserRepository) GetUserPoints(ctx context.Context, userID int64) (int64
 	return points, err
 }
 
-// GetLeaderboard возвращает список пользователей с максимальными баллами.
-func (r *UserRepository) GetLeaderboard(ctx context.Context, limit int) ([]models.UserWithPoints, error) {
+// GetLeaderboard возвращает список пользователей с максимальными баллами. Выпускники в него не попадают.
+// Фильтр ограничивает выдачу институтом, направлением или группой, места считаются внутри выборки.
+func (r *UserRepository) GetLeaderboard(ctx context.Context, filter models.LeaderboardFilter) ([]models.UserWithPoints, error) {
 	var users []models.UserWithPoints
 
 	query :=
@@ -292,10 +394,17 @@ func (r *UserRepository) GetLeaderboard(ctx context.Context, limit int) ([]model
 		        ROW_NUMBER() OVER (ORDER BY up.total_points DESC) as position
          FROM user_points up
          JOIN users u ON up.user_id = u.id
+         LEFT JOIN student_groups sg ON sg.id = u.student_group_id
+         LEFT JOIN studies st ON st.id = sg.studies_id
+         WHERE u.deleted_at IS NULL
+           AND u.archived_at IS NULL
+           AND ($2 = 0 OR st.institute_id = $2)
+           AND ($3 = 0 OR sg.studies_id = $3)
+           AND ($4 = 0 OR u.student_group_id = $4)
          ORDER BY up.total_points DESC
          LIMIT $1`
 
-	err := pgxscan.Select(ctx, r.db, &users, query, limit)
+	err := pgxscan.Select(ctx, r.db, &users, query, filter.Limit, filter.InstituteId, filter.StudyId, filter.StudentGroupId)
 	if err != nil {
 		return nil, fmt.Errorf("could not get leaderboard: %w", err)
 	}
@@ -303,12 +412,176 @@ func (r *UserRepository) GetLeaderboard(ctx context.Context, limit int) ([]model
 	return users, err
 }
 
+// ApplyGroupMapping переводит действующих пользователей из групп fromIds в соответствующие группы toIds
+// и возвращает количество переведенных по каждой исходной группе.
+// Перевод выполняется одним запросом, поэтому цепочки (1 -> 2, 2 -> 3) не складываются.
+func (r *UserRepository) ApplyGroupMapping(ctx context.Context, fromIds, toIds []int64) (map[int64]int64, error) {
+	rows, err := r.db.Query(ctx,
+		`WITH moved AS (
+             UPDATE users u
+             SET student_group_id = m.to_id
+             FROM unnest($1::bigint[], $2::bigint[]) AS m(from_id, to_id)
+             WHERE u.student_group_id = m.from_id
+               AND u.archived_at IS NULL
+               AND u.deleted_at IS NULL
+             RETURNING m.from_id
+         )
+         SELECT from_id, count(*) FROM moved GROUP BY from_id`,
+		fromIds, toIds,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not apply group mapping to users: %w", err)
+	}
+	defer rows.Close()
+
+	moved := make(map[int64]int64, len(fromIds))
+	for rows.Next() {
+		var fromId, count int64
+		if err = rows.Scan(&fromId, &count); err != nil {
+			return nil, fmt.Errorf("could not scan group mapping result: %w", err)
+		}
+		moved[fromId] = count
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not apply group mapping to users: %w", err)
+	}
+
+	return moved, nil
+}
+
+// ArchiveGroups помечает действующих пользователей указанных групп выпускниками.
+func (r *UserRepository) ArchiveGroups(ctx context.Context, groupIds []int64, at time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET archived_at = $2
+         WHERE student_group_id = ANY($1) AND archived_at IS NULL AND deleted_at IS NULL`,
+		groupIds, at,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("could not archive users: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// ScheduleDeletion назначает анонимизацию аккаунта на время at.
+func (r *UserRepository) ScheduleDeletion(ctx context.Context, userId int64, at time.Time) error {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET deletion_scheduled_at = $2 WHERE id = $1 AND deleted_at IS NULL`,
+		userId, at,
+	)
+	if err != nil {
+		return fmt.Errorf("could not schedule deletion: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("could not schedule deletion: %w", pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// CancelDeletion отменяет назначенную анонимизацию. Возвращает false, если она не была назначена.
+func (r *UserRepository) CancelDeletion(ctx context.Context, userId int64) (bool, error) {
+	tag, err := r.db.Exec(ctx,
+		`UPDATE users SET deletion_scheduled_at = NULL
+         WHERE id = $1 AND deletion_scheduled_at IS NOT NULL AND deleted_at IS NULL`,
+		userId,
+	)
+	if err != nil {
+		return false, fmt.Errorf("could not cancel deletion: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// GetUsersDueForDeletion возвращает id пользователей, у которых истек срок на отмену удаления.
+func (r *UserRepository) GetUsersDueForDeletion(ctx context.Context, limit int) ([]int64, error) {
+	var ids []int64
+
+	err := pgxscan.Select(ctx, r.db, &ids,
+		`SELECT id
+         FROM users
+         WHERE deletion_scheduled_at <= NOW() AND deleted_at IS NULL
+         ORDER BY deletion_scheduled_at
+         LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not get users due for deletion: %w", err)
+	}
+
+	return ids, nil
+}
+
+// AnonymizeUser стирает персональные данные пользователя, оставляя строку users,
+// чтобы выполненные события и баллы продолжали учитываться в общей статистике.
+// Выданные токены отзываются через token_version.
+func (r *UserRepository) AnonymizeUser(ctx context.Context, userId int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE users
+         SET book_id = NULL,
+             surname = 'Удален',
+             name = 'Пользователь',
+             middle_name = '',
+             birth_date = NULL,
+             student_group_id = NULL,
+             password = NULL,
+             email = 'deleted:' || id,
+             email_verified_at = NULL,
+             avatar = '',
+             token_version = token_version + 1,
+             deletion_scheduled_at = NULL,
+             deleted_at = NOW()
+         WHERE id = $1 AND deleted_at IS NULL`,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not anonymize user: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteUserAuthData удаляет сессии, секреты 2FA и все токены пользователя.
+func (r *UserRepository) DeleteUserAuthData(ctx context.Context, userId int64) error {
+	for _, query := range []string{
+		`DELETE FROM refresh_tokens WHERE user_id = $1`,
+		`DELETE FROM reset_password_tokens WHERE user_id = $1`,
+		`DELETE FROM email_verification_tokens WHERE user_id = $1`,
+		`DELETE FROM mfa_challenges WHERE user_id = $1`,
+		`DELETE FROM totp_recovery_codes WHERE user_id = $1`,
+		`DELETE FROM user_totp WHERE user_id = $1`,
+	} {
+		if _, err := r.db.Exec(ctx, query, userId); err != nil {
+			return fmt.Errorf("could not delete user auth data: %w", err)
+		}
+	}
+
+	return nil
+}
+
 func (r *UserRepository) UpdatePassword(ctx context.Context, userId int64, hash []byte) error {
 	_, err := r.db.Exec(ctx,
 		`UPDATE users SET password = $1 WHERE id = $2`,
 		hash, userId,
 	)
-	return fmt.Errorf("could not update password: %w", err)
+	if err != nil {
+		return fmt.Errorf("could not update password: %w", err)
+	}
+
+	return nil
+}
+
+// IncrementTokenVersion отзывает выданные пользователю access токены.
+func (r *UserRepository) IncrementTokenVersion(ctx context.Context, userId int64) error {
+	_, err := r.db.Exec(ctx,
+		`UPDATE users SET token_version = token_version + 1 WHERE id = $1`,
+		userId,
+	)
+	if err != nil {
+		return fmt.Errorf("could not increment token version: %w", err)
+	}
+
+	return nil
 }
 
 func (r *UserRepository) GetSuggests(ctx context.Context) ([]models.Event, error) {
@@ -319,9 +592,12 @@ func (r *UserRepository) GetSuggests(ctx context.Context) ([]models.Event, error
         WHERE expires_at < NOW()
         RETURNING event_id
 	)
-	SELECT e.*
+	SELECT e.id, e.title, e.description, e.event_type_code, e.points,
+	       e.icon_url, e.event_date, e.link, e.created_at, e.status, e.deleted_at,
+	       e.capacity, e.registration_opens_at, e.registration_closes_at
 	FROM events e
-	WHERE e.id IN (SELECT event_id FROM suggest_events WHERE event_id NOT IN (SELECT event_id FROM deleted));`)
+	WHERE e.id IN (SELECT event_id FROM suggest_events WHERE event_id NOT IN (SELECT event_id FROM deleted))
+	  AND e.status = 'published' AND e.deleted_at IS NULL;`)
 	if err != nil {
 		return suggests, fmt.Errorf("could not get suggests: %w", err)
 	}
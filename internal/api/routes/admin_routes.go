@@ -1,21 +1,25 @@
 package routes
 
 import (
+	"bobri/internal/api/controllers/audit"
+	"bobri/internal/api/controllers/auth"
+	"bobri/internal/api/controllers/claims"
 	"bobri/internal/api/controllers/events"
+	"bobri/internal/api/controllers/institutes"
+	"bobri/internal/api/controllers/uploads"
 	"bobri/internal/api/controllers/users"
 	"bobri/internal/api/repositories"
 	"bobri/internal/api/services"
 	"bobri/internal/middleware"
+	"bobri/internal/models"
 	"bobri/pkg/helpers"
+	"bobri/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func AdminRoutes(r *gin.Engine, db *pgxpool.Pool, accessJWTMaker *helpers.JWTMaker) {
-	adminHandlersGroup := r.Group("/admin")
-	adminHandlersGroup.Use(middleware.AuthenticationMiddleware(accessJWTMaker, 30))
-
+func AdminRoutes(r *gin.Engine, db *pgxpool.Pool, accessJWTMaker *helpers.JWTMaker, emailAuth models.EmailAuth, verificationPolicy services.EmailVerificationPolicy, fileStorage storage.Storage) {
 	// создаем UoW
 	uow := repositories.NewUoW(db)
 
@@ -24,30 +28,136 @@ func AdminRoutes(r *gin.Engine, db *pgxpool.Pool, accessJWTMaker *helpers.JWTMak
 	completedEventRepo := repositories.NewCompletedEventsRepository(db)
 	userRepo := repositories.NewUserRepository(db)
 	studentRepo := repositories.NewStudentsRepository(db)
+	throttleRepo := repositories.NewAuthThrottleRepository(db)
+	refreshTokensRepo := repositories.NewRefreshTokensRepository(db)
+	mfaRepo := repositories.NewMFARepository(db)
+	emailVerificationRepo := repositories.NewEmailVerificationRepository(db)
+	permissionsRepo := repositories.NewPermissionsRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	impersonationRepo := repositories.NewImpersonationRepository(db)
+	institutesRepo := repositories.NewInstitutesRepository(db)
+	eventTypesRepo := repositories.NewEventTypesRepository(db)
+	claimsRepo := repositories.NewClaimsRepository(db)
+	registrationsRepo := repositories.NewRegistrationsRepository(db)
+	checkinRepo := repositories.NewCheckinRepository(db)
+	pointsRepo := repositories.NewPointsRepository(db)
 
 	// сервисы
-	eventService := services.NewEventService(eventRepo, uow)
-	completedEventService := services.NewCompletedEventsService(completedEventRepo, uow)
-	userService := services.NewUserService(userRepo)
-	studentService := services.NewStudentsService(studentRepo, uow)
+	auditService := services.NewAuditService(auditRepo)
+	eventService := services.NewEventService(eventRepo, registrationsRepo, pointsRepo, auditService, uow)
+	completedEventService := services.NewCompletedEventsService(completedEventRepo, pointsRepo, auditService, uow)
+	userService := services.NewUserService(userRepo, institutesRepo, auditService, uow)
+	throttleService := services.NewAuthThrottleService(throttleRepo, auditService, uow)
+	studentService := services.NewStudentsService(studentRepo, userRepo, institutesRepo, throttleService, auditService, uow)
+	sessionsService := services.NewSessionsService(refreshTokensRepo, impersonationRepo)
+	mfaService := services.NewMFAService(mfaRepo, userRepo, refreshTokensRepo, uow)
+	emailVerificationService := services.NewEmailVerificationService(emailVerificationRepo, userRepo, services.NewEmailProvider(emailAuth), throttleService, verificationPolicy, uow)
+	rolesService := services.NewRolesService(permissionsRepo, userRepo, auditService, uow)
+	tokenProvider := services.NewTokenProvider(accessJWTMaker, refreshTokensRepo, permissionsRepo)
+	rosterService := services.NewRosterService(studentRepo, userRepo, institutesRepo, auditService, uow)
+	impersonationService := services.NewImpersonationService(impersonationRepo, userRepo, tokenProvider, auditService, uow)
+	institutesService := services.NewInstitutesService(institutesRepo, auditService, uow)
+	eventTypesService := services.NewEventTypesService(eventTypesRepo, auditService, uow)
+	claimsService := services.NewClaimsService(claimsRepo, completedEventService, auditService, uow)
+	uploadService := services.NewUploadService(fileStorage, userRepo)
+	registrationsService := services.NewRegistrationsService(registrationsRepo, eventRepo, completedEventRepo, completedEventService, auditService, uow)
+	checkinService := services.NewCheckinService(checkinRepo, eventRepo, registrationsRepo, completedEventRepo, completedEventService, auditService, uow)
+	rolloverService := services.NewRolloverService(institutesRepo, studentRepo, userRepo, auditService, uow)
+	pointsService := services.NewPointsService(pointsRepo, userRepo, auditService, uow)
+
+	// проверка отзыва токенов, обязательной 2FA и подтвержденной почты.
+	// Доступ к конкретным маршрутам определяется правами роли (RequirePermission), а не ее уровнем
+	adminHandlersGroup := r.Group("/admin")
+	adminHandlersGroup.Use(
+		middleware.AuthenticationMiddleware(accessJWTMaker, sessionsService),
+		middleware.MFAEnrollmentMiddleware(mfaService),
+		middleware.EmailVerificationMiddleware(emailVerificationService, services.VerificationAreaAdmin),
+	)
 
 	// users
-	adminHandlersGroup.DELETE("/delete_user/:user_id", users.DeleteUser(userService))
+	adminHandlersGroup.DELETE("/delete_user/:user_id", middleware.RequirePermission(models.PermissionUsersDelete), users.DeleteUser(userService))
+
+	adminHandlersGroup.GET("/students", middleware.RequirePermission(models.PermissionStudentsRead), users.GetStudents(studentService))
+	adminHandlersGroup.POST("/students/import", middleware.RequirePermission(models.PermissionStudentsWrite), users.ImportStudents(rosterService))
+	adminHandlersGroup.POST("/students", middleware.RequirePermission(models.PermissionStudentsWrite), users.CreateStudent(studentService))
+	adminHandlersGroup.PATCH("/students/:book_id", middleware.RequirePermission(models.PermissionStudentsWrite), users.UpdateStudent(studentService))
+	adminHandlersGroup.DELETE("/students/:book_id", middleware.RequirePermission(models.PermissionStudentsWrite), users.DeleteStudent(studentService))
+	adminHandlersGroup.POST("/rollover", middleware.RequirePermission(models.PermissionStudentsWrite), users.Rollover(rolloverService))
+	adminHandlersGroup.GET("/users", middleware.RequirePermission(models.PermissionUsersRead), users.GetUsers(userService))
+	adminHandlersGroup.PATCH("/update_user", middleware.RequirePermission(models.PermissionUsersWrite), users.UpdateUser(userService))
+
+	// структура институтов
+	adminHandlersGroup.POST("/institutes", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.CreateInstitute(institutesService))
+	adminHandlersGroup.PATCH("/institutes/:id", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.UpdateInstitute(institutesService))
+	adminHandlersGroup.DELETE("/institutes/:id", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.DeleteInstitute(institutesService))
+	adminHandlersGroup.POST("/studies", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.CreateStudy(institutesService))
+	adminHandlersGroup.PATCH("/studies/:id", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.UpdateStudy(institutesService))
+	adminHandlersGroup.DELETE("/studies/:id", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.DeleteStudy(institutesService))
+	adminHandlersGroup.POST("/groups", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.CreateGroup(institutesService))
+	adminHandlersGroup.PATCH("/groups/:id", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.UpdateGroup(institutesService))
+	adminHandlersGroup.DELETE("/groups/:id", middleware.RequirePermission(models.PermissionInstitutesWrite), institutes.DeleteGroup(institutesService))
+
+	// роли и права
+	adminHandlersGroup.GET("/permissions", middleware.RequirePermission(models.PermissionRolesManage), users.GetPermissions(rolesService))
+	adminHandlersGroup.GET("/roles", middleware.RequirePermission(models.PermissionRolesManage), users.GetRoles(rolesService))
+	adminHandlersGroup.POST("/roles", middleware.RequirePermission(models.PermissionRolesManage), users.CreateRole(rolesService))
+	adminHandlersGroup.PUT("/roles/:level/permissions", middleware.RequirePermission(models.PermissionRolesManage), users.SetRolePermissions(rolesService))
+
+	// вход от имени пользователя
+	adminHandlersGroup.POST("/impersonate/:user_id", middleware.RequirePermission(models.PermissionUsersImpersonate), users.StartImpersonation(impersonationService))
+	adminHandlersGroup.POST("/impersonations/:id/end", middleware.RequirePermission(models.PermissionUsersImpersonate), users.EndImpersonation(impersonationService))
+	adminHandlersGroup.GET("/impersonations", middleware.RequirePermission(models.PermissionUsersImpersonate), users.GetImpersonations(impersonationService))
 
-	adminHandlersGroup.GET("/students", users.GetStudents(studentService))
-	adminHandlersGroup.GET("/users", users.GetUsers(userService))
-	adminHandlersGroup.PATCH("/update_user", users.UpdateUser(userService))
+	// журнал действий администраторов
+	adminHandlersGroup.GET("/audit", middleware.RequirePermission(models.PermissionAuditRead), audit.GetAuditLog(auditService))
+
+	// блокировки входа
+	adminHandlersGroup.GET("/auth_locks", middleware.RequirePermission(models.PermissionAuthLocksManage), auth.GetAuthLocks(throttleService))
+	adminHandlersGroup.POST("/unlock", middleware.RequirePermission(models.PermissionAuthLocksManage), auth.Unlock(throttleService))
 
 	// events
-	adminHandlersGroup.GET("/events", events.GetEvents(eventService))
-	adminHandlersGroup.POST("/create_event", events.CreateEvent(eventService))
-	adminHandlersGroup.PATCH("/update_event", events.UpdateEvent(eventService))
-	adminHandlersGroup.DELETE("/delete_event/:id", events.DeleteEvent(eventService))
-	adminHandlersGroup.POST("/create_suggest", events.CreateSuggest(eventService))
-	adminHandlersGroup.DELETE("/delete_suggestion/:id", events.DeleteSuggestion(eventService))
+	adminHandlersGroup.GET("/events", middleware.RequirePermission(models.PermissionEventsRead), events.GetEvents(eventService))
+	adminHandlersGroup.GET("/events/search", middleware.RequirePermission(models.PermissionEventsRead), events.SearchEvents(eventService))
+	adminHandlersGroup.POST("/create_event", middleware.RequirePermission(models.PermissionEventsWrite), events.CreateEvent(eventService))
+	adminHandlersGroup.PATCH("/update_event", middleware.RequirePermission(models.PermissionEventsWrite), events.UpdateEvent(eventService))
+	adminHandlersGroup.DELETE("/delete_event/:id", middleware.RequirePermission(models.PermissionEventsWrite), events.DeleteEvent(eventService))
+	adminHandlersGroup.POST("/events/:id/restore", middleware.RequirePermission(models.PermissionEventsWrite), events.RestoreEvent(eventService))
+	adminHandlersGroup.POST("/events/:id/publish", middleware.RequirePermission(models.PermissionEventsWrite), events.PublishEvent(eventService))
+	adminHandlersGroup.POST("/events/:id/archive", middleware.RequirePermission(models.PermissionEventsWrite), events.ArchiveEvent(eventService))
+	adminHandlersGroup.POST("/create_suggest", middleware.RequirePermission(models.PermissionEventsWrite), events.CreateSuggest(eventService))
+	adminHandlersGroup.DELETE("/delete_suggestion/:id", middleware.RequirePermission(models.PermissionEventsWrite), events.DeleteSuggestion(eventService))
+
+	// запись на события и отметка посещения
+	adminHandlersGroup.GET("/events/:id/attendees", middleware.RequirePermission(models.PermissionEventsRead), events.GetAttendees(registrationsService))
+	adminHandlersGroup.POST("/events/:id/attendance", middleware.RequirePermission(models.PermissionCompletedEventsWrite), events.MarkAttendance(registrationsService))
+
+	// отметка по QR коду
+	adminHandlersGroup.GET("/events/:id/checkin", middleware.RequirePermission(models.PermissionEventsRead), events.GetCheckinSettings(checkinService))
+	adminHandlersGroup.PUT("/events/:id/checkin", middleware.RequirePermission(models.PermissionEventsWrite), events.SetCheckinSettings(checkinService))
+	adminHandlersGroup.POST("/events/:id/checkin/token", middleware.RequirePermission(models.PermissionCompletedEventsWrite), events.IssueCheckinToken(checkinService))
+	adminHandlersGroup.POST("/events/:id/checkin/rotate", middleware.RequirePermission(models.PermissionEventsWrite), events.RotateCheckinSecret(checkinService))
+
+	// типы мероприятий
+	adminHandlersGroup.GET("/event_types", middleware.RequirePermission(models.PermissionEventsRead), events.GetEventTypes(eventTypesService))
+	adminHandlersGroup.POST("/event_types", middleware.RequirePermission(models.PermissionEventsWrite), events.CreateEventType(eventTypesService))
+	adminHandlersGroup.PATCH("/event_types/:code", middleware.RequirePermission(models.PermissionEventsWrite), events.UpdateEventType(eventTypesService))
+	adminHandlersGroup.DELETE("/event_types/:code", middleware.RequirePermission(models.PermissionEventsWrite), events.DeleteEventType(eventTypesService))
+
+	// загрузка иконок для событий и типов мероприятий
+	adminHandlersGroup.POST("/uploads/icon", middleware.RequirePermission(models.PermissionEventsWrite), uploads.UploadIcon(uploadService))
+
+	// заявки студентов на баллы
+	adminHandlersGroup.GET("/claims", middleware.RequirePermission(models.PermissionClaimsReview), claims.GetClaims(claimsService))
+	adminHandlersGroup.POST("/claims/:id/approve", middleware.RequirePermission(models.PermissionClaimsReview), claims.ApproveClaim(claimsService))
+	adminHandlersGroup.POST("/claims/:id/reject", middleware.RequirePermission(models.PermissionClaimsReview), claims.RejectClaim(claimsService))
 
 	// completed events
-	adminHandlersGroup.POST("/add_completed_event", events.AddCompletedEvent(completedEventService))
-	adminHandlersGroup.DELETE("/delete_completed_event/:user_id/:event_id", events.DeleteCompletedEvent(completedEventService))
-	adminHandlersGroup.GET("/completed_events", events.GetAllCompletedEvents(completedEventService))
+	adminHandlersGroup.POST("/add_completed_event", middleware.RequirePermission(models.PermissionCompletedEventsWrite), events.AddCompletedEvent(completedEventService))
+	adminHandlersGroup.DELETE("/delete_completed_event/:user_id/:event_id", middleware.RequirePermission(models.PermissionCompletedEventsWrite), events.DeleteCompletedEvent(completedEventService))
+	adminHandlersGroup.GET("/completed_events", middleware.RequirePermission(models.PermissionCompletedEventsRead), events.GetAllCompletedEvents(completedEventService))
+
+	// журнал баллов
+	adminHandlersGroup.GET("/users/:user_id/points", middleware.RequirePermission(models.PermissionCompletedEventsRead), users.GetUserPoints(pointsService))
+	adminHandlersGroup.POST("/users/:user_id/points", middleware.RequirePermission(models.PermissionCompletedEventsWrite), users.AdjustUserPoints(pointsService))
+	adminHandlersGroup.POST("/points/reconcile", middleware.RequirePermission(models.PermissionCompletedEventsWrite), users.ReconcilePoints(pointsService))
 }
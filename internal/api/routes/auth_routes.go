@@ -4,6 +4,7 @@ import (
 	"bobri/internal/api/controllers/auth"
 	"bobri/internal/api/repositories"
 	"bobri/internal/api/services"
+	"bobri/internal/middleware"
 	"bobri/internal/models"
 	"bobri/pkg/helpers"
 
@@ -13,7 +14,7 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
-func AuthRoutes(r *gin.Engine, db *pgxpool.Pool, accessJwtMaker *helpers.JWTMaker, emailAuth models.EmailAuth) {
+func AuthRoutes(r *gin.Engine, db *pgxpool.Pool, accessJwtMaker *helpers.JWTMaker, emailAuth models.EmailAuth, verificationPolicy services.EmailVerificationPolicy) {
 	// создаем UoW
 	uow := repositories.NewUoW(db)
 
@@ -21,27 +22,46 @@ func AuthRoutes(r *gin.Engine, db *pgxpool.Pool, accessJwtMaker *helpers.JWTMake
 	userRepo := repositories.NewUserRepository(db)
 	refreshTokensRepo := repositories.NewRefreshTokensRepository(db)
 	studentsRepo := repositories.NewStudentsRepository(db)
+	institutesRepo := repositories.NewInstitutesRepository(db)
 	resetPasswordRepo := repositories.NewResetPasswordRepository(db)
+	throttleRepo := repositories.NewAuthThrottleRepository(db)
+	mfaRepo := repositories.NewMFARepository(db)
+	emailVerificationRepo := repositories.NewEmailVerificationRepository(db)
+	permissionsRepo := repositories.NewPermissionsRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	impersonationRepo := repositories.NewImpersonationRepository(db)
 
 	// вспомогательные компоненты
-	tokenProvider := services.NewTokenProvider(accessJwtMaker, refreshTokensRepo)
+	tokenProvider := services.NewTokenProvider(accessJwtMaker, refreshTokensRepo, permissionsRepo)
 	emailProvider := services.NewEmailProvider(emailAuth)
+	auditService := services.NewAuditService(auditRepo)
+	throttleService := services.NewAuthThrottleService(throttleRepo, auditService, uow)
+	mfaService := services.NewMFAService(mfaRepo, userRepo, refreshTokensRepo, uow)
+	emailVerificationService := services.NewEmailVerificationService(emailVerificationRepo, userRepo, emailProvider, throttleService, verificationPolicy, uow)
 
 	// сервисы
-	authService := services.NewStudentsService(studentsRepo, uow)
-	registerService := services.NewRegisterService(userRepo, studentsRepo, tokenProvider, uow)
-	loginService := services.NewLoginService(userRepo, tokenProvider, uow)
+	authService := services.NewStudentsService(studentsRepo, userRepo, institutesRepo, throttleService, auditService, uow)
+	registerService := services.NewRegisterService(userRepo, studentsRepo, tokenProvider, emailVerificationService, uow)
+	loginService := services.NewLoginService(userRepo, tokenProvider, throttleService, mfaService, uow)
 	refreshService := services.NewRefreshTokensService(refreshTokensRepo, tokenProvider, uow)
-	resetPasswordService := services.NewResetPasswordService(resetPasswordRepo, userRepo, emailProvider, uow)
+	resetPasswordService := services.NewResetPasswordService(resetPasswordRepo, userRepo, emailProvider, throttleService, emailVerificationService, uow)
+	sessionsService := services.NewSessionsService(refreshTokensRepo, impersonationRepo)
 
 	authGroup := r.Group("/auth")
 
 	authGroup.POST("/check", auth.CheckStudent(authService))
 	authGroup.POST("/register", auth.RegisterByToken(registerService))
 	authGroup.POST("/login", auth.Login(loginService))
+	authGroup.POST("/login/mfa", auth.LoginMFA(loginService))
 	authGroup.POST("/reset_password", auth.ResetPassword(resetPasswordService))
 	authGroup.POST("/set_new_password", auth.SetNewPassword(resetPasswordService))
 	authGroup.POST("/refresh", auth.RefreshToken(refreshService))
+	authGroup.POST("/logout", middleware.AuthenticationMiddleware(accessJwtMaker, sessionsService), middleware.ForbidImpersonation(), auth.Logout(sessionsService))
+	authGroup.POST("/verify_email", auth.VerifyEmail(emailVerificationService))
+	authGroup.POST("/resend_verification", middleware.AuthenticationMiddleware(accessJwtMaker, sessionsService), auth.ResendVerification(emailVerificationService))
+
+	// Публичные ключи для проверки access токенов другими сервисами
+	r.GET("/.well-known/jwks.json", auth.JWKS(accessJwtMaker))
 
 	// Swagger
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
@@ -1,35 +1,125 @@
 package routes
 
 import (
+	"bobri/internal/api/controllers/claims"
+	"bobri/internal/api/controllers/events"
+	"bobri/internal/api/controllers/institutes"
+	"bobri/internal/api/controllers/uploads"
 	"bobri/internal/api/controllers/users"
 	"bobri/internal/api/repositories"
 	"bobri/internal/api/services"
 	"bobri/internal/middleware"
+	"bobri/internal/models"
 	"bobri/pkg/helpers"
+	"bobri/pkg/storage"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func UserRoutes(r *gin.Engine, db *pgxpool.Pool, accessJWTMaker *helpers.JWTMaker) {
+func UserRoutes(r *gin.Engine, db *pgxpool.Pool, accessJWTMaker *helpers.JWTMaker, emailAuth models.EmailAuth, verificationPolicy services.EmailVerificationPolicy, fileStorage storage.Storage) {
 	uow := repositories.NewUoW(db)
 
-	userHandlerGroup := r.Group("/me")
-	userHandlerGroup.Use(middleware.AuthenticationMiddleware(accessJWTMaker, 10))
-
 	// репозитории
 	userRepo := repositories.NewUserRepository(db)
 	completedEventRepo := repositories.NewCompletedEventsRepository(db)
+	refreshTokensRepo := repositories.NewRefreshTokensRepository(db)
+	mfaRepo := repositories.NewMFARepository(db)
+	emailVerificationRepo := repositories.NewEmailVerificationRepository(db)
+	throttleRepo := repositories.NewAuthThrottleRepository(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	impersonationRepo := repositories.NewImpersonationRepository(db)
+	institutesRepo := repositories.NewInstitutesRepository(db)
+	eventTypesRepo := repositories.NewEventTypesRepository(db)
+	claimsRepo := repositories.NewClaimsRepository(db)
+	eventRepo := repositories.NewEventRepository(db)
+	registrationsRepo := repositories.NewRegistrationsRepository(db)
+	checkinRepo := repositories.NewCheckinRepository(db)
+	pointsRepo := repositories.NewPointsRepository(db)
 
 	// сервисы
-	userService := services.NewUserService(userRepo)
-	completedEventService := services.NewCompletedEventsService(completedEventRepo, uow)
+	auditService := services.NewAuditService(auditRepo)
+	userService := services.NewUserService(userRepo, institutesRepo, auditService, uow)
+	completedEventService := services.NewCompletedEventsService(completedEventRepo, pointsRepo, auditService, uow)
+	sessionsService := services.NewSessionsService(refreshTokensRepo, impersonationRepo)
+	mfaService := services.NewMFAService(mfaRepo, userRepo, refreshTokensRepo, uow)
+	throttleService := services.NewAuthThrottleService(throttleRepo, auditService, uow)
+	emailVerificationService := services.NewEmailVerificationService(emailVerificationRepo, userRepo, services.NewEmailProvider(emailAuth), throttleService, verificationPolicy, uow)
+	institutesService := services.NewInstitutesService(institutesRepo, auditService, uow)
+	eventTypesService := services.NewEventTypesService(eventTypesRepo, auditService, uow)
+	claimsService := services.NewClaimsService(claimsRepo, completedEventService, auditService, uow)
+	uploadService := services.NewUploadService(fileStorage, userRepo)
+	registrationsService := services.NewRegistrationsService(registrationsRepo, eventRepo, completedEventRepo, completedEventService, auditService, uow)
+	eventService := services.NewEventService(eventRepo, registrationsRepo, pointsRepo, auditService, uow)
+	checkinService := services.NewCheckinService(checkinRepo, eventRepo, registrationsRepo, completedEventRepo, completedEventService, auditService, uow)
+	pointsService := services.NewPointsService(pointsRepo, userRepo, auditService, uow)
+	accountService := services.NewAccountService(userRepo, refreshTokensRepo, completedEventRepo, pointsRepo, emailVerificationService, uow)
+
+	userHandlerGroup := r.Group("/me")
+	userHandlerGroup.Use(
+		middleware.AuthenticationMiddleware(accessJWTMaker, sessionsService),
+		middleware.RequirePermission(models.PermissionProfileRead),
+		middleware.EmailVerificationMiddleware(emailVerificationService, services.VerificationAreaUser),
+	)
 
 	// маршруты /me
 	userHandlerGroup.GET("/profile", users.GetProfile(userService))
 	userHandlerGroup.GET("/completed_events", users.GetCompletedEvents(completedEventService))
+	userHandlerGroup.GET("/points", users.GetMyPoints(pointsService))
+
+	// запись на события
+	userHandlerGroup.GET("/events/:id/register", events.GetEventRegistration(registrationsService))
+	userHandlerGroup.POST("/events/:id/register", events.RegisterForEvent(registrationsService))
+	userHandlerGroup.POST("/events/:id/unregister", events.UnregisterFromEvent(registrationsService))
+
+	// отметка на событии по QR коду
+	userHandlerGroup.POST("/checkin", middleware.ForbidImpersonation(), events.CheckIn(checkinService))
+
+	// заявки на баллы
+	userHandlerGroup.GET("/claims", claims.GetMyClaims(claimsService))
+	userHandlerGroup.POST("/claims", claims.CreateClaim(claimsService))
+	userHandlerGroup.DELETE("/claims/:id", claims.WithdrawClaim(claimsService))
+
+	// загрузка файлов
+	userHandlerGroup.POST("/avatar", uploads.UploadAvatar(uploadService))
+	userHandlerGroup.POST("/uploads", uploads.UploadEvidence(uploadService))
+
+	// управление аккаунтом
+	userHandlerGroup.PATCH("/profile", users.UpdateProfile(accountService))
+	userHandlerGroup.POST("/password", middleware.ForbidImpersonation(), users.ChangePassword(accountService))
+	userHandlerGroup.POST("/email", middleware.ForbidImpersonation(), users.ChangeEmail(accountService))
+
+	// персональные данные и удаление аккаунта
+	userHandlerGroup.GET("/export", users.ExportAccount(accountService))
+	userHandlerGroup.POST("/delete", middleware.ForbidImpersonation(), users.DeleteAccount(accountService))
+	userHandlerGroup.DELETE("/delete", middleware.ForbidImpersonation(), users.CancelAccountDeletion(accountService))
+
+	// сессии
+	userHandlerGroup.GET("/sessions", users.GetSessions(sessionsService))
+	userHandlerGroup.DELETE("/sessions", middleware.ForbidImpersonation(), users.RevokeAllSessions(sessionsService))
+	userHandlerGroup.DELETE("/sessions/:id", middleware.ForbidImpersonation(), users.RevokeSession(sessionsService))
+
+	// двухфакторная аутентификация
+	userHandlerGroup.GET("/2fa", users.GetTwoFactorStatus(mfaService))
+	userHandlerGroup.POST("/2fa/setup", middleware.ForbidImpersonation(), users.SetupTwoFactor(mfaService))
+	userHandlerGroup.POST("/2fa/enable", middleware.ForbidImpersonation(), users.EnableTwoFactor(mfaService))
+	userHandlerGroup.POST("/2fa/disable", middleware.ForbidImpersonation(), users.DisableTwoFactor(mfaService))
+	userHandlerGroup.POST("/2fa/recovery_codes", middleware.ForbidImpersonation(), users.RegenerateRecoveryCodes(mfaService))
+
+	// каталог и поиск событий для авторизованных пользователей (админка и мобильное приложение)
+	eventsHandlerGroup := r.Group("/events")
+	eventsHandlerGroup.Use(
+		middleware.AuthenticationMiddleware(accessJWTMaker, sessionsService),
+		middleware.RequirePermission(models.PermissionProfileRead),
+		middleware.EmailVerificationMiddleware(emailVerificationService, services.VerificationAreaUser),
+	)
+	eventsHandlerGroup.GET("", events.GetCatalog(eventService))
+	eventsHandlerGroup.GET("/search", events.SearchCatalog(eventService))
+	eventsHandlerGroup.GET("/:id", events.GetCatalogEvent(eventService))
 
 	// паблик маршрут
 	r.GET("/leaderboard", users.GetLeaderboard(userService))
 	r.GET("/get_suggests", users.GetSuggests(userService))
+	r.GET("/institutes", institutes.GetInstitutes(institutesService))
+	r.GET("/event_types", events.GetEventTypes(eventTypesService))
 }
@@ -0,0 +1,297 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrSamePassword             = errors.New("новый пароль совпадает с текущим")
+	ErrSameEmail                = errors.New("новая почта совпадает с текущей")
+	ErrDeletionAlreadyScheduled = errors.New("удаление аккаунта уже запланировано")
+	ErrDeletionNotScheduled     = errors.New("удаление аккаунта не запланировано")
+)
+
+const (
+	// accountDeletionGracePeriod - сколько времени у пользователя есть на отмену удаления аккаунта
+	accountDeletionGracePeriod = 14 * 24 * time.Hour
+	// accountDeletionBatch - сколько аккаунтов анонимизируется за один проход
+	accountDeletionBatch = 100
+)
+
+// AccountService - самостоятельное управление аккаунтом пользователя (/me).
+type AccountService struct {
+	userRepo      *repositories.UserRepository
+	refreshRepo   *repositories.RefreshTokensRepository
+	completedRepo *repositories.CompletedEventsRepository
+	pointsRepo    *repositories.PointsRepository
+	verification  *EmailVerificationService
+	uow           *repositories.UoW
+}
+
+func NewAccountService(
+	userRepo *repositories.UserRepository,
+	refreshRepo *repositories.RefreshTokensRepository,
+	completedRepo *repositories.CompletedEventsRepository,
+	pointsRepo *repositories.PointsRepository,
+	verification *EmailVerificationService,
+	uow *repositories.UoW,
+) *AccountService {
+	return &AccountService{
+		userRepo:      userRepo,
+		refreshRepo:   refreshRepo,
+		completedRepo: completedRepo,
+		pointsRepo:    pointsRepo,
+		verification:  verification,
+		uow:           uow,
+	}
+}
+
+// ChangePassword меняет пароль после проверки текущего. Все сессии, кроме текущей, завершаются,
+// выданные access токены отзываются через token_version: текущая сессия получает новый
+// access token обменом своего refresh токена.
+func (s *AccountService) ChangePassword(ctx context.Context, userId, currentSessionId int64, currentPassword, newPassword string) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(currentPassword)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	if len(newPassword) < 8 {
+		return ErrWeakPassword
+	}
+	if newPassword == currentPassword {
+		return ErrSamePassword
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		if err := s.userRepo.WithDB(tx).UpdatePassword(ctx, userId, hashed); err != nil {
+			return err
+		}
+		if err := s.userRepo.WithDB(tx).IncrementTokenVersion(ctx, userId); err != nil {
+			return err
+		}
+
+		return s.refreshRepo.WithDB(tx).DeleteOtherSessions(ctx, userId, currentSessionId)
+	})
+}
+
+// ChangeEmail меняет почту после проверки пароля. Новая почта считается неподтвержденной,
+// на нее отправляется письмо с токеном подтверждения.
+func (s *AccountService) ChangeEmail(ctx context.Context, userId int64, password, newEmail string) error {
+	newEmail = strings.TrimSpace(newEmail)
+
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(password)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	if strings.EqualFold(user.Email, newEmail) {
+		return ErrSameEmail
+	}
+
+	var verificationToken string
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		rows, err := s.userRepo.WithDB(tx).UpdateUser(ctx, models.UpdateUserRequest{
+			UserId:  userId,
+			NewData: models.UserUpdateData{Email: newEmail},
+		})
+		if err != nil {
+			// почта занята другим пользователем (users_email_uq)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return ErrUserWithEmailAlreadyExists
+			}
+			return err
+		}
+		if rows != 1 {
+			return ErrRowsAffected
+		}
+
+		verificationToken, err = s.verification.IssueToken(ctx, tx, userId, newEmail)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// почта уже изменена: если письмо не ушло, пользователь может запросить его повторно
+	if err = s.verification.SendToken(newEmail, verificationToken); err != nil {
+		log.Printf("could not send verification email to user %d: %v", userId, err)
+	}
+
+	return nil
+}
+
+// UpdateProfile меняет поля профиля, доступные самому пользователю, и возвращает обновленный профиль.
+func (s *AccountService) UpdateProfile(ctx context.Context, userId int64, req models.UpdateProfileRequest) (models.ProfileResponse, error) {
+	rows, err := s.userRepo.UpdateUser(ctx, models.UpdateUserRequest{
+		UserId:  userId,
+		NewData: models.UserUpdateData{Avatar: strings.TrimSpace(req.Avatar)},
+	})
+	if err != nil {
+		return models.ProfileResponse{}, err
+	}
+	if rows != 1 {
+		return models.ProfileResponse{}, ErrUserNotFound
+	}
+
+	return s.userRepo.GetProfileByUserID(ctx, userId)
+}
+
+// Export собирает все данные, которые сервис хранит о пользователе.
+func (s *AccountService) Export(ctx context.Context, userId int64) (models.AccountExport, error) {
+	profile, err := s.userRepo.GetProfileByUserID(ctx, userId)
+	if err != nil {
+		return models.AccountExport{}, ErrUserNotFound
+	}
+
+	completed, err := s.completedRepo.GetCompletedEventsWithStats(ctx, userId)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+
+	transactions, err := s.pointsRepo.GetAllTransactions(ctx, userId)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+
+	sessions, err := s.refreshRepo.GetSessions(ctx, userId)
+	if err != nil {
+		return models.AccountExport{}, err
+	}
+
+	return models.AccountExport{
+		ExportedAt:      time.Now().UTC(),
+		Profile:         profile,
+		CompletedEvents: completed,
+		PointsHistory:   pointsHistory(transactions),
+		Sessions:        sessions,
+	}, nil
+}
+
+// pointsHistory дополняет записи журнала баллов суммой после каждой из них.
+func pointsHistory(transactions []models.PointsTransaction) []models.PointsHistoryEntry {
+	history := make([]models.PointsHistoryEntry, 0, len(transactions))
+	var total int64
+	for _, t := range transactions {
+		total += int64(t.Delta)
+		history = append(history, models.PointsHistoryEntry{
+			PointsTransaction: t,
+			TotalPoints:       total,
+		})
+	}
+
+	return history
+}
+
+// RequestDeletion планирует удаление аккаунта после проверки пароля.
+// До истечения срока пользователь может отменить удаление через CancelDeletion.
+func (s *AccountService) RequestDeletion(ctx context.Context, userId int64, password string) (time.Time, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return time.Time{}, ErrUserNotFound
+	}
+
+	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(password)); err != nil {
+		return time.Time{}, ErrInvalidPassword
+	}
+
+	profile, err := s.userRepo.GetProfileByUserID(ctx, userId)
+	if err != nil {
+		return time.Time{}, ErrUserNotFound
+	}
+	if profile.DeletionScheduledAt != nil {
+		return *profile.DeletionScheduledAt, ErrDeletionAlreadyScheduled
+	}
+
+	at := time.Now().Add(accountDeletionGracePeriod).UTC()
+	if err = s.userRepo.ScheduleDeletion(ctx, userId, at); err != nil {
+		return time.Time{}, err
+	}
+
+	return at, nil
+}
+
+// CancelDeletion отменяет запланированное удаление аккаунта.
+func (s *AccountService) CancelDeletion(ctx context.Context, userId int64) error {
+	cancelled, err := s.userRepo.CancelDeletion(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !cancelled {
+		return ErrDeletionNotScheduled
+	}
+
+	return nil
+}
+
+// PurgeScheduledDeletions анонимизирует аккаунты, у которых истек срок на отмену удаления.
+// Выполненные события и баллы остаются, чтобы не искажать общую статистику.
+func (s *AccountService) PurgeScheduledDeletions(ctx context.Context) (int, error) {
+	ids, err := s.userRepo.GetUsersDueForDeletion(ctx, accountDeletionBatch)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, id := range ids {
+		err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+			userRepo := s.userRepo.WithDB(tx)
+
+			if err := userRepo.DeleteUserAuthData(ctx, id); err != nil {
+				return err
+			}
+
+			return userRepo.AnonymizeUser(ctx, id)
+		})
+		if err != nil {
+			return purged, fmt.Errorf("could not purge user %d: %w", id, err)
+		}
+		purged++
+	}
+
+	return purged, nil
+}
+
+// RunDeletionWorker периодически анонимизирует аккаунты, запланированные к удалению, пока не отменен ctx.
+func (s *AccountService) RunDeletionWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeScheduledDeletions(ctx)
+		if err != nil {
+			log.Printf("could not purge scheduled account deletions: %v", err)
+		} else if purged > 0 {
+			log.Printf("anonymized %d accounts scheduled for deletion", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChangePasswordWrongCurrentPassword(t *testing.T) {
+	f := newAuthFixture(t)
+
+	userId := f.createUser(t, 2001, "wrong@example.com", "password-1")
+
+	err := f.account.ChangePassword(context.Background(), userId, 0, "not-my-password", "password-2")
+	if !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidPassword)
+	}
+}
+
+func TestChangePasswordSamePassword(t *testing.T) {
+	f := newAuthFixture(t)
+
+	userId := f.createUser(t, 2002, "same@example.com", "password-1")
+
+	err := f.account.ChangePassword(context.Background(), userId, 0, "password-1", "password-1")
+	if !errors.Is(err, ErrSamePassword) {
+		t.Fatalf("got %v, want %v", err, ErrSamePassword)
+	}
+}
+
+func TestChangePasswordRevokesOtherSessions(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	meta := models.SessionMeta{IP: "127.0.0.1"}
+
+	userId := f.createUser(t, 2003, "sessions@example.com", "password-1")
+	current := f.loginUser(t, "sessions@example.com", "password-1", "laptop")
+	other := f.loginUser(t, "sessions@example.com", "password-1", "phone")
+
+	var sessionId int64
+	err := f.pool.QueryRow(ctx,
+		`SELECT family_id FROM refresh_tokens WHERE token_hash = $1`,
+		helpers.HashToken(current),
+	).Scan(&sessionId)
+	if err != nil {
+		t.Fatalf("could not get current session: %v", err)
+	}
+
+	versionBefore, err := f.refreshRepo.GetTokenVersion(ctx, userId)
+	if err != nil {
+		t.Fatalf("could not get token version: %v", err)
+	}
+
+	if err = f.account.ChangePassword(ctx, userId, sessionId, "password-1", "password-2"); err != nil {
+		t.Fatalf("change password failed: %v", err)
+	}
+
+	versionAfter, err := f.refreshRepo.GetTokenVersion(ctx, userId)
+	if err != nil {
+		t.Fatalf("could not get token version: %v", err)
+	}
+	if versionAfter != versionBefore+1 {
+		t.Fatalf("token_version: got %d, want %d", versionAfter, versionBefore+1)
+	}
+
+	if _, err = f.refresh.RefreshToken(ctx, other, meta); !errors.Is(err, ErrNoTokensFound) {
+		t.Fatalf("other session: got %v, want %v", err, ErrNoTokensFound)
+	}
+
+	// текущая сессия остается и получает access token с новой версией
+	if _, err = f.refresh.RefreshToken(ctx, current, meta); err != nil {
+		t.Fatalf("current session refresh failed: %v", err)
+	}
+
+	if _, err = f.login.Login(ctx, "sessions@example.com", "password-2", meta); err != nil {
+		t.Fatalf("login with new password failed: %v", err)
+	}
+}
+
+func TestChangeEmailTaken(t *testing.T) {
+	f := newAuthFixture(t)
+
+	f.createUser(t, 2004, "taken@example.com", "password-1")
+	userId := f.createUser(t, 2005, "mine@example.com", "password-1")
+
+	err := f.account.ChangeEmail(context.Background(), userId, "password-1", "taken@example.com")
+	if !errors.Is(err, ErrUserWithEmailAlreadyExists) {
+		t.Fatalf("got %v, want %v", err, ErrUserWithEmailAlreadyExists)
+	}
+
+	user, err := f.userRepo.GetUserById(context.Background(), userId)
+	if err != nil {
+		t.Fatalf("could not get user: %v", err)
+	}
+	if user.Email != "mine@example.com" {
+		t.Fatalf("email changed to %q", user.Email)
+	}
+}
+
+func TestChangeEmailWrongPassword(t *testing.T) {
+	f := newAuthFixture(t)
+
+	userId := f.createUser(t, 2006, "owner@example.com", "password-1")
+
+	err := f.account.ChangeEmail(context.Background(), userId, "not-my-password", "new@example.com")
+	if !errors.Is(err, ErrInvalidPassword) {
+		t.Fatalf("got %v, want %v", err, ErrInvalidPassword)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	userId := f.createUser(t, 2007, "profile@example.com", "password-1")
+
+	profile, err := f.account.UpdateProfile(ctx, userId, models.UpdateProfileRequest{Avatar: " avatars/2007.png "})
+	if err != nil {
+		t.Fatalf("update profile failed: %v", err)
+	}
+	if profile.Avatar != "avatars/2007.png" {
+		t.Fatalf("avatar: got %q, want %q", profile.Avatar, "avatars/2007.png")
+	}
+
+	if _, err = f.account.UpdateProfile(ctx, userId+1000, models.UpdateProfileRequest{Avatar: "a.png"}); !errors.Is(err, ErrUserNotFound) {
+		t.Fatalf("unknown user: got %v, want %v", err, ErrUserNotFound)
+	}
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Действия, которые записываются в журнал.
+const (
+	AuditEventCreate          = "event.create"
+	AuditEventUpdate          = "event.update"
+	AuditEventDelete          = "event.delete"
+	AuditEventRestore         = "event.restore"
+	AuditEventPublish         = "event.publish"
+	AuditEventArchive         = "event.archive"
+	AuditEventAttendance      = "event.attendance"
+	AuditEventCheckinSettings = "event.checkin_settings"
+	AuditEventCheckinRotate   = "event.checkin_rotate"
+	AuditEventTypeCreate      = "event_type.create"
+	AuditEventTypeUpdate      = "event_type.update"
+	AuditEventTypeDelete      = "event_type.delete"
+	AuditSuggestCreate        = "suggest.create"
+	AuditSuggestDelete        = "suggest.delete"
+	AuditCompletedEventAdd    = "completed_event.add"
+	AuditCompletedEventDelete = "completed_event.delete"
+	AuditPointsAdjust         = "points.adjust"
+	AuditPointsReconcile      = "points.reconcile"
+	AuditUserUpdate           = "user.update"
+	AuditUserDelete           = "user.delete"
+	AuditRoleCreate           = "role.create"
+	AuditRolePermissionsSet   = "role.set_permissions"
+	AuditAuthUnlock           = "auth_lock.unlock"
+	AuditImpersonationStart   = "impersonation.start"
+	AuditImpersonationEnd     = "impersonation.end"
+	AuditStudentsImport       = "student.import"
+	AuditStudentCreate        = "student.create"
+	AuditStudentUpdate        = "student.update"
+	AuditStudentDelete        = "student.delete"
+	AuditStudentsRollover     = "student.rollover"
+	AuditInstituteCreate      = "institute.create"
+	AuditInstituteUpdate      = "institute.update"
+	AuditInstituteDelete      = "institute.delete"
+	AuditStudyCreate          = "study.create"
+	AuditStudyUpdate          = "study.update"
+	AuditStudyDelete          = "study.delete"
+	AuditGroupCreate          = "group.create"
+	AuditGroupUpdate          = "group.update"
+	AuditGroupDelete          = "group.delete"
+	AuditClaimApprove         = "claim.approve"
+	AuditClaimReject          = "claim.reject"
+)
+
+// Типы объектов, над которыми выполняются действия.
+const (
+	AuditTargetEvent     = "event"
+	AuditTargetEventType = "event_type"
+	AuditTargetUser      = "user"
+	AuditTargetRole      = "role"
+	AuditTargetAuthLock  = "auth_lock"
+	AuditTargetStudent   = "student"
+	AuditTargetInstitute = "institute"
+	AuditTargetStudy     = "study"
+	AuditTargetGroup     = "group"
+	AuditTargetClaim     = "claim"
+	AuditTargetPoints    = "points" // журнал баллов целиком (сверка), target_id пустой
+)
+
+type AuditService struct {
+	repo *repositories.AuditRepository
+}
+
+func NewAuditService(repo *repositories.AuditRepository) *AuditService {
+	return &AuditService{
+		repo: repo,
+	}
+}
+
+// Record записывает действие actorId в журнал. Вызывается внутри транзакции самого действия,
+// чтобы запись появлялась только вместе с изменением. before и after сериализуются в JSON, nil - NULL.
+func (s *AuditService) Record(ctx context.Context, db repositories.DBTX, actorId int64, action, targetType string, targetId any, before, after any) error {
+	beforeJSON, err := marshalAudit(before)
+	if err != nil {
+		return err
+	}
+	afterJSON, err := marshalAudit(after)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.WithDB(db).CreateEntry(ctx, actorId, action, targetType, fmt.Sprint(targetId), beforeJSON, afterJSON)
+}
+
+// GetEntries возвращает записи журнала по фильтру.
+func (s *AuditService) GetEntries(ctx context.Context, filter models.AuditFilter) ([]models.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.repo.GetEntries(ctx, filter)
+}
+
+func marshalAudit(v any) ([]byte, error) {
+	if v == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("could not marshal audit data: %w", err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// Сценарии, для которых ограничивается число попыток.
+const (
+	ThrottleScopeLogin         = "login"
+	ThrottleScopeCheckStudent  = "check"
+	ThrottleScopeResetPassword = "reset_password"
+	ThrottleScopeVerifyEmail   = "verify_email"
+	ThrottleScopeMFA           = "mfa"
+)
+
+const (
+	throttleKeyIP      = "ip"
+	throttleKeyAccount = "account"
+)
+
+var (
+	ErrTooManyAttempts = errors.New("слишком много попыток, попробуйте позже")
+	ErrAccountLocked   = errors.New("аккаунт временно заблокирован из-за большого числа попыток")
+	ErrLockNotFound    = errors.New("блокировка не найдена")
+)
+
+// ThrottleError - ошибка превышения числа попыток. Оборачивает ErrTooManyAttempts
+// (блокировка по IP) или ErrAccountLocked (блокировка аккаунта) и хранит время окончания блокировки.
+type ThrottleError struct {
+	Err         error
+	LockedUntil time.Time
+}
+
+func (e *ThrottleError) Error() string { return e.Err.Error() }
+
+func (e *ThrottleError) Unwrap() error { return e.Err }
+
+// throttleRule - не больше limit попыток за window, после чего ключ блокируется
+// на baseLock, а каждая следующая блокировка вдвое дольше предыдущей (но не дольше maxLock).
+type throttleRule struct {
+	limit    int
+	window   time.Duration
+	baseLock time.Duration
+	maxLock  time.Duration
+}
+
+type throttlePolicy struct {
+	ip      throttleRule
+	account throttleRule
+}
+
+var throttlePolicies = map[string]throttlePolicy{
+	// считаются только неудачные попытки входа
+	ThrottleScopeLogin: {
+		ip:      throttleRule{limit: 20, window: 15 * time.Minute, baseLock: 15 * time.Minute, maxLock: 24 * time.Hour},
+		account: throttleRule{limit: 5, window: 15 * time.Minute, baseLock: 5 * time.Minute, maxLock: 24 * time.Hour},
+	},
+	// считается каждый запрос, чтобы нельзя было перебирать номера студенческих
+	ThrottleScopeCheckStudent: {
+		ip:      throttleRule{limit: 10, window: 10 * time.Minute, baseLock: 10 * time.Minute, maxLock: 24 * time.Hour},
+		account: throttleRule{limit: 5, window: 10 * time.Minute, baseLock: 10 * time.Minute, maxLock: 24 * time.Hour},
+	},
+	// считается каждый запрос, чтобы нельзя было заваливать почту письмами
+	ThrottleScopeResetPassword: {
+		ip:      throttleRule{limit: 5, window: 15 * time.Minute, baseLock: 15 * time.Minute, maxLock: 24 * time.Hour},
+		account: throttleRule{limit: 3, window: time.Hour, baseLock: time.Hour, maxLock: 24 * time.Hour},
+	},
+	// считаются только неверные коды второго фактора: лимит общий для всех челленджей аккаунта,
+	// иначе перебор TOTP продолжался бы с новыми челленджами
+	ThrottleScopeMFA: {
+		ip:      throttleRule{limit: 20, window: 15 * time.Minute, baseLock: 15 * time.Minute, maxLock: 24 * time.Hour},
+		account: throttleRule{limit: 5, window: 15 * time.Minute, baseLock: 15 * time.Minute, maxLock: 24 * time.Hour},
+	},
+	// повторная отправка письма подтверждения почты
+	ThrottleScopeVerifyEmail: {
+		ip:      throttleRule{limit: 10, window: time.Hour, baseLock: time.Hour, maxLock: 24 * time.Hour},
+		account: throttleRule{limit: 3, window: time.Hour, baseLock: time.Hour, maxLock: 24 * time.Hour},
+	},
+}
+
+type AuthThrottleService struct {
+	repo  *repositories.AuthThrottleRepository
+	audit *AuditService
+	uow   *repositories.UoW
+}
+
+func NewAuthThrottleService(repo *repositories.AuthThrottleRepository, audit *AuditService, uow *repositories.UoW) *AuthThrottleService {
+	return &AuthThrottleService{
+		repo:  repo,
+		audit: audit,
+		uow:   uow,
+	}
+}
+
+// NormalizeAccount приводит идентификатор аккаунта (почту, номер студенческого) к единому виду.
+func NormalizeAccount(account string) string {
+	return strings.ToLower(strings.TrimSpace(account))
+}
+
+// Check возвращает ThrottleError, если IP или аккаунт сейчас заблокированы в сценарии scope.
+func (s *AuthThrottleService) Check(ctx context.Context, scope, ip, account string) error {
+	until, err := s.repo.GetLockedUntil(ctx, scope, throttleKeyAccount, NormalizeAccount(account))
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		return &ThrottleError{Err: ErrAccountLocked, LockedUntil: until}
+	}
+
+	until, err = s.repo.GetLockedUntil(ctx, scope, throttleKeyIP, ip)
+	if err != nil {
+		return err
+	}
+	if !until.IsZero() {
+		return &ThrottleError{Err: ErrTooManyAttempts, LockedUntil: until}
+	}
+
+	return nil
+}
+
+// RegisterAttempt учитывает попытку для IP и аккаунта и блокирует тех, кто превысил лимит.
+func (s *AuthThrottleService) RegisterAttempt(ctx context.Context, scope, ip, account string) error {
+	policy, ok := throttlePolicies[scope]
+	if !ok {
+		return errors.New("unknown throttle scope: " + scope)
+	}
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		if err := s.registerKey(ctx, tx, scope, throttleKeyIP, ip, policy.ip); err != nil {
+			return err
+		}
+		if account == "" {
+			return nil
+		}
+		return s.registerKey(ctx, tx, scope, throttleKeyAccount, NormalizeAccount(account), policy.account)
+	})
+}
+
+func (s *AuthThrottleService) registerKey(ctx context.Context, tx repositories.DBTX, scope, keyType, key string, rule throttleRule) error {
+	state, err := s.repo.WithDB(tx).RegisterAttempt(ctx, scope, keyType, key, rule.window)
+	if err != nil {
+		return err
+	}
+	if state.Failures < rule.limit {
+		return nil
+	}
+
+	// прогрессивная блокировка: baseLock, 2*baseLock, 4*baseLock ... не больше maxLock
+	lock := rule.baseLock
+	for i := 0; i < state.Lockouts && lock < rule.maxLock; i++ {
+		lock *= 2
+	}
+	if lock > rule.maxLock {
+		lock = rule.maxLock
+	}
+
+	return s.repo.WithDB(tx).Lock(ctx, scope, keyType, key, time.Now().Add(lock))
+}
+
+// Reset сбрасывает счетчик аккаунта после успешной попытки.
+func (s *AuthThrottleService) Reset(ctx context.Context, scope, account string) error {
+	return s.repo.Reset(ctx, scope, throttleKeyAccount, NormalizeAccount(account))
+}
+
+// Unlock снимает блокировки с аккаунта или IP во всех сценариях.
+func (s *AuthThrottleService) Unlock(ctx context.Context, actorId int64, key string) error {
+	key = NormalizeAccount(key)
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		tag, err := s.repo.WithDB(tx).DeleteByKey(ctx, key)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrLockNotFound
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditAuthUnlock, AuditTargetAuthLock, key, nil, nil)
+	})
+}
+
+// GetActiveLocks возвращает действующие блокировки.
+func (s *AuthThrottleService) GetActiveLocks(ctx context.Context, limit int) ([]models.AuthLock, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return s.repo.GetActiveLocks(ctx, limit)
+}
@@ -0,0 +1,328 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Время жизни токена отметки: QR код на экране обновляется чаще, чем его успевают переслать.
+const (
+	DefaultCheckinTokenTTL = time.Minute
+	MinCheckinTokenTTL     = 10 * time.Second
+	MaxCheckinTokenTTL     = 10 * time.Minute
+)
+
+var (
+	ErrCheckinNotConfigured = errors.New("окно отметки для события не задано")
+	ErrCheckinNotOpen       = errors.New("отметка на событии еще не началась")
+	ErrCheckinClosed        = errors.New("отметка на событии закончилась")
+	ErrInvalidCheckinWindow = errors.New("некорректное окно отметки: конец должен быть позже начала")
+	ErrInvalidCheckinToken  = errors.New("недействительный токен отметки")
+	ErrCheckinTokenExpired  = errors.New("срок действия токена отметки истек")
+	ErrAlreadyCheckedIn     = errors.New("пользователь уже отметился на событии")
+)
+
+// CheckinService - самостоятельная отметка студентов на событии по QR коду.
+// Администратор показывает QR код с короткоживущим токеном, подписанным ключом события;
+// студент отправляет отсканированный токен и получает событие засчитанным один раз.
+// Смена ключа отзывает все выданные токены.
+type CheckinService struct {
+	repo          *repositories.CheckinRepository
+	events        *repositories.EventRepository
+	registrations *repositories.RegistrationsRepository
+	completedRepo *repositories.CompletedEventsRepository
+	completed     *CompletedEventsService
+	audit         *AuditService
+	uow           *repositories.UoW
+}
+
+func NewCheckinService(
+	repo *repositories.CheckinRepository,
+	events *repositories.EventRepository,
+	registrations *repositories.RegistrationsRepository,
+	completedRepo *repositories.CompletedEventsRepository,
+	completed *CompletedEventsService,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *CheckinService {
+	return &CheckinService{
+		repo:          repo,
+		events:        events,
+		registrations: registrations,
+		completedRepo: completedRepo,
+		completed:     completed,
+		audit:         audit,
+		uow:           uow,
+	}
+}
+
+// GetSettings возвращает окно отметки события.
+func (s *CheckinService) GetSettings(ctx context.Context, eventId int64) (models.CheckinSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, eventId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return settings, s.notConfigured(ctx, eventId)
+		}
+		return settings, err
+	}
+	return settings, nil
+}
+
+// SetSettings задает окно отметки. Ключ подписи создается при первом задании окна.
+func (s *CheckinService) SetSettings(ctx context.Context, actorId, eventId int64, req models.SetCheckinSettingsRequest) (models.CheckinSettings, error) {
+	if req.OpensAt == nil || req.ClosesAt == nil || !req.ClosesAt.After(*req.OpensAt) {
+		return models.CheckinSettings{}, ErrInvalidCheckinWindow
+	}
+
+	secret, err := helpers.NewCheckinSecret()
+	if err != nil {
+		return models.CheckinSettings{}, err
+	}
+
+	var result models.CheckinSettings
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		if _, err := s.registrations.WithDB(tx).LockEvent(ctx, eventId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		before, err := repo.GetSettings(ctx, eventId)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+
+		if err = repo.UpsertSettings(ctx, eventId, *req.OpensAt, *req.ClosesAt, secret); err != nil {
+			return err
+		}
+
+		if result, err = repo.GetSettings(ctx, eventId); err != nil {
+			return err
+		}
+
+		var beforeValue any
+		if before.EventId != 0 {
+			beforeValue = before
+		}
+		return s.audit.Record(ctx, tx, actorId, AuditEventCheckinSettings, AuditTargetEvent, eventId, beforeValue, result)
+	})
+
+	return result, err
+}
+
+// RotateSecret меняет ключ подписи события: все ранее выданные токены перестают действовать.
+func (s *CheckinService) RotateSecret(ctx context.Context, actorId, eventId int64) error {
+	secret, err := helpers.NewCheckinSecret()
+	if err != nil {
+		return err
+	}
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		ok, err := s.repo.WithDB(tx).RotateSecret(ctx, eventId, secret)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return s.notConfigured(ctx, eventId)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventCheckinRotate, AuditTargetEvent, eventId, nil, nil)
+	})
+}
+
+// IssueToken выдает новый токен для QR кода. Токен живет ttl (в пределах MinCheckinTokenTTL..MaxCheckinTokenTTL),
+// но не дольше окна отметки. Вне окна и для неопубликованных событий токен не выдается.
+func (s *CheckinService) IssueToken(ctx context.Context, eventId int64, ttl time.Duration) (models.CheckinTokenResponse, error) {
+	ttl = min(max(ttl, MinCheckinTokenTTL), MaxCheckinTokenTTL)
+
+	settings, err := s.repo.GetSecret(ctx, eventId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.CheckinTokenResponse{}, s.notConfigured(ctx, eventId)
+		}
+		return models.CheckinTokenResponse{}, err
+	}
+
+	event, err := s.events.GetEventById(ctx, eventId)
+	if err != nil {
+		return models.CheckinTokenResponse{}, err
+	}
+	if event.DeletedAt != nil {
+		return models.CheckinTokenResponse{}, ErrEventNotFound
+	}
+	if event.Status != models.EventStatusPublished {
+		return models.CheckinTokenResponse{}, ErrCheckinClosed
+	}
+
+	now := time.Now()
+	if err = checkinWindowError(settings, now); err != nil {
+		return models.CheckinTokenResponse{}, err
+	}
+
+	// токен хранит время с точностью до секунды
+	expiresAt := now.Add(ttl)
+	if expiresAt.After(settings.ClosesAt) {
+		expiresAt = settings.ClosesAt
+	}
+	expiresAt = expiresAt.Truncate(time.Second)
+
+	token, err := helpers.IssueCheckinToken(settings.Secret, eventId, expiresAt)
+	if err != nil {
+		return models.CheckinTokenResponse{}, err
+	}
+
+	return models.CheckinTokenResponse{
+		EventId:   eventId,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// CheckIn отмечает пользователя по отсканированному токену и засчитывает событие с начислением баллов.
+// Просроченные токены и токены, подписанные старым ключом, не принимаются, повторная отметка невозможна.
+// На события с ограничением мест отметиться могут только получившие место.
+func (s *CheckinService) CheckIn(ctx context.Context, userId int64, rawToken string) (models.CheckinResponse, error) {
+	token, err := helpers.ParseCheckinToken(rawToken)
+	if err != nil {
+		return models.CheckinResponse{}, ErrInvalidCheckinToken
+	}
+
+	var result models.CheckinResponse
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+		registrations := s.registrations.WithDB(tx)
+
+		// блокировка события упорядочивает отметку с ручной отметкой посещения
+		event, err := registrations.LockEvent(ctx, token.EventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidCheckinToken
+			}
+			return err
+		}
+
+		settings, err := repo.GetSecret(ctx, token.EventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidCheckinToken
+			}
+			return err
+		}
+		if !token.Verify(settings.Secret) {
+			return ErrInvalidCheckinToken
+		}
+
+		now := time.Now()
+		if !now.Before(token.ExpiresAt) {
+			return ErrCheckinTokenExpired
+		}
+		if err = checkinWindowError(settings, now); err != nil {
+			return err
+		}
+		if event.Status != models.EventStatusPublished {
+			return ErrCheckinClosed
+		}
+
+		checkedIn, err := repo.IsCheckedIn(ctx, token.EventId, userId)
+		if err != nil {
+			return err
+		}
+		if checkedIn {
+			return ErrAlreadyCheckedIn
+		}
+
+		// проверки заранее: нарушение уникальности прервало бы всю транзакцию
+		done, err := s.completedRepo.WithDB(tx).IsCompleted(ctx, userId, token.EventId)
+		if err != nil {
+			return err
+		}
+		if done {
+			return ErrAlreadyCompleted
+		}
+
+		if event.Capacity != nil {
+			reg, err := registrations.GetRegistration(ctx, token.EventId, userId)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+			if err != nil || reg.Status != models.RegistrationStatusRegistered {
+				return ErrNotRegistered
+			}
+		}
+
+		checkedInAt, err := repo.CreateCheckin(ctx, token.EventId, userId, token.Nonce)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return ErrAlreadyCheckedIn
+				case "23503":
+					return ErrUserNotFound
+				}
+			}
+			return err
+		}
+
+		if _, err = registrations.MarkAttended(ctx, token.EventId, []int64{userId}); err != nil {
+			return err
+		}
+
+		if err = s.completed.AddCompletedEventTx(ctx, tx, userId, userId, token.EventId); err != nil {
+			return err
+		}
+
+		details, err := s.events.WithDB(tx).GetEventById(ctx, token.EventId)
+		if err != nil {
+			return err
+		}
+
+		result = models.CheckinResponse{
+			EventId:     token.EventId,
+			Title:       details.Title,
+			Points:      details.Points,
+			CheckedInAt: checkedInAt,
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// notConfigured отличает событие без окна отметки от несуществующего события.
+func (s *CheckinService) notConfigured(ctx context.Context, eventId int64) error {
+	event, err := s.events.GetEventById(ctx, eventId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrEventNotFound
+		}
+		return err
+	}
+	if event.DeletedAt != nil {
+		return ErrEventNotFound
+	}
+	return ErrCheckinNotConfigured
+}
+
+// checkinWindowError проверяет, что now попадает в окно отметки.
+func checkinWindowError(settings models.CheckinSecret, now time.Time) error {
+	if now.Before(settings.OpensAt) {
+		return ErrCheckinNotOpen
+	}
+	if !now.Before(settings.ClosesAt) {
+		return ErrCheckinClosed
+	}
+	return nil
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	maxClaimAttachments  = 10
+	maxClaimCommentRunes = 2000
+)
+
+var (
+	ErrClaimNotFound       = errors.New("заявка не найдена")
+	ErrClaimNotPending     = errors.New("заявка уже рассмотрена или отозвана")
+	ErrClaimAlreadyPending = errors.New("заявка на это событие уже ждет рассмотрения")
+	ErrInvalidClaim        = errors.New("некорректная заявка")
+)
+
+// ClaimsService - заявки студентов на баллы: подача с подтверждениями, очередь модерации,
+// одобрение (через CompletedEventsService) и отклонение с причиной.
+type ClaimsService struct {
+	repo      *repositories.ClaimsRepository
+	completed *CompletedEventsService
+	audit     *AuditService
+	uow       *repositories.UoW
+}
+
+func NewClaimsService(
+	repo *repositories.ClaimsRepository,
+	completed *CompletedEventsService,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *ClaimsService {
+	return &ClaimsService{
+		repo:      repo,
+		completed: completed,
+		audit:     audit,
+		uow:       uow,
+	}
+}
+
+// Submit создает заявку студента userId на событие вместе с подтверждениями.
+func (s *ClaimsService) Submit(ctx context.Context, userId int64, req models.CreateClaimRequest) (models.Claim, error) {
+	req.Comment = strings.TrimSpace(req.Comment)
+	if err := validateClaim(req); err != nil {
+		return models.Claim{}, err
+	}
+
+	done, err := s.completed.IsCompleted(ctx, userId, req.EventId)
+	if err != nil {
+		return models.Claim{}, err
+	}
+	if done {
+		return models.Claim{}, ErrAlreadyCompleted
+	}
+
+	var claim models.Claim
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		id, err := repo.CreateClaim(ctx, userId, req.EventId, req.Comment)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return ErrClaimAlreadyPending
+				case "23503":
+					return ErrEventNotFound
+				}
+			}
+			return err
+		}
+
+		if err = repo.AddAttachments(ctx, id, req.Attachments); err != nil {
+			return err
+		}
+
+		claim, err = s.getClaim(ctx, repo, id, false)
+		return err
+	})
+
+	return claim, err
+}
+
+// GetMyClaims возвращает заявки студента userId, новые первыми.
+func (s *ClaimsService) GetMyClaims(ctx context.Context, userId int64) ([]models.Claim, error) {
+	return s.getClaims(ctx, models.ClaimsFilter{UserId: userId, Limit: 500})
+}
+
+// GetClaims возвращает очередь модерации. По умолчанию - заявки на рассмотрении, старые первыми.
+func (s *ClaimsService) GetClaims(ctx context.Context, filter models.ClaimsFilter) ([]models.Claim, error) {
+	if filter.Status == "" {
+		filter.Status = models.ClaimStatusPending
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = 50
+	}
+	if filter.Limit > 500 {
+		filter.Limit = 500
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.getClaims(ctx, filter)
+}
+
+// Withdraw отзывает заявку студента, пока она не рассмотрена.
+func (s *ClaimsService) Withdraw(ctx context.Context, userId, claimId int64) error {
+	ok, err := s.repo.Withdraw(ctx, claimId, userId)
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+
+	claim, err := s.repo.GetClaim(ctx, claimId, false)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrClaimNotFound
+		}
+		return err
+	}
+	if claim.UserId != userId {
+		return ErrClaimNotFound
+	}
+
+	return ErrClaimNotPending
+}
+
+// Approve одобряет заявку: событие отмечается выполненным у студента в той же транзакции,
+// что и смена статуса, поэтому баллы не начислятся дважды и не потеряются.
+func (s *ClaimsService) Approve(ctx context.Context, actorId, claimId int64) (models.Claim, error) {
+	var claim models.Claim
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := s.getClaim(ctx, repo, claimId, true)
+		if err != nil {
+			return err
+		}
+		if before.Status != models.ClaimStatusPending {
+			return ErrClaimNotPending
+		}
+
+		if err = s.completed.AddCompletedEventTx(ctx, tx, actorId, before.UserId, before.EventId); err != nil {
+			return err
+		}
+
+		if _, err = repo.Review(ctx, claimId, models.ClaimStatusApproved, actorId, nil); err != nil {
+			return err
+		}
+
+		claim, err = s.getClaim(ctx, repo, claimId, false)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditClaimApprove, AuditTargetClaim, claimId, before, claim)
+	})
+
+	return claim, err
+}
+
+// Reject отклоняет заявку с указанием причины.
+func (s *ClaimsService) Reject(ctx context.Context, actorId, claimId int64, reason string) (models.Claim, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return models.Claim{}, fmt.Errorf("%w: причина отклонения не указана", ErrInvalidClaim)
+	}
+
+	var claim models.Claim
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := s.getClaim(ctx, repo, claimId, true)
+		if err != nil {
+			return err
+		}
+		if before.Status != models.ClaimStatusPending {
+			return ErrClaimNotPending
+		}
+
+		if _, err = repo.Review(ctx, claimId, models.ClaimStatusRejected, actorId, &reason); err != nil {
+			return err
+		}
+
+		claim, err = s.getClaim(ctx, repo, claimId, false)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditClaimReject, AuditTargetClaim, claimId, before, claim)
+	})
+
+	return claim, err
+}
+
+// getClaims возвращает заявки по фильтру вместе с подтверждениями.
+func (s *ClaimsService) getClaims(ctx context.Context, filter models.ClaimsFilter) ([]models.Claim, error) {
+	claims, err := s.repo.GetClaims(ctx, filter)
+	if err != nil || len(claims) == 0 {
+		return claims, err
+	}
+
+	ids := make([]int64, len(claims))
+	for i, c := range claims {
+		ids[i] = c.Id
+	}
+
+	attachments, err := s.repo.GetAttachments(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	byClaim := make(map[int64][]models.ClaimAttachment)
+	for _, a := range attachments {
+		byClaim[a.ClaimId] = append(byClaim[a.ClaimId], a)
+	}
+	for i := range claims {
+		claims[i].Attachments = byClaim[claims[i].Id]
+		if claims[i].Attachments == nil {
+			claims[i].Attachments = []models.ClaimAttachment{}
+		}
+	}
+
+	return claims, nil
+}
+
+// getClaim возвращает заявку с подтверждениями через переданный репозиторий.
+func (s *ClaimsService) getClaim(ctx context.Context, repo *repositories.ClaimsRepository, id int64, forUpdate bool) (models.Claim, error) {
+	claim, err := repo.GetClaim(ctx, id, forUpdate)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return claim, ErrClaimNotFound
+		}
+		return claim, err
+	}
+
+	attachments, err := repo.GetAttachments(ctx, []int64{id})
+	if err != nil {
+		return claim, err
+	}
+	claim.Attachments = attachments
+	if claim.Attachments == nil {
+		claim.Attachments = []models.ClaimAttachment{}
+	}
+
+	return claim, nil
+}
+
+// validateClaim проверяет комментарий и подтверждения: не больше 10 ссылок, только http(s)
+// или пути на этом сервере (файлы, загруженные через /me/uploads в локальное хранилище).
+func validateClaim(req models.CreateClaimRequest) error {
+	if utf8.RuneCountInString(req.Comment) > maxClaimCommentRunes {
+		return fmt.Errorf("%w: комментарий длиннее %d символов", ErrInvalidClaim, maxClaimCommentRunes)
+	}
+	if len(req.Attachments) > maxClaimAttachments {
+		return fmt.Errorf("%w: больше %d подтверждений", ErrInvalidClaim, maxClaimAttachments)
+	}
+
+	for _, a := range req.Attachments {
+		u, err := url.Parse(strings.TrimSpace(a.Url))
+		if err != nil {
+			return fmt.Errorf("%w: некорректная ссылка %q", ErrInvalidClaim, a.Url)
+		}
+		local := u.Scheme == "" && u.Host == "" && strings.HasPrefix(u.Path, "/") && !strings.HasPrefix(u.Path, "//")
+		if !local && ((u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+			return fmt.Errorf("%w: некорректная ссылка %q", ErrInvalidClaim, a.Url)
+		}
+	}
+
+	return nil
+}
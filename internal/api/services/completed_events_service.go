@@ -5,6 +5,9 @@ import (
 	"bobri/internal/models"
 	"context"
 	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 var (
@@ -13,44 +16,110 @@ var (
 	ErrCompletedEventNotFound = errors.New("выполненное событие не найдено")
 )
 
+// CompletedEventsService - выполненные события. Баллы за них начисляются и списываются
+// записями в журнале баллов в той же транзакции.
 type CompletedEventsService struct {
-	repo *repositories.CompletedEventsRepository
-	uow  *repositories.UoW
+	repo   *repositories.CompletedEventsRepository
+	points *repositories.PointsRepository
+	audit  *AuditService
+	uow    *repositories.UoW
 }
 
 // NewCompletedEventsService создает сервис выполненных событий.
-func NewCompletedEventsService(repo *repositories.CompletedEventsRepository, uow *repositories.UoW) *CompletedEventsService {
+func NewCompletedEventsService(repo *repositories.CompletedEventsRepository, points *repositories.PointsRepository, audit *AuditService, uow *repositories.UoW) *CompletedEventsService {
 	return &CompletedEventsService{
-		repo: repo,
-		uow:  uow,
+		repo:   repo,
+		points: points,
+		audit:  audit,
+		uow:    uow,
 	}
 }
 
 // AddCompletedEvent добавляет выполненное событие пользователю.
-func (s *CompletedEventsService) AddCompletedEvent(ctx context.Context, userId, eventId int64) error {
+func (s *CompletedEventsService) AddCompletedEvent(ctx context.Context, actorId, userId, eventId int64) error {
 	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
-		// вызываем репозиторий через WithDB(tx)
-
-		err := s.repo.WithDB(tx).AddCompletedEvent(ctx, userId, eventId)
-		if err != nil {
-			return err
-		}
-		return nil
+		return s.AddCompletedEventTx(ctx, tx, actorId, userId, eventId)
 	})
 }
 
-// DeleteCompletedEvent удаляет отметку о выполнении события.
-func (s *CompletedEventsService) DeleteCompletedEvent(ctx context.Context, userId, eventId int64) error {
-	tag, err := s.repo.DeleteCompletedEvent(ctx, userId, eventId)
+// AddCompletedEventTx добавляет выполненное событие в уже открытой транзакции tx:
+// так начисление проходит атомарно вместе с действием, которое его вызвало (например, одобрением заявки).
+// Удаленное событие или черновик - ErrEventNotFound.
+func (s *CompletedEventsService) AddCompletedEventTx(ctx context.Context, tx repositories.DBTX, actorId, userId, eventId int64) error {
+	points, err := s.repo.WithDB(tx).AddCompletedEvent(ctx, userId, eventId)
 	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return ErrEventNotFound
+		case errors.As(err, &pgErr) && pgErr.Code == "23505":
+			return ErrAlreadyCompleted
+		case errors.As(err, &pgErr) && pgErr.Code == "23503":
+			return ErrInvalidReference
+		}
 		return err
 	}
 
-	if tag.RowsAffected() == 0 {
-		return ErrCompletedEventNotFound
+	err = s.points.WithDB(tx).AddTransaction(ctx, models.NewPointsTransaction{
+		UserId:  userId,
+		Delta:   points,
+		Reason:  models.PointsReasonEventCompleted,
+		EventId: &eventId,
+		ActorId: actorId,
+	})
+	if err != nil {
+		return err
+	}
+
+	// в журнал попадают и начисленные баллы
+	after, err := s.repo.WithDB(tx).GetCompletedEventDetails(ctx, userId, eventId)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return s.audit.Record(ctx, tx, actorId, AuditCompletedEventAdd, AuditTargetUser, userId, nil, after)
+}
+
+// IsCompleted проверяет, отмечено ли событие выполненным у пользователя.
+func (s *CompletedEventsService) IsCompleted(ctx context.Context, userId, eventId int64) (bool, error) {
+	return s.repo.IsCompleted(ctx, userId, eventId)
+}
+
+// DeleteCompletedEvent удаляет отметку о выполнении события.
+func (s *CompletedEventsService) DeleteCompletedEvent(ctx context.Context, actorId, userId, eventId int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		before, err := s.repo.WithDB(tx).GetCompletedEventDetails(ctx, userId, eventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrCompletedEventNotFound
+			}
+			return err
+		}
+
+		if _, err = s.repo.WithDB(tx).DeleteCompletedEvent(ctx, userId, eventId); err != nil {
+			return err
+		}
+
+		// списывается столько, сколько начислено за событие по журналу, даже если баллы события менялись
+		balance, err := s.points.WithDB(tx).GetEventBalance(ctx, userId, eventId)
+		if err != nil {
+			return err
+		}
+		if balance != 0 {
+			err = s.points.WithDB(tx).AddTransaction(ctx, models.NewPointsTransaction{
+				UserId:  userId,
+				Delta:   -balance,
+				Reason:  models.PointsReasonEventRevoked,
+				EventId: &eventId,
+				ActorId: actorId,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditCompletedEventDelete, AuditTargetUser, userId, before, nil)
+	})
 }
 
 // GetAllCompletedEvents возвращает список всех выполненных событий.
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestPool подключается к базе из TEST_DATABASE_URL и создает схему по pkg/migrations/tables.sql
+// в отдельной схеме, которая удаляется после теста. Без TEST_DATABASE_URL тест пропускается.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL не задан, тест с базой пропущен")
+	}
+
+	ctx := context.Background()
+
+	admin, err := pgx.Connect(ctx, dsn)
+	if err != nil {
+		t.Fatalf("could not connect to test database: %v", err)
+	}
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err = admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("could not create test schema: %v", err)
+	}
+	t.Cleanup(func() {
+		_, _ = admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		_ = admin.Close(context.Background())
+	})
+
+	config, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("could not parse TEST_DATABASE_URL: %v", err)
+	}
+	config.ConnConfig.RuntimeParams["search_path"] = schema
+
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("could not create pool: %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	ddl, err := os.ReadFile("../../../pkg/migrations/tables.sql")
+	if err != nil {
+		t.Fatalf("could not read tables.sql: %v", err)
+	}
+	if _, err = pool.Exec(ctx, string(ddl)); err != nil {
+		t.Fatalf("could not apply tables.sql: %v", err)
+	}
+
+	return pool
+}
+
+// authFixture - сервисы входа и сессий поверх тестовой базы.
+type authFixture struct {
+	pool        *pgxpool.Pool
+	userRepo    *repositories.UserRepository
+	refreshRepo *repositories.RefreshTokensRepository
+	login       *LoginService
+	refresh     *RefreshTokensService
+	account     *AccountService
+}
+
+func newAuthFixture(t *testing.T) *authFixture {
+	t.Helper()
+
+	pool := newTestPool(t)
+
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate signing key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatalf("could not marshal signing key: %v", err)
+	}
+	key, err := helpers.ParseSigningKey("test", pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		t.Fatalf("could not parse signing key: %v", err)
+	}
+	jwtMaker, err := helpers.NewJWTMaker([]*helpers.SigningKey{key}, "test", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("could not create jwt maker: %v", err)
+	}
+
+	uow := repositories.NewUoW(pool)
+	userRepo := repositories.NewUserRepository(pool)
+	refreshRepo := repositories.NewRefreshTokensRepository(pool)
+
+	auditService := NewAuditService(repositories.NewAuditRepository(pool))
+	throttleService := NewAuthThrottleService(repositories.NewAuthThrottleRepository(pool), auditService, uow)
+	mfaService := NewMFAService(repositories.NewMFARepository(pool), userRepo, refreshRepo, uow)
+	verificationService := NewEmailVerificationService(repositories.NewEmailVerificationRepository(pool), userRepo,
+		NewEmailProvider(models.EmailAuth{}), throttleService, EmailVerificationPolicy{}, uow)
+	tokenProvider := NewTokenProvider(jwtMaker, refreshRepo, repositories.NewPermissionsRepository(pool))
+
+	return &authFixture{
+		pool:        pool,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+		login:       NewLoginService(userRepo, tokenProvider, throttleService, mfaService, uow),
+		refresh:     NewRefreshTokensService(refreshRepo, tokenProvider, uow),
+		account: NewAccountService(userRepo, refreshRepo, repositories.NewCompletedEventsRepository(pool),
+			repositories.NewPointsRepository(pool), verificationService, uow),
+	}
+}
+
+// createUser создает студента с указанными почтой и паролем и возвращает его id.
+func (f *authFixture) createUser(t *testing.T, bookId int64, email, password string) int64 {
+	t.Helper()
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("could not hash password: %v", err)
+	}
+
+	userId, err := f.userRepo.CreateUser(context.Background(), models.User{
+		BookId:     bookId,
+		Name:       "Иван",
+		Surname:    "Иванов",
+		MiddleName: "Иванович",
+		Password:   hashed,
+		Email:      email,
+		RoleLevel:  10,
+	})
+	if err != nil {
+		t.Fatalf("could not create user: %v", err)
+	}
+
+	return userId
+}
+
+// loginUser входит под пользователем с нового устройства и возвращает refresh токен сессии.
+func (f *authFixture) loginUser(t *testing.T, email, password, device string) string {
+	t.Helper()
+
+	resp, err := f.login.Login(context.Background(), email, password, models.SessionMeta{DeviceName: device, IP: "127.0.0.1"})
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+
+	return resp.RefreshToken
+}
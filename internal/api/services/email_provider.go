@@ -24,9 +24,6 @@ func NewEmailProvider(emailAuth models.EmailAuth) *EmailProvider {
 }
 
 func (e *EmailProvider) SendResetPassword(email, rawToken string) error {
-	from := e.fromEmail
-	pass := e.password
-
 	htmlBody := `
 <h2>Здравствуйте!</h2>
 <p>Вы запросили сброс пароля для своего аккаунта в системе <b>Beaver</b>.</p>
@@ -45,13 +42,45 @@ func (e *EmailProvider) SendResetPassword(email, rawToken string) error {
 
 	htmlBody = strings.ReplaceAll(htmlBody, "{{TOKEN}}", rawToken)
 
+	return e.send(email, "Сброс пароля", htmlBody)
+}
+
+func (e *EmailProvider) SendEmailVerification(email, rawToken string) error {
+	htmlBody := `
+<h2>Здравствуйте!</h2>
+<p>Этот адрес указан при регистрации в системе <b>Beaver</b>.</p>
+
+<p>Чтобы подтвердить почту, введите токен в приложении. Токен действует 24 часа.</p>
+
+<p>
+    Token: {{TOKEN}}
+</p>
+
+<p>Если вы не регистрировались - просто проигнорируйте это письмо.</p>
+
+<hr>
+<p style="font-size:12px;color:gray;">
+С уважением,<br>
+Команда поддержки <b>Beaver</b>
+</p>`
+
+	htmlBody = strings.ReplaceAll(htmlBody, "{{TOKEN}}", rawToken)
+
+	return e.send(email, "Подтверждение почты", htmlBody)
+}
+
+// send отправляет html письмо через Gmail SMTP.
+func (e *EmailProvider) send(email, subject, htmlBody string) error {
+	from := e.fromEmail
+	pass := e.password
+
 	msg := []byte(fmt.Sprintf(
-		"Subject: Сброс пароля\r\n"+
+		"Subject: %s\r\n"+
 			"MIME-Version: 1.0\r\n"+
 			"Content-Type: text/html; charset=UTF-8\r\n"+
 			"From: %s\r\n"+
 			"To: %s\r\n\r\n%s",
-		from, email, htmlBody,
+		subject, from, email, htmlBody,
 	))
 
 	// адрес Gmail SMTP
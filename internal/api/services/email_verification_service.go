@@ -0,0 +1,190 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/pkg/helpers"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// emailVerificationTTL - время жизни токена подтверждения почты.
+const emailVerificationTTL = 24 * time.Hour
+
+// Действия, доступ к которым может зависеть от подтверждения почты.
+const (
+	VerificationAreaPasswordReset = "password_reset"
+	VerificationAreaUser          = "user"
+	VerificationAreaAdmin         = "admin"
+)
+
+var (
+	ErrInvalidVerificationToken = errors.New("невалидный или истекший токен подтверждения почты")
+	ErrEmailAlreadyVerified     = errors.New("почта уже подтверждена")
+	ErrEmailNotVerified         = errors.New("почта не подтверждена")
+	ErrUnknownVerificationMode  = errors.New("неизвестная политика подтверждения почты")
+)
+
+// EmailVerificationPolicy - что разрешено пользователям с неподтвержденной почтой.
+// Вход, подтверждение и повторная отправка письма доступны всегда.
+type EmailVerificationPolicy struct {
+	// AllowPasswordReset - отправлять письмо сброса пароля на неподтвержденную почту
+	AllowPasswordReset bool
+	// AllowUserRoutes - доступ к маршрутам /me
+	AllowUserRoutes bool
+	// AllowAdminRoutes - доступ к маршрутам /admin
+	AllowAdminRoutes bool
+}
+
+// ParseEmailVerificationPolicy возвращает политику по названию режима (EMAIL_VERIFICATION_POLICY):
+//   - off: подтверждение почты ни на что не влияет;
+//   - reset (по умолчанию): сброс пароля только на подтвержденную почту;
+//   - strict: без подтвержденной почты доступен только вход и само подтверждение.
+func ParseEmailVerificationPolicy(mode string) (EmailVerificationPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(mode)) {
+	case "off":
+		return EmailVerificationPolicy{AllowPasswordReset: true, AllowUserRoutes: true, AllowAdminRoutes: true}, nil
+	case "", "reset":
+		return EmailVerificationPolicy{AllowUserRoutes: true, AllowAdminRoutes: true}, nil
+	case "strict":
+		return EmailVerificationPolicy{}, nil
+	default:
+		return EmailVerificationPolicy{}, fmt.Errorf("%w: %s", ErrUnknownVerificationMode, mode)
+	}
+}
+
+func (p EmailVerificationPolicy) allows(area string) bool {
+	switch area {
+	case VerificationAreaPasswordReset:
+		return p.AllowPasswordReset
+	case VerificationAreaUser:
+		return p.AllowUserRoutes
+	case VerificationAreaAdmin:
+		return p.AllowAdminRoutes
+	default:
+		return false
+	}
+}
+
+type EmailVerificationService struct {
+	verifyRepo    *repositories.EmailVerificationRepository
+	userRepo      *repositories.UserRepository
+	emailProvider *EmailProvider
+	throttle      *AuthThrottleService
+	policy        EmailVerificationPolicy
+	uow           *repositories.UoW
+}
+
+func NewEmailVerificationService(
+	verifyRepo *repositories.EmailVerificationRepository,
+	userRepo *repositories.UserRepository,
+	emailProvider *EmailProvider,
+	throttle *AuthThrottleService,
+	policy EmailVerificationPolicy,
+	uow *repositories.UoW,
+) *EmailVerificationService {
+	return &EmailVerificationService{
+		verifyRepo:    verifyRepo,
+		userRepo:      userRepo,
+		emailProvider: emailProvider,
+		throttle:      throttle,
+		policy:        policy,
+		uow:           uow,
+	}
+}
+
+// IssueToken создает новый токен подтверждения почты (обычно внутри транзакции)
+// и возвращает его в открытом виде для отправки письмом.
+func (s *EmailVerificationService) IssueToken(ctx context.Context, db repositories.DBTX, userId int64, email string) (string, error) {
+	rawToken, err := helpers.GenerateTokenRaw(32)
+	if err != nil {
+		return "", errors.New("ошибка генерации токена")
+	}
+
+	err = s.verifyRepo.WithDB(db).UpsertVerificationToken(ctx, userId, email, helpers.HashToken(rawToken), time.Now().Add(emailVerificationTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+// SendToken отправляет письмо с токеном подтверждения.
+func (s *EmailVerificationService) SendToken(email, rawToken string) error {
+	return s.emailProvider.SendEmailVerification(email, rawToken)
+}
+
+// VerifyEmail подтверждает почту по токену из письма.
+// Токен действует только для той почты, на которую был отправлен.
+func (s *EmailVerificationService) VerifyEmail(ctx context.Context, token string) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.verifyRepo.WithDB(tx)
+
+		userId, email, err := repo.GetVerificationToken(ctx, helpers.HashToken(token))
+		if err != nil {
+			return ErrInvalidVerificationToken
+		}
+
+		verified, err := repo.MarkEmailVerified(ctx, userId, email)
+		if err != nil {
+			return err
+		}
+		if !verified {
+			// почту успели поменять после отправки письма
+			return ErrInvalidVerificationToken
+		}
+
+		return repo.DeleteVerificationToken(ctx, userId)
+	})
+}
+
+// ResendVerification отправляет новое письмо с токеном подтверждения текущей почты пользователя.
+func (s *EmailVerificationService) ResendVerification(ctx context.Context, userId int64, ip string) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return ErrUserNotFound
+	}
+
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	// каждый запрос учитывается, чтобы нельзя было заваливать почту письмами
+	if err = s.throttle.Check(ctx, ThrottleScopeVerifyEmail, ip, user.Email); err != nil {
+		return err
+	}
+	if err = s.throttle.RegisterAttempt(ctx, ThrottleScopeVerifyEmail, ip, user.Email); err != nil {
+		return err
+	}
+
+	var rawToken string
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		rawToken, err = s.IssueToken(ctx, tx, user.Id, user.Email)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.SendToken(user.Email, rawToken)
+}
+
+// CheckAccess проверяет, разрешено ли действие area пользователю с учетом политики.
+// Возвращает ErrEmailNotVerified, если почта не подтверждена, а политика это требует.
+func (s *EmailVerificationService) CheckAccess(ctx context.Context, userId int64, area string) error {
+	if s.policy.allows(area) {
+		return nil
+	}
+
+	verified, err := s.verifyRepo.IsEmailVerified(ctx, userId)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		return ErrEmailNotVerified
+	}
+
+	return nil
+}
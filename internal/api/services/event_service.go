@@ -4,6 +4,8 @@ import (
 	"bobri/internal/api/repositories"
 	"bobri/internal/models"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"time"
 
@@ -14,83 +16,423 @@ import (
 var (
 	ErrEventAlreadyExists = errors.New("событие с таким названием уже существует")
 	ErrEventNotFound      = errors.New("событие не найдено")
+	ErrInvalidEventSearch = errors.New("некорректные параметры поиска событий")
+	ErrInvalidEventStatus = errors.New("некорректный статус события: ожидается draft или published")
+	ErrEventDeleted       = errors.New("событие удалено")
+	ErrEventNotDeleted    = errors.New("событие не удалено")
+)
+
+// Размер страницы поиска событий.
+const (
+	DefaultEventSearchLimit = 20
+	MaxEventSearchLimit     = 100
 )
 
 type EventService struct {
-	events *repositories.EventRepository
-	uow    *repositories.UoW
+	events        *repositories.EventRepository
+	registrations *repositories.RegistrationsRepository
+	points        *repositories.PointsRepository
+	audit         *AuditService
+	uow           *repositories.UoW
 }
 
-func NewEventService(repo *repositories.EventRepository, uow *repositories.UoW) *EventService {
+func NewEventService(
+	repo *repositories.EventRepository,
+	registrations *repositories.RegistrationsRepository,
+	points *repositories.PointsRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *EventService {
 	return &EventService{
-		events: repo,
-		uow:    uow,
+		events:        repo,
+		registrations: registrations,
+		points:        points,
+		audit:         audit,
+		uow:           uow,
 	}
 }
 
 // CreateEvent создает новое событие.
-func (s *EventService) CreateEvent(ctx context.Context, data models.CreateEventRequest) (models.CreateEventResponse, error) {
+func (s *EventService) CreateEvent(ctx context.Context, actorId int64, data models.CreateEventRequest) (models.CreateEventResponse, error) {
 	var result models.CreateEventResponse
 
+	if data.Status != "" && data.Status != models.EventStatusDraft && data.Status != models.EventStatusPublished {
+		return result, ErrInvalidEventStatus
+	}
+	if err := validateRegistrationSettings(data.Capacity, false, data.RegistrationOpensAt, data.RegistrationClosesAt); err != nil {
+		return result, err
+	}
+
 	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
 		id, err := s.events.WithDB(tx).CreateEvent(ctx, data)
 		if err != nil {
-			var pgErr *pgconn.PgError
-			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-				return ErrEventAlreadyExists
+			return mapEventWriteError(err)
+		}
+
+		result, err = s.events.WithDB(tx).GetEventById(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventCreate, AuditTargetEvent, id, nil, result)
+	})
+
+	return result, err
+}
+
+// DeleteEvent мягко удаляет событие: оно пропадает из списков, но выполненные события
+// и баллы студентов остаются, поэтому суммы баллов не меняются. Событие можно восстановить.
+func (s *EventService) DeleteEvent(ctx context.Context, actorId, eventId int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		before, err := s.events.WithDB(tx).GetEventById(ctx, eventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
 			}
 			return err
 		}
+		if before.DeletedAt != nil {
+			return ErrEventDeleted
+		}
 
-		result, err = s.events.WithDB(tx).GetEventById(ctx, id)
-		return err
+		if _, err = s.events.WithDB(tx).DeleteEvent(ctx, eventId); err != nil {
+			return err
+		}
+
+		after, err := s.events.WithDB(tx).GetEventById(ctx, eventId)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventDelete, AuditTargetEvent, eventId, before, after)
+	})
+}
+
+// RestoreEvent восстанавливает удаленное событие с тем статусом, который был до удаления.
+func (s *EventService) RestoreEvent(ctx context.Context, actorId, eventId int64) (models.CreateEventResponse, error) {
+	return s.changeEvent(ctx, actorId, eventId, AuditEventRestore, func(ctx context.Context, repo *repositories.EventRepository, before models.CreateEventResponse) error {
+		if before.DeletedAt == nil {
+			return ErrEventNotDeleted
+		}
+		return repo.RestoreEvent(ctx, eventId)
+	})
+}
+
+// PublishEvent публикует черновик или возвращает событие из архива.
+func (s *EventService) PublishEvent(ctx context.Context, actorId, eventId int64) (models.CreateEventResponse, error) {
+	return s.setStatus(ctx, actorId, eventId, models.EventStatusPublished, AuditEventPublish)
+}
+
+// ArchiveEvent отправляет событие в архив: оно пропадает из каталога, запись и отметка закрываются.
+// Выполненные события и баллы сохраняются.
+func (s *EventService) ArchiveEvent(ctx context.Context, actorId, eventId int64) (models.CreateEventResponse, error) {
+	return s.setStatus(ctx, actorId, eventId, models.EventStatusArchived, AuditEventArchive)
+}
+
+func (s *EventService) setStatus(ctx context.Context, actorId, eventId int64, status, action string) (models.CreateEventResponse, error) {
+	return s.changeEvent(ctx, actorId, eventId, action, func(ctx context.Context, repo *repositories.EventRepository, before models.CreateEventResponse) error {
+		if before.DeletedAt != nil {
+			return ErrEventDeleted
+		}
+		return repo.SetStatus(ctx, eventId, status)
+	})
+}
+
+// changeEvent применяет изменение к событию в транзакции и записывает его в журнал с состоянием до и после.
+func (s *EventService) changeEvent(
+	ctx context.Context,
+	actorId, eventId int64,
+	action string,
+	change func(ctx context.Context, repo *repositories.EventRepository, before models.CreateEventResponse) error,
+) (models.CreateEventResponse, error) {
+	var result models.CreateEventResponse
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.events.WithDB(tx)
+
+		before, err := repo.GetEventById(ctx, eventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		if err = change(ctx, repo, before); err != nil {
+			return err
+		}
+
+		if result, err = repo.GetEventById(ctx, eventId); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, action, AuditTargetEvent, eventId, before, result)
 	})
 
 	return result, err
 }
 
-// DeleteEvent удаляет событие по id.
-func (s *EventService) DeleteEvent(ctx context.Context, eventId int64) error {
-	tag, err := s.events.DeleteEvent(ctx, eventId)
+// GetEvents возвращает список событий, кроме удаленных.
+func (s *EventService) GetEvents(ctx context.Context, limit int) ([]models.Event, error) {
+	return s.events.GetEvents(ctx, limit)
+}
+
+// SearchEvents ищет события по тексту и фильтрам. Следующая страница запрашивается с NextCursor
+// из ответа и теми же параметрами сортировки; курсор от другой сортировки не принимается.
+func (s *EventService) SearchEvents(ctx context.Context, filter models.EventSearchFilter) (models.EventSearchResponse, error) {
+	if filter.Sort == "" {
+		filter.Sort = models.EventSortDate
+	}
+	if filter.Sort != models.EventSortDate && filter.Sort != models.EventSortPoints {
+		return models.EventSearchResponse{}, ErrInvalidEventSearch
+	}
+	if filter.DateFrom != nil && filter.DateTo != nil && !filter.DateTo.After(*filter.DateFrom) {
+		return models.EventSearchResponse{}, ErrInvalidEventSearch
+	}
+	if filter.PointsMin != nil && filter.PointsMax != nil && *filter.PointsMin > *filter.PointsMax {
+		return models.EventSearchResponse{}, ErrInvalidEventSearch
+	}
+	if filter.Cursor != "" {
+		after, err := decodeEventCursor(filter.Cursor)
+		if err != nil || after.Sort != filter.Sort || after.Desc != filter.Desc {
+			return models.EventSearchResponse{}, ErrInvalidEventSearch
+		}
+		filter.After = &after
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultEventSearchLimit
+	}
+	limit = min(limit, MaxEventSearchLimit)
+
+	// одна лишняя запись показывает, есть ли следующая страница
+	filter.Limit = limit + 1
+	events, err := s.events.SearchEvents(ctx, filter)
 	if err != nil {
-		return err
+		return models.EventSearchResponse{}, err
 	}
 
-	if tag.RowsAffected() == 0 {
-		return ErrEventNotFound
+	result := models.EventSearchResponse{Items: events}
+	if len(events) > limit {
+		result.Items = events[:limit]
+		last := result.Items[limit-1]
+		result.NextCursor = encodeEventCursor(models.EventCursor{
+			Sort:      filter.Sort,
+			Desc:      filter.Desc,
+			EventDate: last.EventDate,
+			Points:    last.Points,
+			EventId:   last.EventID,
+		})
 	}
 
-	return nil
+	return result, nil
 }
 
-// GetEvents возвращает список всех событий.
-func (s *EventService) GetEvents(ctx context.Context, limit int) ([]models.Event, error) {
-	return s.events.GetEvents(ctx, limit)
+// SearchCatalog ищет среди опубликованных событий для студента. У каждого события отмечено,
+// засчитано ли оно пользователю.
+func (s *EventService) SearchCatalog(ctx context.Context, userId int64, filter models.EventSearchFilter) (models.EventCatalogResponse, error) {
+	filter.Statuses = []string{models.EventStatusPublished}
+	filter.Deleted = false
+
+	page, err := s.SearchEvents(ctx, filter)
+	if err != nil {
+		return models.EventCatalogResponse{}, err
+	}
+
+	items, err := s.catalogEvents(ctx, userId, page.Items)
+	if err != nil {
+		return models.EventCatalogResponse{}, err
+	}
+
+	return models.EventCatalogResponse{Items: items, NextCursor: page.NextCursor}, nil
 }
 
-// UpdateEvent обновляет событие.
-func (s *EventService) UpdateEvent(ctx context.Context, req models.UpdateEventRequest) error {
-	return s.events.UpdateEvent(ctx, req)
+// GetCatalog возвращает каталог опубликованных событий для студента: предстоящие по возрастанию даты,
+// прошедшие - начиная с последних. У каждого события отмечено, засчитано ли оно пользователю.
+func (s *EventService) GetCatalog(ctx context.Context, userId int64, when string, filter models.EventSearchFilter) (models.EventCatalogResponse, error) {
+	now := time.Now()
+	filter.Sort = models.EventSortDate
+	switch when {
+	case models.EventsUpcoming, "":
+		filter.DateFrom, filter.Desc = &now, false
+	case models.EventsPast:
+		filter.DateTo, filter.Desc = &now, true
+	case models.EventsAll:
+		filter.Desc = true
+	default:
+		return models.EventCatalogResponse{}, ErrInvalidEventSearch
+	}
+
+	return s.SearchCatalog(ctx, userId, filter)
 }
 
-func (s *EventService) SuggestEvent(ctx context.Context, eventId, expiresAtHours int64) error {
-	tag, err := s.events.CreateSuggest(ctx, eventId, time.Now().Add(time.Hour*time.Duration(expiresAtHours)))
+// GetCatalogEvent возвращает событие каталога для студента. Событие из архива тоже доступно
+// (например, по ссылке из выполненных), черновики и удаленные - нет.
+func (s *EventService) GetCatalogEvent(ctx context.Context, userId, eventId int64) (models.CatalogEvent, error) {
+	event, err := s.events.GetEventById(ctx, eventId)
 	if err != nil {
-		return err
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.CatalogEvent{}, ErrEventNotFound
+		}
+		return models.CatalogEvent{}, err
 	}
-	if tag.RowsAffected() == 0 {
-		return ErrNoRowsAffected
+	if event.DeletedAt != nil || event.Status == models.EventStatusDraft {
+		return models.CatalogEvent{}, ErrEventNotFound
 	}
-	return nil
+
+	items, err := s.catalogEvents(ctx, userId, []models.Event{models.Event(event)})
+	if err != nil {
+		return models.CatalogEvent{}, err
+	}
+	return items[0], nil
 }
 
-func (s *EventService) DeleteSuggestion(ctx context.Context, eventId int64) error {
-	_, err := s.events.DeleteSuggest(ctx, eventId)
+// catalogEvents дополняет события отметкой о выполнении пользователем и числом участников.
+func (s *EventService) catalogEvents(ctx context.Context, userId int64, events []models.Event) ([]models.CatalogEvent, error) {
+	items := make([]models.CatalogEvent, len(events))
+	if len(events) == 0 {
+		return items, nil
+	}
+
+	ids := make([]int64, len(events))
+	for i, event := range events {
+		ids[i] = event.EventID
+	}
+
+	stats, err := s.events.GetEventUserStats(ctx, userId, ids)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrNoRowsAffected
+		return nil, err
+	}
+	byEvent := make(map[int64]models.EventUserStats, len(stats))
+	for _, st := range stats {
+		byEvent[st.EventId] = st
+	}
+
+	for i, event := range events {
+		st := byEvent[event.EventID]
+		items[i] = models.CatalogEvent{
+			Event:             event,
+			Completed:         st.CompletedAt != nil,
+			CompletedAt:       st.CompletedAt,
+			ParticipantsCount: st.ParticipantsCount,
 		}
+	}
+
+	return items, nil
+}
+
+func encodeEventCursor(cursor models.EventCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeEventCursor(raw string) (models.EventCursor, error) {
+	var cursor models.EventCursor
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return cursor, err
+	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
+
+// UpdateEvent обновляет событие. Если мест стало больше, ожидающие из листа ожидания
+// получают освободившиеся места в той же транзакции. Если изменились баллы, всем, кому событие
+// уже засчитано, в журнал баллов добавляется разница.
+func (s *EventService) UpdateEvent(ctx context.Context, actorId int64, req models.UpdateEventRequest) error {
+	if err := validateRegistrationSettings(req.NewData.Capacity, true, nil, nil); err != nil {
 		return err
 	}
-	return nil
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		before, err := s.events.WithDB(tx).GetEventById(ctx, req.EventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+		if before.DeletedAt != nil {
+			return ErrEventDeleted
+		}
+
+		if err = s.events.WithDB(tx).UpdateEvent(ctx, req); err != nil {
+			return mapEventWriteError(err)
+		}
+
+		after, err := s.events.WithDB(tx).GetEventById(ctx, req.EventId)
+		if err != nil {
+			return err
+		}
+		if err = validateRegistrationSettings(nil, true, after.RegistrationOpensAt, after.RegistrationClosesAt); err != nil {
+			return err
+		}
+
+		if req.NewData.Capacity != nil {
+			if _, err = s.registrations.WithDB(tx).PromoteWaitlist(ctx, req.EventId); err != nil {
+				return err
+			}
+		}
+
+		if after.Points != before.Points {
+			_, err = s.points.WithDB(tx).SetEventPoints(ctx, req.EventId, after.Points, models.PointsReasonEventRepriced, actorId, "")
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventUpdate, AuditTargetEvent, req.EventId, before, after)
+	})
+}
+
+func (s *EventService) SuggestEvent(ctx context.Context, actorId, eventId, expiresAtHours int64) error {
+	expiresAt := time.Now().Add(time.Hour * time.Duration(expiresAtHours))
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		tag, err := s.events.WithDB(tx).CreateSuggest(ctx, eventId, expiresAt)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrNoRowsAffected
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditSuggestCreate, AuditTargetEvent, eventId, nil, map[string]any{
+			"event_id":   eventId,
+			"expires_at": expiresAt,
+		})
+	})
+}
+
+func (s *EventService) DeleteSuggestion(ctx context.Context, actorId, eventId int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		_, err := s.events.WithDB(tx).DeleteSuggest(ctx, eventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNoRowsAffected
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditSuggestDelete, AuditTargetEvent, eventId, map[string]any{
+			"event_id": eventId,
+		}, nil)
+	})
+}
+
+// mapEventWriteError переводит нарушения ограничений при записи события в ошибки сервиса:
+// занятое название и несуществующий тип мероприятия.
+func mapEventWriteError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrEventAlreadyExists
+		case "23503":
+			return ErrEventTypeNotFound
+		}
+	}
+	return err
 }
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrEventTypeNotFound      = errors.New("тип мероприятия не найден")
+	ErrEventTypeAlreadyExists = errors.New("тип мероприятия с таким кодом уже существует")
+	ErrEventTypeProtected     = errors.New("тип 0 (неизвестно) нельзя удалить")
+	ErrInvalidEventType       = errors.New("некорректные данные типа мероприятия")
+)
+
+// eventTypeDefaultColor - цвет типа, если он не указан при создании.
+const eventTypeDefaultColor = "#9E9E9E"
+
+var eventTypeColorRe = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// EventTypesService - справочник типов мероприятий.
+type EventTypesService struct {
+	repo  *repositories.EventTypesRepository
+	audit *AuditService
+	uow   *repositories.UoW
+}
+
+func NewEventTypesService(
+	repo *repositories.EventTypesRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *EventTypesService {
+	return &EventTypesService{
+		repo:  repo,
+		audit: audit,
+		uow:   uow,
+	}
+}
+
+// GetEventTypes возвращает все типы мероприятий.
+func (s *EventTypesService) GetEventTypes(ctx context.Context) ([]models.EventType, error) {
+	return s.repo.GetEventTypes(ctx)
+}
+
+// CreateEventType добавляет тип мероприятия. Код 0 занят типом "Неизвестно".
+func (s *EventTypesService) CreateEventType(ctx context.Context, actorId int64, req models.CreateEventTypeRequest) (models.EventType, error) {
+	eventType := models.EventType{
+		Code:    req.Code,
+		Name:    strings.TrimSpace(req.Name),
+		IconUrl: strings.TrimSpace(req.IconUrl),
+		Color:   strings.TrimSpace(req.Color),
+	}
+	if eventType.Color == "" {
+		eventType.Color = eventTypeDefaultColor
+	}
+	if eventType.Code <= 0 || eventType.Name == "" || !eventTypeColorRe.MatchString(eventType.Color) {
+		return models.EventType{}, ErrInvalidEventType
+	}
+
+	var created models.EventType
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		var err error
+		if created, err = s.repo.WithDB(tx).CreateEventType(ctx, eventType); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return ErrEventTypeAlreadyExists
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventTypeCreate, AuditTargetEventType, created.Code, nil, created)
+	})
+
+	return created, err
+}
+
+// UpdateEventType меняет название, иконку или цвет типа.
+func (s *EventTypesService) UpdateEventType(ctx context.Context, actorId int64, code int, req models.UpdateEventTypeRequest) (models.EventType, error) {
+	color := strings.TrimSpace(req.Color)
+	if color != "" && !eventTypeColorRe.MatchString(color) {
+		return models.EventType{}, ErrInvalidEventType
+	}
+
+	var updated models.EventType
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetEventType(ctx, code)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventTypeNotFound
+			}
+			return err
+		}
+
+		updated = before
+		if v := strings.TrimSpace(req.Name); v != "" {
+			updated.Name = v
+		}
+		if v := strings.TrimSpace(req.IconUrl); v != "" {
+			updated.IconUrl = v
+		}
+		if color != "" {
+			updated.Color = color
+		}
+
+		if updated, err = repo.UpdateEventType(ctx, updated); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventTypeUpdate, AuditTargetEventType, code, before, updated)
+	})
+
+	return updated, err
+}
+
+// DeleteEventType удаляет тип мероприятия, его события получают тип 0.
+func (s *EventTypesService) DeleteEventType(ctx context.Context, actorId int64, code int) error {
+	if code == 0 {
+		return ErrEventTypeProtected
+	}
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetEventType(ctx, code)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventTypeNotFound
+			}
+			return err
+		}
+
+		if err = repo.DeleteEventType(ctx, code); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventTypeDelete, AuditTargetEventType, code, before, nil)
+	})
+}
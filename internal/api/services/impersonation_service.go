@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrImpersonateSelf            = errors.New("нельзя войти от своего имени")
+	ErrImpersonationNested        = errors.New("нельзя начать вход от имени пользователя из чужой сессии")
+	ErrImpersonationNotFound      = errors.New("сессия входа от имени пользователя не найдена или уже завершена")
+	ErrImpersonationReasonMissing = errors.New("нужно указать причину входа от имени пользователя")
+)
+
+// ImpersonationService - вход разработчика от имени пользователя для отладки.
+// Каждый вход записывается в impersonation_sessions и в журнал действий.
+type ImpersonationService struct {
+	repo     *repositories.ImpersonationRepository
+	userRepo *repositories.UserRepository
+	tokens   *TokenProvider
+	audit    *AuditService
+	uow      *repositories.UoW
+}
+
+func NewImpersonationService(
+	repo *repositories.ImpersonationRepository,
+	userRepo *repositories.UserRepository,
+	tokens *TokenProvider,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *ImpersonationService {
+	return &ImpersonationService{
+		repo:     repo,
+		userRepo: userRepo,
+		tokens:   tokens,
+		audit:    audit,
+		uow:      uow,
+	}
+}
+
+// Start выдает actor access токен от имени пользователя userId. По умолчанию токен только для чтения.
+// Входить можно только к пользователям с ролью ниже своей.
+func (s *ImpersonationService) Start(ctx context.Context, actor *models.Payload, userId int64, req models.StartImpersonationRequest) (models.ImpersonationResponse, error) {
+	if actor.IsImpersonated() {
+		return models.ImpersonationResponse{}, ErrImpersonationNested
+	}
+	if userId == actor.Sub {
+		return models.ImpersonationResponse{}, ErrImpersonateSelf
+	}
+
+	reason := strings.TrimSpace(req.Reason)
+	if reason == "" {
+		return models.ImpersonationResponse{}, ErrImpersonationReasonMissing
+	}
+
+	readOnly := true
+	if req.ReadOnly != nil {
+		readOnly = *req.ReadOnly
+	}
+
+	target, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ImpersonationResponse{}, ErrUserNotFound
+		}
+		return models.ImpersonationResponse{}, err
+	}
+	if target.RoleLevel >= actor.RoleLevel {
+		return models.ImpersonationResponse{}, ErrForbidden
+	}
+
+	var resp models.ImpersonationResponse
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		expiresAt := time.Now().Add(s.tokens.ImpersonationTTL())
+
+		session, err := s.repo.WithDB(tx).CreateSession(ctx, actor.Sub, userId, reason, readOnly, expiresAt)
+		if err != nil {
+			return err
+		}
+
+		accessToken, expUnix, err := s.tokens.IssueImpersonation(ctx, tx, actor.Sub, userId, target.RoleLevel, session.Id, readOnly)
+		if err != nil {
+			return err
+		}
+
+		resp = models.ImpersonationResponse{
+			AccessToken: accessToken,
+			ExpUnix:     expUnix,
+			Session:     session,
+		}
+
+		return s.audit.Record(ctx, tx, actor.Sub, AuditImpersonationStart, AuditTargetUser, userId, nil, session)
+	})
+	if err != nil {
+		return models.ImpersonationResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// End завершает вход от имени пользователя: выданный токен перестает приниматься.
+// Завершить можно только свою сессию; чужие - только с правом impersonations:manage,
+// иначе сессия считается ненайденной.
+func (s *ImpersonationService) End(ctx context.Context, actor *models.Payload, sessionId int64) (models.ImpersonationSession, error) {
+	var session models.ImpersonationSession
+
+	owner := actor.Sub
+	if actor.HasPermission(models.PermissionImpersonationsManage) {
+		owner = 0
+	}
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		var err error
+		session, err = s.repo.WithDB(tx).EndSession(ctx, sessionId, owner)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrImpersonationNotFound
+			}
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actor.Sub, AuditImpersonationEnd, AuditTargetUser, session.UserId, nil, session)
+	})
+	if err != nil {
+		return models.ImpersonationSession{}, err
+	}
+
+	return session, nil
+}
+
+// GetSessions возвращает последние входы от имени пользователей, userId = 0 - по всем.
+func (s *ImpersonationService) GetSessions(ctx context.Context, userId int64, limit int) ([]models.ImpersonationSession, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	return s.repo.GetSessions(ctx, userId, limit)
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrInstituteNotFound    = errors.New("институт не найден")
+	ErrStudyNotFound        = errors.New("направление не найдено")
+	ErrStudentGroupNotFound = errors.New("группа не найдена")
+	ErrStructureNameTaken   = errors.New("такое название уже используется")
+	ErrStructureNotEmpty    = errors.New("нельзя удалить: внутри остались направления или группы")
+	ErrStudentGroupInUse    = errors.New("нельзя удалить группу, в которой есть студенты или пользователи")
+	ErrStructureNameEmpty   = errors.New("название не может быть пустым")
+)
+
+// InstitutesService - структура университета: институты, направления подготовки и группы.
+type InstitutesService struct {
+	repo  *repositories.InstitutesRepository
+	audit *AuditService
+	uow   *repositories.UoW
+}
+
+func NewInstitutesService(
+	repo *repositories.InstitutesRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *InstitutesService {
+	return &InstitutesService{
+		repo:  repo,
+		audit: audit,
+		uow:   uow,
+	}
+}
+
+// GetTree возвращает институты с направлениями и группами, а также группы без направления.
+func (s *InstitutesService) GetTree(ctx context.Context) (models.InstitutesTree, error) {
+	institutes, err := s.repo.GetInstitutes(ctx)
+	if err != nil {
+		return models.InstitutesTree{}, err
+	}
+	studies, err := s.repo.GetStudies(ctx)
+	if err != nil {
+		return models.InstitutesTree{}, err
+	}
+	groups, err := s.repo.GetGroups(ctx)
+	if err != nil {
+		return models.InstitutesTree{}, err
+	}
+
+	tree := models.InstitutesTree{
+		Institutes: institutes,
+		Unassigned: []models.StudentGroup{},
+	}
+	if tree.Institutes == nil {
+		tree.Institutes = []models.Institute{}
+	}
+
+	groupsByStudy := make(map[int64][]models.StudentGroup)
+	for _, g := range groups {
+		if g.StudyId == 0 {
+			tree.Unassigned = append(tree.Unassigned, g)
+			continue
+		}
+		groupsByStudy[g.StudyId] = append(groupsByStudy[g.StudyId], g)
+	}
+
+	studiesByInstitute := make(map[int64][]models.Study)
+	for _, st := range studies {
+		st.Groups = groupsByStudy[st.Id]
+		studiesByInstitute[st.InstituteId] = append(studiesByInstitute[st.InstituteId], st)
+	}
+
+	for i := range tree.Institutes {
+		tree.Institutes[i].Studies = studiesByInstitute[tree.Institutes[i].Id]
+	}
+
+	return tree, nil
+}
+
+// CreateInstitute добавляет институт.
+func (s *InstitutesService) CreateInstitute(ctx context.Context, actorId int64, name string) (models.Institute, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Institute{}, ErrStructureNameEmpty
+	}
+
+	var institute models.Institute
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		var err error
+		if institute, err = s.repo.WithDB(tx).CreateInstitute(ctx, name); err != nil {
+			return mapStructureError(err, ErrInstituteNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditInstituteCreate, AuditTargetInstitute, institute.Id, nil, institute)
+	})
+
+	return institute, err
+}
+
+// UpdateInstitute переименовывает институт.
+func (s *InstitutesService) UpdateInstitute(ctx context.Context, actorId, id int64, name string) (models.Institute, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return models.Institute{}, ErrStructureNameEmpty
+	}
+
+	var institute models.Institute
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetInstitute(ctx, id)
+		if err != nil {
+			return mapStructureError(err, ErrInstituteNotFound)
+		}
+
+		if institute, err = repo.UpdateInstitute(ctx, id, name); err != nil {
+			return mapStructureError(err, ErrInstituteNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditInstituteUpdate, AuditTargetInstitute, id, before, institute)
+	})
+
+	return institute, err
+}
+
+// DeleteInstitute удаляет пустой институт.
+func (s *InstitutesService) DeleteInstitute(ctx context.Context, actorId, id int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetInstitute(ctx, id)
+		if err != nil {
+			return mapStructureError(err, ErrInstituteNotFound)
+		}
+
+		deleted, err := repo.DeleteInstitute(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrStructureNotEmpty
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditInstituteDelete, AuditTargetInstitute, id, before, nil)
+	})
+}
+
+// CreateStudy добавляет направление в институт.
+func (s *InstitutesService) CreateStudy(ctx context.Context, actorId int64, req models.CreateStudyRequest) (models.Study, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return models.Study{}, ErrStructureNameEmpty
+	}
+
+	var study models.Study
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		var err error
+		if study, err = s.repo.WithDB(tx).CreateStudy(ctx, name, req.InstituteId); err != nil {
+			return mapStructureError(err, ErrInstituteNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudyCreate, AuditTargetStudy, study.Id, nil, study)
+	})
+
+	return study, err
+}
+
+// UpdateStudy меняет название направления или переносит его в другой институт.
+func (s *InstitutesService) UpdateStudy(ctx context.Context, actorId, id int64, req models.UpdateStudyRequest) (models.Study, error) {
+	var study models.Study
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetStudy(ctx, id)
+		if err != nil {
+			return mapStructureError(err, ErrStudyNotFound)
+		}
+
+		updated := before
+		if name := strings.TrimSpace(req.Name); name != "" {
+			updated.Name = name
+		}
+		if req.InstituteId != 0 {
+			updated.InstituteId = req.InstituteId
+		}
+
+		if study, err = repo.UpdateStudy(ctx, updated); err != nil {
+			return mapStructureError(err, ErrInstituteNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudyUpdate, AuditTargetStudy, id, before, study)
+	})
+
+	return study, err
+}
+
+// DeleteStudy удаляет направление без групп.
+func (s *InstitutesService) DeleteStudy(ctx context.Context, actorId, id int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetStudy(ctx, id)
+		if err != nil {
+			return mapStructureError(err, ErrStudyNotFound)
+		}
+
+		deleted, err := repo.DeleteStudy(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			return ErrStructureNotEmpty
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudyDelete, AuditTargetStudy, id, before, nil)
+	})
+}
+
+// CreateGroup добавляет группу, StudyId = 0 - группа без направления.
+func (s *InstitutesService) CreateGroup(ctx context.Context, actorId int64, req models.CreateGroupRequest) (models.StudentGroup, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return models.StudentGroup{}, ErrStructureNameEmpty
+	}
+
+	var group models.StudentGroup
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		var err error
+		if group, err = s.repo.WithDB(tx).CreateGroup(ctx, name, req.StudyId); err != nil {
+			return mapStructureError(err, ErrStudyNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditGroupCreate, AuditTargetGroup, group.Id, nil, group)
+	})
+
+	return group, err
+}
+
+// UpdateGroup меняет название группы или переносит ее на другое направление.
+// Студенты и пользователи ссылаются на группу по id, поэтому переименование видно сразу везде.
+func (s *InstitutesService) UpdateGroup(ctx context.Context, actorId, id int64, req models.UpdateGroupRequest) (models.StudentGroup, error) {
+	var group models.StudentGroup
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetGroup(ctx, id)
+		if err != nil {
+			return mapStructureError(err, ErrStudentGroupNotFound)
+		}
+
+		updated := before
+		if name := strings.TrimSpace(req.Name); name != "" {
+			updated.Name = name
+		}
+		if req.StudyId != 0 {
+			updated.StudyId = req.StudyId
+		}
+
+		if group, err = repo.UpdateGroup(ctx, updated); err != nil {
+			return mapStructureError(err, ErrStudyNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditGroupUpdate, AuditTargetGroup, id, before, group)
+	})
+
+	return group, err
+}
+
+// DeleteGroup удаляет группу, в которой нет студентов и пользователей.
+func (s *InstitutesService) DeleteGroup(ctx context.Context, actorId, id int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		before, err := repo.GetGroup(ctx, id)
+		if err != nil {
+			return mapStructureError(err, ErrStudentGroupNotFound)
+		}
+
+		if err = repo.DeleteGroup(ctx, id); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrStudentGroupInUse
+			}
+			return mapStructureError(err, ErrStudentGroupNotFound)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditGroupDelete, AuditTargetGroup, id, before, nil)
+	})
+}
+
+// resolveGroup находит группу по id или, если id не задан, по названию.
+func resolveGroup(ctx context.Context, repo *repositories.InstitutesRepository, id int64, name string) (models.StudentGroup, error) {
+	var (
+		group models.StudentGroup
+		err   error
+	)
+	if id != 0 {
+		group, err = repo.GetGroup(ctx, id)
+	} else {
+		group, err = repo.GetGroupByName(ctx, strings.TrimSpace(name))
+	}
+	if err != nil {
+		return group, mapStructureError(err, ErrStudentGroupNotFound)
+	}
+
+	return group, nil
+}
+
+// mapStructureError переводит ошибки базы в ошибки сервиса: нет строки - notFound,
+// нарушение уникальности - ErrStructureNameTaken, нарушение внешнего ключа - родитель не найден (тоже notFound).
+func mapStructureError(err error, notFound error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return notFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrStructureNameTaken
+		case "23503":
+			return notFound
+		}
+	}
+
+	return err
+}
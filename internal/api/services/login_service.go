@@ -17,36 +17,130 @@ var (
 type LoginService struct {
 	userRepo      *repositories.UserRepository
 	tokenProvider *TokenProvider
+	throttle      *AuthThrottleService
+	mfa           *MFAService
 	uow           *repositories.UoW
 }
 
 func NewLoginService(
 	userRepo *repositories.UserRepository,
 	tokenProvider *TokenProvider,
+	throttle *AuthThrottleService,
+	mfa *MFAService,
 	uow *repositories.UoW,
 ) *LoginService {
 	return &LoginService{
 		userRepo:      userRepo,
 		tokenProvider: tokenProvider,
+		throttle:      throttle,
+		mfa:           mfa,
 		uow:           uow,
 	}
 }
 
-func (s *LoginService) Login(ctx context.Context, email string, password string) (models.LoginResponse, error) {
+func (s *LoginService) Login(ctx context.Context, email string, password string, meta models.SessionMeta) (models.LoginResponse, error) {
+	// проверяем, не заблокированы ли IP или аккаунт из-за перебора паролей
+	if err := s.throttle.Check(ctx, ThrottleScopeLogin, meta.IP, email); err != nil {
+		return models.LoginResponse{}, err
+	}
+
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil {
+		if err = s.throttle.RegisterAttempt(ctx, ThrottleScopeLogin, meta.IP, email); err != nil {
+			return models.LoginResponse{}, err
+		}
 		return models.LoginResponse{}, ErrUserNotFound
 	}
 
 	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(password)); err != nil {
+		if err = s.throttle.RegisterAttempt(ctx, ThrottleScopeLogin, meta.IP, email); err != nil {
+			return models.LoginResponse{}, err
+		}
 		return models.LoginResponse{}, ErrInvalidPassword
 	}
 
+	// для администраторов с включенной 2FA токены выдаются только после проверки кода,
+	// счетчик неудачных попыток сбрасывается тоже только после него (в CompleteMFA)
+	if MFARequired(user.RoleLevel) {
+		enabled, err := s.mfa.IsEnabled(ctx, user.Id)
+		if err != nil {
+			return models.LoginResponse{}, err
+		}
+		if enabled {
+			challenge, err := s.mfa.CreateChallenge(ctx, user.Id)
+			if err != nil {
+				return models.LoginResponse{}, err
+			}
+			return models.LoginResponse{
+				MFARequired:     true,
+				MFAToken:        challenge,
+				MFAExpiresInSec: int64(mfaChallengeTTL.Seconds()),
+			}, nil
+		}
+	}
+
+	// успешный вход сбрасывает счетчик неудачных попыток аккаунта
+	if err = s.throttle.Reset(ctx, ThrottleScopeLogin, email); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return s.issueTokens(ctx, user, meta)
+}
+
+// CompleteMFA завершает вход с двухфакторной аутентификацией: проверяет код для челленджа,
+// выданного Login, и выдает пару токенов. Неверные коды учитываются по аккаунту и IP
+// (ThrottleScopeMFA), поэтому новые челленджи не дают продолжить перебор.
+func (s *LoginService) CompleteMFA(ctx context.Context, challengeToken, code string, meta models.SessionMeta) (models.LoginResponse, error) {
+	if err := s.throttle.Check(ctx, ThrottleScopeMFA, meta.IP, ""); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	userId, err := s.mfa.ChallengeUser(ctx, challengeToken)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFAChallenge) {
+			if err := s.throttle.RegisterAttempt(ctx, ThrottleScopeMFA, meta.IP, ""); err != nil {
+				return models.LoginResponse{}, err
+			}
+		}
+		return models.LoginResponse{}, err
+	}
+
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		return models.LoginResponse{}, ErrUserNotFound
+	}
+
+	if err = s.throttle.Check(ctx, ThrottleScopeMFA, meta.IP, user.Email); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	if _, err = s.mfa.VerifyChallenge(ctx, challengeToken, code); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if err := s.throttle.RegisterAttempt(ctx, ThrottleScopeMFA, meta.IP, user.Email); err != nil {
+				return models.LoginResponse{}, err
+			}
+		}
+		return models.LoginResponse{}, err
+	}
+
+	// вход завершен: сбрасываются счетчики и пароля, и второго фактора
+	if err = s.throttle.Reset(ctx, ThrottleScopeLogin, user.Email); err != nil {
+		return models.LoginResponse{}, err
+	}
+	if err = s.throttle.Reset(ctx, ThrottleScopeMFA, user.Email); err != nil {
+		return models.LoginResponse{}, err
+	}
+
+	return s.issueTokens(ctx, user, meta)
+}
+
+// issueTokens открывает новую сессию пользователя и собирает ответ логина.
+func (s *LoginService) issueTokens(ctx context.Context, user models.User, meta models.SessionMeta) (models.LoginResponse, error) {
 	var result models.LoginResponse
 
-	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
 		// генерируем и сохраняем токены
-		tokens, err := s.tokenProvider.IssuePair(ctx, tx, user.Id, user.RoleLevel)
+		tokens, err := s.tokenProvider.IssuePair(ctx, tx, user.Id, user.RoleLevel, meta)
 		if err != nil {
 			return err
 		}
@@ -58,13 +152,14 @@ func (s *LoginService) Login(ctx context.Context, email string, password string)
 				ExpUnix:      tokens.ExpUnix,
 			},
 			UserSubstructure: models.UserSubstructure{
-				ID:           user.Id,
-				Email:        user.Email,
-				BookId:       user.BookId,
-				FirstName:    user.Name,
-				RoleLevel:    user.RoleLevel,
-				StudentGroup: user.StudentGroup,
-				Avatar:       user.Avatar,
+				ID:            user.Id,
+				Email:         user.Email,
+				BookId:        user.BookId,
+				FirstName:     user.Name,
+				RoleLevel:     user.RoleLevel,
+				StudentGroup:  user.StudentGroup,
+				Avatar:        user.Avatar,
+				EmailVerified: user.EmailVerified,
 			},
 		}
 
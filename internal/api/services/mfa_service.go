@@ -0,0 +1,346 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// MFARequiredRoleLevel - начиная с этой роли (администратор) вход требует второго фактора.
+	MFARequiredRoleLevel = 50
+
+	mfaIssuer               = "Beaver"
+	mfaChallengeTTL         = 5 * time.Minute
+	mfaChallengeMaxAttempts = 5
+	recoveryCodesCount      = 10
+)
+
+var (
+	ErrMFAAlreadyEnabled     = errors.New("двухфакторная аутентификация уже включена")
+	ErrMFANotEnabled         = errors.New("двухфакторная аутентификация не включена")
+	ErrMFASetupNotStarted    = errors.New("секрет для приложения-аутентификатора не запрошен")
+	ErrInvalidMFACode        = errors.New("неверный код подтверждения")
+	ErrInvalidMFAChallenge   = errors.New("челлендж не найден или истек")
+	ErrMFAEnrollmentRequired = errors.New("для этой роли необходимо включить двухфакторную аутентификацию")
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFARequired сообщает, нужен ли второй фактор пользователю с такой ролью.
+func MFARequired(roleLevel int64) bool {
+	return roleLevel >= MFARequiredRoleLevel
+}
+
+type MFAService struct {
+	mfaRepo     *repositories.MFARepository
+	userRepo    *repositories.UserRepository
+	refreshRepo *repositories.RefreshTokensRepository
+	uow         *repositories.UoW
+}
+
+func NewMFAService(
+	mfaRepo *repositories.MFARepository,
+	userRepo *repositories.UserRepository,
+	refreshRepo *repositories.RefreshTokensRepository,
+	uow *repositories.UoW,
+) *MFAService {
+	return &MFAService{
+		mfaRepo:     mfaRepo,
+		userRepo:    userRepo,
+		refreshRepo: refreshRepo,
+		uow:         uow,
+	}
+}
+
+// IsEnabled проверяет, включена ли у пользователя двухфакторная аутентификация.
+func (s *MFAService) IsEnabled(ctx context.Context, userId int64) (bool, error) {
+	return s.mfaRepo.IsTOTPEnabled(ctx, userId)
+}
+
+// GetStatus возвращает состояние двухфакторной аутентификации пользователя.
+func (s *MFAService) GetStatus(ctx context.Context, userId, roleLevel int64) (models.TwoFactorStatus, error) {
+	enabled, err := s.mfaRepo.IsTOTPEnabled(ctx, userId)
+	if err != nil {
+		return models.TwoFactorStatus{}, err
+	}
+
+	status := models.TwoFactorStatus{
+		Enabled:  enabled,
+		Required: MFARequired(roleLevel),
+	}
+
+	if enabled {
+		status.RecoveryCodesLeft, err = s.mfaRepo.CountRecoveryCodes(ctx, userId)
+		if err != nil {
+			return models.TwoFactorStatus{}, err
+		}
+	}
+
+	return status, nil
+}
+
+// Setup генерирует новый секрет и URI для QR кода. Двухфакторная аутентификация
+// включится только после подтверждения кодом из приложения (Enable).
+func (s *MFAService) Setup(ctx context.Context, userId int64) (models.TwoFactorSetupResponse, error) {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.TwoFactorSetupResponse{}, ErrUserNotFound
+		}
+		return models.TwoFactorSetupResponse{}, err
+	}
+
+	secret, err := helpers.GenerateTOTPSecret()
+	if err != nil {
+		return models.TwoFactorSetupResponse{}, err
+	}
+
+	tag, err := s.mfaRepo.UpsertPendingTOTP(ctx, userId, secret)
+	if err != nil {
+		return models.TwoFactorSetupResponse{}, err
+	}
+	if tag.RowsAffected() == 0 {
+		return models.TwoFactorSetupResponse{}, ErrMFAAlreadyEnabled
+	}
+
+	return models.TwoFactorSetupResponse{
+		Secret:          secret,
+		ProvisioningURI: helpers.TOTPProvisioningURI(mfaIssuer, user.Email, secret),
+	}, nil
+}
+
+// Enable подтверждает привязку кодом из приложения, выдает коды восстановления
+// и завершает остальные сессии пользователя, открытые только по паролю.
+func (s *MFAService) Enable(ctx context.Context, userId, currentSessionId int64, code string) (models.TwoFactorRecoveryCodesResponse, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	var invalidCode bool
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		totp, err := s.mfaRepo.WithDB(tx).GetTOTP(ctx, userId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrMFASetupNotStarted
+			}
+			return err
+		}
+		if totp.EnabledAt != nil {
+			return ErrMFAAlreadyEnabled
+		}
+
+		step, ok := helpers.ValidateTOTP(totp.Secret, code, time.Now())
+		if !ok {
+			invalidCode = true
+			return nil
+		}
+		if _, err = s.mfaRepo.WithDB(tx).UseTOTPStep(ctx, userId, step); err != nil {
+			return err
+		}
+
+		if err = s.mfaRepo.WithDB(tx).EnableTOTP(ctx, userId); err != nil {
+			return err
+		}
+		if err = s.mfaRepo.WithDB(tx).ReplaceRecoveryCodes(ctx, userId, hashes); err != nil {
+			return err
+		}
+
+		return s.refreshRepo.WithDB(tx).DeleteOtherSessions(ctx, userId, currentSessionId)
+	})
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+	if invalidCode {
+		return models.TwoFactorRecoveryCodesResponse{}, ErrInvalidMFACode
+	}
+
+	return models.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// Disable отключает двухфакторную аутентификацию. Требует пароль и действующий код.
+func (s *MFAService) Disable(ctx context.Context, userId int64, password, code string) error {
+	user, err := s.userRepo.GetUserById(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if err = bcrypt.CompareHashAndPassword(user.Password, []byte(password)); err != nil {
+		return ErrInvalidPassword
+	}
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		if err := s.verifyUserCode(ctx, tx, userId, code); err != nil {
+			return err
+		}
+		return s.mfaRepo.WithDB(tx).DeleteTOTP(ctx, userId)
+	})
+}
+
+// RegenerateRecoveryCodes выдает новый набор кодов восстановления взамен старого.
+func (s *MFAService) RegenerateRecoveryCodes(ctx context.Context, userId int64, code string) (models.TwoFactorRecoveryCodesResponse, error) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		if err := s.verifyUserCode(ctx, tx, userId, code); err != nil {
+			return err
+		}
+		return s.mfaRepo.WithDB(tx).ReplaceRecoveryCodes(ctx, userId, hashes)
+	})
+	if err != nil {
+		return models.TwoFactorRecoveryCodesResponse{}, err
+	}
+
+	return models.TwoFactorRecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// CreateChallenge выдает короткоживущий токен, который вместе с кодом обменивается на пару токенов.
+func (s *MFAService) CreateChallenge(ctx context.Context, userId int64) (string, error) {
+	rawToken, err := helpers.GenerateTokenRaw(32)
+	if err != nil {
+		return "", ErrTokenGenerationError
+	}
+
+	err = s.mfaRepo.CreateChallenge(ctx, helpers.HashToken(rawToken), userId, time.Now().Add(mfaChallengeTTL))
+	if err != nil {
+		return "", err
+	}
+
+	return rawToken, nil
+}
+
+// ChallengeUser возвращает ID пользователя, которому выдан челлендж, не проверяя код.
+func (s *MFAService) ChallengeUser(ctx context.Context, challengeToken string) (int64, error) {
+	challenge, err := s.mfaRepo.GetChallenge(ctx, helpers.HashToken(challengeToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrInvalidMFAChallenge
+		}
+		return 0, err
+	}
+
+	return challenge.UserId, nil
+}
+
+// VerifyChallenge проверяет код для челленджа и возвращает ID пользователя.
+// Неверные коды учитываются, после mfaChallengeMaxAttempts челлендж удаляется.
+func (s *MFAService) VerifyChallenge(ctx context.Context, challengeToken, code string) (int64, error) {
+	var (
+		userId    int64
+		verifyErr error
+	)
+
+	tokenHash := helpers.HashToken(challengeToken)
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		challenge, err := s.mfaRepo.WithDB(tx).GetChallengeForUpdate(ctx, tokenHash)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrInvalidMFAChallenge
+			}
+			return err
+		}
+
+		if challenge.Attempts >= mfaChallengeMaxAttempts {
+			verifyErr = ErrInvalidMFAChallenge
+			return s.mfaRepo.WithDB(tx).DeleteChallenge(ctx, tokenHash, challenge.UserId)
+		}
+
+		// счетчик неверных попыток должен сохраниться, поэтому транзакцию не откатываем
+		if err = s.verifyUserCode(ctx, tx, challenge.UserId, code); err != nil {
+			if errors.Is(err, ErrInvalidMFACode) {
+				verifyErr = err
+				return s.mfaRepo.WithDB(tx).IncrementChallengeAttempts(ctx, tokenHash)
+			}
+			return err
+		}
+
+		userId = challenge.UserId
+		return s.mfaRepo.WithDB(tx).DeleteChallenge(ctx, tokenHash, challenge.UserId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	if verifyErr != nil {
+		return 0, verifyErr
+	}
+
+	return userId, nil
+}
+
+// verifyUserCode проверяет TOTP код или одноразовый код восстановления включенной 2FA (внутри транзакции).
+func (s *MFAService) verifyUserCode(ctx context.Context, tx repositories.DBTX, userId int64, code string) error {
+	totp, err := s.mfaRepo.WithDB(tx).GetTOTP(ctx, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrMFANotEnabled
+		}
+		return err
+	}
+	if totp.EnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+
+	if step, ok := helpers.ValidateTOTP(totp.Secret, code, time.Now()); ok {
+		// код уже принимался - повторное использование
+		tag, err := s.mfaRepo.WithDB(tx).UseTOTPStep(ctx, userId, step)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrInvalidMFACode
+		}
+		return nil
+	}
+
+	tag, err := s.mfaRepo.WithDB(tx).UseRecoveryCode(ctx, userId, helpers.HashToken(normalizeRecoveryCode(code)))
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrInvalidMFACode
+	}
+
+	return nil
+}
+
+// generateRecoveryCodes генерирует коды восстановления вида abcd-efgh и их хеши для хранения.
+func generateRecoveryCodes() ([]string, [][]byte, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	hashes := make([][]byte, 0, recoveryCodesCount)
+
+	for i := 0; i < recoveryCodesCount; i++ {
+		b := make([]byte, 5)
+		if _, err := rand.Read(b); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(recoveryCodeEncoding.EncodeToString(b))
+
+		codes = append(codes, raw[:4]+"-"+raw[4:])
+		hashes = append(hashes, helpers.HashToken(raw))
+	}
+
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
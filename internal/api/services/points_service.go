@@ -0,0 +1,173 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrInvalidPointsAdjustment = errors.New("некорректное изменение баллов: delta не должна быть 0, комментарий обязателен (до 500 символов)")
+)
+
+// Размер страницы журнала баллов.
+const (
+	DefaultPointsHistoryLimit = 50
+	MaxPointsHistoryLimit     = 500
+)
+
+const maxPointsCommentLength = 500
+
+// PointsService - журнал баллов. Баллы меняются только новыми записями журнала,
+// сумма пользователя в user_points - кэш суммы журнала.
+type PointsService struct {
+	repo  *repositories.PointsRepository
+	users *repositories.UserRepository
+	audit *AuditService
+	uow   *repositories.UoW
+}
+
+func NewPointsService(
+	repo *repositories.PointsRepository,
+	users *repositories.UserRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *PointsService {
+	return &PointsService{
+		repo:  repo,
+		users: users,
+		audit: audit,
+		uow:   uow,
+	}
+}
+
+// GetHistory возвращает баланс пользователя и страницу его журнала баллов, новые записи первыми.
+func (s *PointsService) GetHistory(ctx context.Context, userId int64, limit, offset int) (models.PointsHistoryResponse, error) {
+	if limit <= 0 {
+		limit = DefaultPointsHistoryLimit
+	}
+	if limit > MaxPointsHistoryLimit {
+		limit = MaxPointsHistoryLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	if _, err := s.users.GetUserById(ctx, userId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.PointsHistoryResponse{}, ErrUserNotFound
+		}
+		return models.PointsHistoryResponse{}, err
+	}
+
+	total, err := s.repo.GetTotal(ctx, userId)
+	if err != nil {
+		return models.PointsHistoryResponse{}, err
+	}
+
+	transactions, err := s.repo.GetTransactions(ctx, userId, limit, offset)
+	if err != nil {
+		return models.PointsHistoryResponse{}, err
+	}
+
+	return models.PointsHistoryResponse{
+		TotalPoints:  total,
+		Transactions: transactions,
+	}, nil
+}
+
+// AdjustPoints вручную начисляет (delta > 0) или списывает (delta < 0) баллы пользователю.
+// Комментарий обязателен: он объясняет запись в журнале.
+func (s *PointsService) AdjustPoints(ctx context.Context, actorId, userId int64, req models.AdjustPointsRequest) (models.PointsTransaction, error) {
+	comment := strings.TrimSpace(req.Comment)
+	if req.Delta == 0 || comment == "" || utf8.RuneCountInString(comment) > maxPointsCommentLength {
+		return models.PointsTransaction{}, ErrInvalidPointsAdjustment
+	}
+
+	var result models.PointsTransaction
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		err := repo.AddTransaction(ctx, models.NewPointsTransaction{
+			UserId:  userId,
+			Delta:   req.Delta,
+			Reason:  models.PointsReasonManual,
+			ActorId: actorId,
+			Comment: comment,
+		})
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		transactions, err := repo.GetTransactions(ctx, userId, 1, 0)
+		if err != nil {
+			return err
+		}
+		result = transactions[0]
+
+		return s.audit.Record(ctx, tx, actorId, AuditPointsAdjust, AuditTargetUser, userId, nil, result)
+	})
+
+	return result, err
+}
+
+// Reconcile сверяет журнал с выполненными событиями и кэш сумм с журналом.
+// Баллы за событие по журналу должны совпадать с текущими баллами события, если оно засчитано, и быть 0, если нет.
+// Без dryRun каждое расхождение по событию закрывается записью reconcile, затем суммы в user_points
+// пересчитываются по журналу. Отчет содержит расхождения, найденные до исправления.
+// actorId = 0 - запуск из командной строки.
+func (s *PointsService) Reconcile(ctx context.Context, actorId int64, dryRun bool) (models.PointsReconcileReport, error) {
+	report := models.PointsReconcileReport{DryRun: dryRun}
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		var err error
+		if report.EventDrift, err = repo.GetEventDrift(ctx); err != nil {
+			return err
+		}
+		if report.TotalDrift, err = repo.GetTotalDrift(ctx); err != nil {
+			return err
+		}
+
+		if dryRun || (len(report.EventDrift) == 0 && len(report.TotalDrift) == 0) {
+			return nil
+		}
+
+		for _, d := range report.EventDrift {
+			err = repo.AddTransaction(ctx, models.NewPointsTransaction{
+				UserId:  d.UserId,
+				Delta:   int(d.Expected - d.Actual),
+				Reason:  models.PointsReasonReconcile,
+				EventId: d.EventId,
+				ActorId: actorId,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if _, err = repo.RecalculateTotals(ctx); err != nil {
+			return err
+		}
+		report.Applied = true
+
+		return s.audit.Record(ctx, tx, actorId, AuditPointsReconcile, AuditTargetPoints, "", nil, report)
+	})
+	if err != nil {
+		return models.PointsReconcileReport{}, err
+	}
+
+	return report, nil
+}
@@ -7,10 +7,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
 )
 
 var (
-	ErrNoTokensFound = errors.New("не удалось найти токен")
+	ErrNoTokensFound      = errors.New("не удалось найти токен")
+	ErrRefreshTokenReused = errors.New("refresh токен уже был использован, сессия отозвана")
 )
 
 type RefreshTokensService struct {
@@ -31,39 +35,63 @@ func NewRefreshTokensService(
 	}
 }
 
-func (s *RefreshTokensService) RefreshToken(ctx context.Context, refreshToken string) (models.RefreshTokenResponse, error) {
-	// проверяем токен
-	userId, err := s.refreshRepo.GetUserIdByRefreshToken(ctx, helpers.HashToken(refreshToken))
-	if err != nil {
-		return models.RefreshTokenResponse{}, fmt.Errorf("could not found user_id by refresh token: %w", err)
-	}
-	if userId == 0 {
-		return models.RefreshTokenResponse{}, ErrNoTokensFound
-	}
+// RefreshToken обменивает refresh токен на новую пару токенов.
+// Если предъявлен уже обменянный токен (его могли украсть), отзывается всё семейство
+// токенов этой сессии и возвращается ErrRefreshTokenReused.
+func (s *RefreshTokensService) RefreshToken(ctx context.Context, refreshToken string, meta models.SessionMeta) (models.RefreshTokenResponse, error) {
+	var (
+		tokens models.GetTokensResponse
+		parent models.RefreshSession
+		reused bool
+	)
 
-	// роль пользователя
-	roleLevel, err := s.refreshRepo.GetRoleLevelByUserId(ctx, userId)
-	if err != nil {
-		return models.RefreshTokenResponse{}, err
-	}
+	// транзакция через UoW
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		var err error
 
-	var tokens models.GetTokensResponse
+		// ищем токен и блокируем его, чтобы параллельные обмены шли по очереди
+		parent, err = s.refreshRepo.WithDB(tx).GetRefreshTokenForUpdate(ctx, helpers.HashToken(refreshToken))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNoTokensFound
+			}
+			return fmt.Errorf("could not found refresh token: %w", err)
+		}
+
+		// повторное использование обменянного токена - отзываем сессию целиком.
+		// Ошибку не возвращаем, чтобы отзыв был закоммичен.
+		if parent.RotatedAt != nil {
+			reused = true
+			_, err = s.refreshRepo.WithDB(tx).RevokeFamily(ctx, parent.FamilyId)
+			return err
+		}
+
+		if parent.RevokedAt != nil || !parent.ExpiresAt.After(time.Now()) {
+			return ErrNoTokensFound
+		}
+
+		// роль пользователя
+		roleLevel, err := s.refreshRepo.WithDB(tx).GetRoleLevelByUserId(ctx, parent.UserId)
+		if err != nil {
+			return err
+		}
 
-	// транзакция через UoW
-	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
 		// TokenProvider сам:
-		// - удаляет старые токены
-		// - генерирует новые
-		// - сохраняет обновленные значения
-		tokens, err = s.tokenProvider.IssuePair(ctx, tx, userId, roleLevel)
+		// - генерирует новые токены
+		// - помечает старый refresh token как обменянный
+		// - сохраняет новый refresh token в том же семействе
+		tokens, err = s.tokenProvider.RotatePair(ctx, tx, parent, roleLevel, meta)
 		return err
 	})
 	if err != nil {
 		return models.RefreshTokenResponse{}, err
 	}
+	if reused {
+		return models.RefreshTokenResponse{}, ErrRefreshTokenReused
+	}
 
 	return models.RefreshTokenResponse{
-		UserID: userId,
+		UserID: parent.UserId,
 		AuthTokens: models.AuthTokens{
 			AccessToken:  tokens.AccessToken,
 			RefreshToken: tokens.RefreshToken,
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestRefreshTokenReuseRevokesFamily(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+	meta := models.SessionMeta{IP: "127.0.0.1"}
+
+	f.createUser(t, 1001, "reuse@example.com", "password-1")
+	tokenA := f.loginUser(t, "reuse@example.com", "password-1", "laptop")
+
+	rotated, err := f.refresh.RefreshToken(ctx, tokenA, meta)
+	if err != nil {
+		t.Fatalf("first refresh failed: %v", err)
+	}
+	tokenB := rotated.RefreshToken
+
+	// повторное предъявление A - признак кражи токена
+	if _, err = f.refresh.RefreshToken(ctx, tokenA, meta); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reused token: got %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	// B выдан тем же семейством и тоже отозван
+	if _, err = f.refresh.RefreshToken(ctx, tokenB, meta); !errors.Is(err, ErrNoTokensFound) {
+		t.Fatalf("token from revoked family: got %v, want %v", err, ErrNoTokensFound)
+	}
+
+	var total, active int
+	err = f.pool.QueryRow(ctx,
+		`SELECT count(*), count(*) FILTER (WHERE revoked_at IS NULL)
+		 FROM refresh_tokens
+		 WHERE family_id = (SELECT family_id FROM refresh_tokens WHERE token_hash = $1)`,
+		helpers.HashToken(tokenA),
+	).Scan(&total, &active)
+	if err != nil {
+		t.Fatalf("could not count family tokens: %v", err)
+	}
+	if total != 2 || active != 0 {
+		t.Fatalf("family tokens: total %d, not revoked %d; want 2 and 0", total, active)
+	}
+}
+
+func TestRefreshTokenConcurrentRefresh(t *testing.T) {
+	f := newAuthFixture(t)
+	ctx := context.Background()
+
+	f.createUser(t, 1002, "race@example.com", "password-1")
+	token := f.loginUser(t, "race@example.com", "password-1", "laptop")
+
+	const attempts = 2
+
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, attempts)
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			_, errs[i] = f.refresh.RefreshToken(ctx, token, models.SessionMeta{IP: "127.0.0.1"})
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, ErrRefreshTokenReused):
+			t.Errorf("unexpected refresh error: %v", err)
+		}
+	}
+	if succeeded != 1 {
+		t.Fatalf("successful refreshes: got %d, want 1 (errors: %v)", succeeded, errs)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"bobri/pkg/helpers"
 	"context"
 	"errors"
+	"log"
 
 	"golang.org/x/crypto/bcrypt"
 )
@@ -20,6 +21,7 @@ type RegisterService struct {
 	userRepo      *repositories.UserRepository
 	authRepo      *repositories.StudentsRepository
 	tokenProvider *TokenProvider
+	verification  *EmailVerificationService
 	uow           *repositories.UoW
 }
 
@@ -27,17 +29,19 @@ func NewRegisterService(
 	userRepo *repositories.UserRepository,
 	authRepo *repositories.StudentsRepository,
 	tokenProvider *TokenProvider,
+	verification *EmailVerificationService,
 	uow *repositories.UoW,
 ) *RegisterService {
 	return &RegisterService{
 		userRepo:      userRepo,
 		authRepo:      authRepo,
 		tokenProvider: tokenProvider,
+		verification:  verification,
 		uow:           uow,
 	}
 }
 
-func (s *RegisterService) RegisterUser(ctx context.Context, email, password, token string) (models.RegisterResponse, error) {
+func (s *RegisterService) RegisterUser(ctx context.Context, email, password, token string, meta models.SessionMeta) (models.RegisterResponse, error) {
 	// проверяем, есть ли пользователь с такой почтой
 	exists, err := s.userRepo.CheckUserWithEmailExists(ctx, email)
 	if err != nil {
@@ -48,6 +52,7 @@ func (s *RegisterService) RegisterUser(ctx context.Context, email, password, tok
 	}
 
 	var result models.RegisterResponse
+	var verificationToken string
 
 	// выполняем атомарную логику через UoW
 	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
@@ -73,15 +78,16 @@ func (s *RegisterService) RegisterUser(ctx context.Context, email, password, tok
 		}
 
 		user := models.User{
-			BookId:       student.BookId,
-			Name:         student.Name,
-			Surname:      student.Surname,
-			MiddleName:   student.MiddleName,
-			BirthDate:    student.BirthDate,
-			StudentGroup: student.StudentGroup,
-			Password:     hashed,
-			Email:        email,
-			RoleLevel:    10,
+			BookId:         student.BookId,
+			Name:           student.Name,
+			Surname:        student.Surname,
+			MiddleName:     student.MiddleName,
+			BirthDate:      student.BirthDate,
+			StudentGroupId: student.StudentGroupId,
+			StudentGroup:   student.StudentGroup,
+			Password:       hashed,
+			Email:          email,
+			RoleLevel:      10,
 		}
 
 		// создаем пользователя
@@ -91,7 +97,13 @@ func (s *RegisterService) RegisterUser(ctx context.Context, email, password, tok
 		}
 
 		// генерируем пару токенов
-		tokens, err := s.tokenProvider.IssuePair(ctx, tx, userId, user.RoleLevel)
+		tokens, err := s.tokenProvider.IssuePair(ctx, tx, userId, user.RoleLevel, meta)
+		if err != nil {
+			return err
+		}
+
+		// почта считается неподтвержденной, пока пользователь не введет токен из письма
+		verificationToken, err = s.verification.IssueToken(ctx, tx, userId, email)
 		if err != nil {
 			return err
 		}
@@ -114,6 +126,15 @@ func (s *RegisterService) RegisterUser(ctx context.Context, email, password, tok
 
 		return nil
 	})
+	if err != nil {
+		return models.RegisterResponse{}, err
+	}
+
+	// письмо отправляем после коммита: если отправка не удалась,
+	// пользователь уже зарегистрирован и может запросить письмо повторно
+	if err = s.verification.SendToken(email, verificationToken); err != nil {
+		log.Printf("could not send verification email to user %d: %v", result.ID, err)
+	}
 
-	return result, err
+	return result, nil
 }
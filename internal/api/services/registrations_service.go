@@ -0,0 +1,302 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrRegistrationNotOpen  = errors.New("запись на событие еще не открыта")
+	ErrRegistrationClosed   = errors.New("запись на событие закрыта")
+	ErrAlreadyRegistered    = errors.New("пользователь уже записан на событие")
+	ErrNotRegistered        = errors.New("пользователь не записан на событие")
+	ErrAttendanceMarked     = errors.New("посещение уже отмечено, отменить запись нельзя")
+	ErrInvalidAttendance    = errors.New("укажите user_ids или all")
+	ErrInvalidRegistration  = errors.New("некорректные настройки записи: мест должно быть больше 0, закрытие записи позже открытия")
+	errRegistrationNotFound = errors.New("registration not found")
+)
+
+// RegistrationsService - запись студентов на события с ограничением мест и листом ожидания.
+// Изменения записей одного события идут под блокировкой строки события, поэтому
+// одновременные записи не занимают больше мест, чем есть, а освободившееся место
+// достается первому из листа ожидания.
+type RegistrationsService struct {
+	repo          *repositories.RegistrationsRepository
+	events        *repositories.EventRepository
+	completedRepo *repositories.CompletedEventsRepository
+	completed     *CompletedEventsService
+	audit         *AuditService
+	uow           *repositories.UoW
+}
+
+func NewRegistrationsService(
+	repo *repositories.RegistrationsRepository,
+	events *repositories.EventRepository,
+	completedRepo *repositories.CompletedEventsRepository,
+	completed *CompletedEventsService,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *RegistrationsService {
+	return &RegistrationsService{
+		repo:          repo,
+		events:        events,
+		completedRepo: completedRepo,
+		completed:     completed,
+		audit:         audit,
+		uow:           uow,
+	}
+}
+
+// Register записывает пользователя на событие. Если мест нет, пользователь попадает в лист ожидания.
+// Записаться можно только на опубликованное событие.
+func (s *RegistrationsService) Register(ctx context.Context, userId, eventId int64) (models.EventRegistration, error) {
+	var result models.EventRegistration
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		settings, err := repo.LockEvent(ctx, eventId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		switch settings.Status {
+		case models.EventStatusDraft:
+			return ErrEventNotFound
+		case models.EventStatusArchived:
+			return ErrRegistrationClosed
+		}
+
+		now := time.Now()
+		if settings.RegistrationOpensAt != nil && now.Before(*settings.RegistrationOpensAt) {
+			return ErrRegistrationNotOpen
+		}
+		if settings.RegistrationClosesAt != nil && !now.Before(*settings.RegistrationClosesAt) {
+			return ErrRegistrationClosed
+		}
+
+		done, err := s.completedRepo.WithDB(tx).IsCompleted(ctx, userId, eventId)
+		if err != nil {
+			return err
+		}
+		if done {
+			return ErrAlreadyCompleted
+		}
+
+		registered, err := repo.CountRegistered(ctx, eventId)
+		if err != nil {
+			return err
+		}
+
+		status := models.RegistrationStatusRegistered
+		if settings.Capacity != nil && registered >= *settings.Capacity {
+			status = models.RegistrationStatusWaitlisted
+		}
+
+		if err = repo.CreateRegistration(ctx, eventId, userId, status); err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return ErrAlreadyRegistered
+				case "23503":
+					return ErrUserNotFound
+				}
+			}
+			return err
+		}
+
+		result, err = s.registration(ctx, repo, userId, eventId, settings.RegistrationSettings)
+		return err
+	})
+
+	return result, err
+}
+
+// Unregister отменяет запись пользователя. Освободившееся место получает первый из листа ожидания.
+func (s *RegistrationsService) Unregister(ctx context.Context, userId, eventId int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		if _, err := repo.LockEvent(ctx, eventId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		reg, err := repo.GetRegistration(ctx, eventId, userId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotRegistered
+			}
+			return err
+		}
+		if reg.AttendedAt != nil {
+			return ErrAttendanceMarked
+		}
+
+		if _, err = repo.DeleteRegistration(ctx, eventId, userId); err != nil {
+			return err
+		}
+
+		if reg.Status == models.RegistrationStatusRegistered {
+			_, err = repo.PromoteWaitlist(ctx, eventId)
+		}
+		return err
+	})
+}
+
+// GetRegistration возвращает запись пользователя на событие.
+func (s *RegistrationsService) GetRegistration(ctx context.Context, userId, eventId int64) (models.EventRegistration, error) {
+	event, err := s.events.GetEventById(ctx, eventId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EventRegistration{}, ErrEventNotFound
+		}
+		return models.EventRegistration{}, err
+	}
+	if event.DeletedAt != nil {
+		return models.EventRegistration{}, ErrEventNotFound
+	}
+
+	result, err := s.registration(ctx, s.repo, userId, eventId, event.RegistrationSettings)
+	if errors.Is(err, errRegistrationNotFound) {
+		return result, ErrNotRegistered
+	}
+	return result, err
+}
+
+// GetAttendees возвращает список записавшихся на событие вместе с листом ожидания.
+func (s *RegistrationsService) GetAttendees(ctx context.Context, eventId int64) ([]models.Attendee, error) {
+	if _, err := s.events.GetEventById(ctx, eventId); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrEventNotFound
+		}
+		return nil, err
+	}
+
+	return s.repo.GetAttendees(ctx, eventId)
+}
+
+// MarkAttendance отмечает посещение и засчитывает событие пришедшим одним действием:
+// баллы начисляются через CompletedEventsService в той же транзакции. Пользователи из листа ожидания
+// и не записанные пропускаются, уже выполнившие событие повторно баллы не получают.
+func (s *RegistrationsService) MarkAttendance(ctx context.Context, actorId, eventId int64, req models.MarkAttendanceRequest) (models.MarkAttendanceResponse, error) {
+	if !req.All && len(req.UserIds) == 0 {
+		return models.MarkAttendanceResponse{}, ErrInvalidAttendance
+	}
+
+	result := models.MarkAttendanceResponse{
+		Completed:        []int64{},
+		AlreadyCompleted: []int64{},
+		NotRegistered:    []int64{},
+	}
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		repo := s.repo.WithDB(tx)
+
+		if _, err := repo.LockEvent(ctx, eventId); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrEventNotFound
+			}
+			return err
+		}
+
+		userIds := req.UserIds
+		if req.All {
+			var err error
+			if userIds, err = repo.GetRegisteredUserIds(ctx, eventId); err != nil {
+				return err
+			}
+		}
+		userIds = slices.Clone(userIds)
+		slices.Sort(userIds)
+		userIds = slices.Compact(userIds)
+
+		marked, err := repo.MarkAttended(ctx, eventId, userIds)
+		if err != nil {
+			return err
+		}
+
+		for _, userId := range userIds {
+			if !slices.Contains(marked, userId) {
+				result.NotRegistered = append(result.NotRegistered, userId)
+				continue
+			}
+
+			// проверка заранее: нарушение уникальности прервало бы всю транзакцию
+			done, err := s.completedRepo.WithDB(tx).IsCompleted(ctx, userId, eventId)
+			if err != nil {
+				return err
+			}
+			if done {
+				result.AlreadyCompleted = append(result.AlreadyCompleted, userId)
+				continue
+			}
+
+			if err = s.completed.AddCompletedEventTx(ctx, tx, actorId, userId, eventId); err != nil {
+				return err
+			}
+			result.Completed = append(result.Completed, userId)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditEventAttendance, AuditTargetEvent, eventId, nil, result)
+	})
+
+	return result, err
+}
+
+// registration собирает ответ о записи пользователя: статус, место в листе ожидания и занятость мест.
+func (s *RegistrationsService) registration(ctx context.Context, repo *repositories.RegistrationsRepository, userId, eventId int64, settings models.RegistrationSettings) (models.EventRegistration, error) {
+	reg, err := repo.GetRegistration(ctx, eventId, userId)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EventRegistration{}, errRegistrationNotFound
+		}
+		return models.EventRegistration{}, err
+	}
+
+	registered, err := repo.CountRegistered(ctx, eventId)
+	if err != nil {
+		return models.EventRegistration{}, err
+	}
+
+	result := models.EventRegistration{
+		EventId:         eventId,
+		Status:          reg.Status,
+		RegisteredCount: registered,
+		Capacity:        settings.Capacity,
+		CreatedAt:       reg.CreatedAt,
+		AttendedAt:      reg.AttendedAt,
+	}
+	if reg.Status == models.RegistrationStatusWaitlisted {
+		if result.WaitlistPosition, err = repo.WaitlistPosition(ctx, eventId, userId); err != nil {
+			return models.EventRegistration{}, err
+		}
+	}
+
+	return result, nil
+}
+
+// validateRegistrationSettings проверяет число мест и окно записи.
+// Capacity 0 допустим только при изменении события - он снимает ограничение.
+func validateRegistrationSettings(capacity *int, allowZero bool, opensAt, closesAt *time.Time) error {
+	if capacity != nil && (*capacity < 0 || (*capacity == 0 && !allowZero)) {
+		return ErrInvalidRegistration
+	}
+	if opensAt != nil && closesAt != nil && !closesAt.After(*opensAt) {
+		return ErrInvalidRegistration
+	}
+	return nil
+}
@@ -5,7 +5,6 @@ import (
 	"bobri/pkg/helpers"
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -21,6 +20,8 @@ type ResetPasswordService struct {
 	resetRepo     *repositories.ResetPasswordRepository
 	userRepo      *repositories.UserRepository
 	emailProvider *EmailProvider
+	throttle      *AuthThrottleService
+	verification  *EmailVerificationService
 	uow           *repositories.UoW
 }
 
@@ -28,22 +29,39 @@ func NewResetPasswordService(
 	resetRepo *repositories.ResetPasswordRepository,
 	userRepo *repositories.UserRepository,
 	emailProvider *EmailProvider,
+	throttle *AuthThrottleService,
+	verification *EmailVerificationService,
 	uow *repositories.UoW,
 ) *ResetPasswordService {
 	return &ResetPasswordService{
 		resetRepo:     resetRepo,
 		userRepo:      userRepo,
 		emailProvider: emailProvider,
+		throttle:      throttle,
+		verification:  verification,
 		uow:           uow,
 	}
 }
 
-func (s *ResetPasswordService) ResetPassword(ctx context.Context, email string) error {
+func (s *ResetPasswordService) ResetPassword(ctx context.Context, email, ip string) error {
+	// каждый запрос учитывается, чтобы нельзя было перебирать почты и заваливать их письмами
+	if err := s.throttle.Check(ctx, ThrottleScopeResetPassword, ip, email); err != nil {
+		return err
+	}
+	if err := s.throttle.RegisterAttempt(ctx, ThrottleScopeResetPassword, ip, email); err != nil {
+		return err
+	}
+
 	userId, err := s.resetRepo.GetUserIdByEmail(ctx, email)
 	if err != nil {
 		return ErrUserNotFound
 	}
 
+	// письмо со сбросом пароля уходит только на почту, владение которой подтверждено (если этого требует политика)
+	if err = s.verification.CheckAccess(ctx, userId, VerificationAreaPasswordReset); err != nil {
+		return err
+	}
+
 	rawToken, err := helpers.GenerateTokenRaw(32)
 	if err != nil {
 		return errors.New("ошибка генерации токена")
@@ -60,7 +78,6 @@ func (s *ResetPasswordService) ResetPassword(ctx context.Context, email string)
 		return err
 	}
 
-	fmt.Println("token:", rawToken)
 	return s.emailProvider.SendResetPassword(email, rawToken)
 
 }
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrForbidden          = errors.New("недостаточно прав")
+	ErrRoleNotFound       = errors.New("такой роли не существует")
+	ErrRoleAlreadyExists  = errors.New("роль с таким кодом или уровнем уже существует")
+	ErrUnknownPermission  = errors.New("неизвестное право")
+	ErrInvalidRoleLevel   = errors.New("уровень роли должен быть положительным")
+	ErrPermissionEscalate = errors.New("нельзя выдать право, которого нет у вас")
+)
+
+type RolesService struct {
+	permissionsRepo *repositories.PermissionsRepository
+	userRepo        *repositories.UserRepository
+	audit           *AuditService
+	uow             *repositories.UoW
+}
+
+func NewRolesService(
+	permissionsRepo *repositories.PermissionsRepository,
+	userRepo *repositories.UserRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *RolesService {
+	return &RolesService{
+		permissionsRepo: permissionsRepo,
+		userRepo:        userRepo,
+		audit:           audit,
+		uow:             uow,
+	}
+}
+
+func (s *RolesService) GetPermissions(ctx context.Context) ([]models.Permission, error) {
+	return s.permissionsRepo.GetPermissions(ctx)
+}
+
+func (s *RolesService) GetRoles(ctx context.Context) ([]models.Role, error) {
+	return s.permissionsRepo.GetRoles(ctx)
+}
+
+// CreateRole создает роль ниже уровня actor с набором прав, которые есть у самого actor.
+func (s *RolesService) CreateRole(ctx context.Context, actor *models.Payload, req models.CreateRoleRequest) (models.Role, error) {
+	if req.Level <= 0 {
+		return models.Role{}, ErrInvalidRoleLevel
+	}
+	if req.Level >= actor.RoleLevel {
+		return models.Role{}, ErrForbidden
+	}
+	if err := s.checkGrantable(ctx, actor, req.Permissions); err != nil {
+		return models.Role{}, err
+	}
+
+	role := models.Role{
+		Code:        req.Code,
+		Name:        req.Name,
+		Level:       req.Level,
+		Permissions: req.Permissions,
+	}
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		id, err := s.permissionsRepo.WithDB(tx).CreateRole(ctx, role)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrRoleAlreadyExists
+			}
+			return err
+		}
+		role.Id = id
+
+		if err = s.permissionsRepo.WithDB(tx).SetRolePermissions(ctx, role.Level, role.Permissions); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actor.Sub, AuditRoleCreate, AuditTargetRole, role.Level, nil, role)
+	})
+	if err != nil {
+		return models.Role{}, err
+	}
+
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+
+	return role, nil
+}
+
+// SetRolePermissions заменяет права роли ниже уровня actor. Выданные пользователям роли
+// access токены отзываются, чтобы изменения применились сразу.
+func (s *RolesService) SetRolePermissions(ctx context.Context, actor *models.Payload, roleLevel int64, permissions []string) error {
+	if roleLevel >= actor.RoleLevel {
+		return ErrForbidden
+	}
+
+	exists, err := s.userRepo.RoleExists(ctx, roleLevel)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrRoleNotFound
+	}
+
+	if err = s.checkGrantable(ctx, actor, permissions); err != nil {
+		return err
+	}
+
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		before, err := s.permissionsRepo.WithDB(tx).GetPermissionsByRoleLevel(ctx, roleLevel)
+		if err != nil {
+			return err
+		}
+
+		if err = s.permissionsRepo.WithDB(tx).SetRolePermissions(ctx, roleLevel, permissions); err != nil {
+			return err
+		}
+
+		if err = s.permissionsRepo.WithDB(tx).RevokeRoleTokens(ctx, roleLevel); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actor.Sub, AuditRolePermissionsSet, AuditTargetRole, roleLevel,
+			map[string]any{"permissions": before},
+			map[string]any{"permissions": permissions},
+		)
+	})
+}
+
+// checkGrantable проверяет, что все права существуют и есть у самого actor.
+func (s *RolesService) checkGrantable(ctx context.Context, actor *models.Payload, permissions []string) error {
+	known, err := s.permissionsRepo.GetPermissions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, code := range permissions {
+		if !slices.ContainsFunc(known, func(p models.Permission) bool { return p.Code == code }) {
+			return ErrUnknownPermission
+		}
+		if !actor.HasPermission(code) {
+			return ErrPermissionEscalate
+		}
+	}
+
+	return nil
+}
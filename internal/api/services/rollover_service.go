@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidRollover = errors.New("некорректная таблица перевода групп")
+)
+
+// errRolloverDryRun откатывает транзакцию пробного запуска, чтобы отчет содержал точные количества.
+var errRolloverDryRun = errors.New("rollover dry run")
+
+// RolloverService - переход на новый учебный год: перевод групп и выпуск студентов.
+type RolloverService struct {
+	institutesRepo *repositories.InstitutesRepository
+	studentsRepo   *repositories.StudentsRepository
+	userRepo       *repositories.UserRepository
+	audit          *AuditService
+	uow            *repositories.UoW
+}
+
+func NewRolloverService(
+	institutesRepo *repositories.InstitutesRepository,
+	studentsRepo *repositories.StudentsRepository,
+	userRepo *repositories.UserRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *RolloverService {
+	return &RolloverService{
+		institutesRepo: institutesRepo,
+		studentsRepo:   studentsRepo,
+		userRepo:       userRepo,
+		audit:          audit,
+		uow:            uow,
+	}
+}
+
+// Rollover сначала выпускает группы GraduatingGroups (студенты и пользователи получают archived_at),
+// затем переводит остальных по таблице Mappings. Поэтому выпускающаяся группа может в той же операции
+// принять следующий курс: {"graduating_groups": ["ИВТ-41"], "mappings": [{"from": "ИВТ-31", "to": "ИВТ-41"}]}.
+// Группа To, которой еще нет, создается на направлении группы From.
+// Все выполняется в одной транзакции; при dryRun транзакция откатывается, а отчет возвращается.
+// actorId = 0 - запуск из командной строки.
+func (s *RolloverService) Rollover(ctx context.Context, actorId int64, req models.RolloverRequest, dryRun bool) (models.RolloverReport, error) {
+	mappings, graduating, err := normalizeRollover(req)
+	if err != nil {
+		return models.RolloverReport{}, err
+	}
+
+	report := models.RolloverReport{
+		DryRun:   dryRun,
+		Mappings: make([]models.RolloverMappingResult, 0, len(mappings)),
+		Graduation: models.RolloverGraduation{
+			Groups: graduating,
+		},
+	}
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		institutesRepo := s.institutesRepo.WithDB(tx)
+		studentsRepo := s.studentsRepo.WithDB(tx)
+		userRepo := s.userRepo.WithDB(tx)
+
+		names := append([]string{}, graduating...)
+		for _, m := range mappings {
+			names = append(names, m.From, m.To)
+		}
+		groups, err := institutesRepo.GetGroupsByNames(ctx, names)
+		if err != nil {
+			return err
+		}
+		byName := make(map[string]models.StudentGroup, len(groups))
+		for _, g := range groups {
+			byName[g.Name] = g
+		}
+
+		// выпуск
+		graduatingIds := make([]int64, 0, len(graduating))
+		for _, name := range graduating {
+			g, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("%w: выпускающаяся группа %q не найдена", ErrStudentGroupNotFound, name)
+			}
+			graduatingIds = append(graduatingIds, g.Id)
+		}
+		if len(graduatingIds) > 0 {
+			now := time.Now()
+			if report.Graduation.Students, err = studentsRepo.ArchiveGroups(ctx, graduatingIds, now); err != nil {
+				return err
+			}
+			if report.Graduation.Users, err = userRepo.ArchiveGroups(ctx, graduatingIds, now); err != nil {
+				return err
+			}
+		}
+
+		// перевод
+		fromIds := make([]int64, 0, len(mappings))
+		toIds := make([]int64, 0, len(mappings))
+		for _, m := range mappings {
+			from, ok := byName[m.From]
+			if !ok {
+				return fmt.Errorf("%w: группа %q не найдена", ErrStudentGroupNotFound, m.From)
+			}
+
+			result := models.RolloverMappingResult{From: m.From, To: m.To}
+			to, ok := byName[m.To]
+			if !ok {
+				if to, err = institutesRepo.CreateGroup(ctx, m.To, from.StudyId); err != nil {
+					return err
+				}
+				byName[m.To] = to
+				result.CreatedGroup = true
+			}
+
+			fromIds = append(fromIds, from.Id)
+			toIds = append(toIds, to.Id)
+			report.Mappings = append(report.Mappings, result)
+		}
+		if len(fromIds) > 0 {
+			students, err := studentsRepo.ApplyGroupMapping(ctx, fromIds, toIds)
+			if err != nil {
+				return err
+			}
+			users, err := userRepo.ApplyGroupMapping(ctx, fromIds, toIds)
+			if err != nil {
+				return err
+			}
+			for i, id := range fromIds {
+				report.Mappings[i].Students = students[id]
+				report.Mappings[i].Users = users[id]
+			}
+		}
+
+		if dryRun {
+			return errRolloverDryRun
+		}
+		report.Applied = true
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudentsRollover, AuditTargetStudent, time.Now().Format("2006-01-02"), nil, report)
+	})
+	if err != nil && !errors.Is(err, errRolloverDryRun) {
+		return models.RolloverReport{}, err
+	}
+
+	return report, nil
+}
+
+// normalizeRollover убирает лишние пробелы и проверяет, что таблица перевода однозначна.
+func normalizeRollover(req models.RolloverRequest) ([]models.GroupMapping, []string, error) {
+	clean := func(name string) string {
+		return strings.Join(strings.Fields(name), " ")
+	}
+
+	if len(req.Mappings) == 0 && len(req.GraduatingGroups) == 0 {
+		return nil, nil, fmt.Errorf("%w: нет ни перевода групп, ни выпуска", ErrInvalidRollover)
+	}
+
+	graduating := make([]string, 0, len(req.GraduatingGroups))
+	seenGraduating := make(map[string]bool, len(req.GraduatingGroups))
+	for _, name := range req.GraduatingGroups {
+		name = clean(name)
+		if name == "" {
+			return nil, nil, fmt.Errorf("%w: пустое название выпускающейся группы", ErrInvalidRollover)
+		}
+		if seenGraduating[name] {
+			return nil, nil, fmt.Errorf("%w: группа %q указана в выпуске дважды", ErrInvalidRollover, name)
+		}
+		seenGraduating[name] = true
+		graduating = append(graduating, name)
+	}
+
+	mappings := make([]models.GroupMapping, 0, len(req.Mappings))
+	seenFrom := make(map[string]bool, len(req.Mappings))
+	for _, m := range req.Mappings {
+		m.From, m.To = clean(m.From), clean(m.To)
+		switch {
+		case m.From == "" || m.To == "":
+			return nil, nil, fmt.Errorf("%w: пустое название группы", ErrInvalidRollover)
+		case m.From == m.To:
+			return nil, nil, fmt.Errorf("%w: группа %q переводится сама в себя", ErrInvalidRollover, m.From)
+		case seenFrom[m.From]:
+			return nil, nil, fmt.Errorf("%w: группа %q переводится дважды", ErrInvalidRollover, m.From)
+		case seenGraduating[m.From]:
+			return nil, nil, fmt.Errorf("%w: группа %q одновременно выпускается и переводится", ErrInvalidRollover, m.From)
+		}
+		seenFrom[m.From] = true
+		mappings = append(mappings, m)
+	}
+
+	return mappings, graduating, nil
+}
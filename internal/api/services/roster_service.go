@@ -0,0 +1,454 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"bobri/pkg/helpers"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrRosterMissingColumns = errors.New("в файле нет обязательных колонок")
+	ErrRosterTooManyRows    = errors.New("слишком много строк в файле")
+	ErrRosterHasErrors      = errors.New("в файле есть ошибки или конфликты, изменения не применены")
+)
+
+// rosterMaxRows - максимальное количество студентов в одном файле.
+const rosterMaxRows = 10000
+
+// Колонки списка студентов и их возможные заголовки.
+const (
+	rosterColBookId     = "book_id"
+	rosterColSurname    = "surname"
+	rosterColName       = "name"
+	rosterColMiddleName = "middle_name"
+	rosterColFIO        = "fio"
+	rosterColBirthDate  = "birth_date"
+	rosterColGroup      = "student_group"
+)
+
+// rosterHeaders - допустимые заголовки колонок (в нижнем регистре, "ё" заменяется на "е").
+var rosterHeaders = map[string]string{
+	"book_id": rosterColBookId,
+	"номер зачетной книжки":      rosterColBookId,
+	"номер зачетки":              rosterColBookId,
+	"зачетная книжка":            rosterColBookId,
+	"номер студенческого билета": rosterColBookId,
+	"surname":       rosterColSurname,
+	"фамилия":       rosterColSurname,
+	"name":          rosterColName,
+	"имя":           rosterColName,
+	"middle_name":   rosterColMiddleName,
+	"отчество":      rosterColMiddleName,
+	"fio":           rosterColFIO,
+	"фио":           rosterColFIO,
+	"birth_date":    rosterColBirthDate,
+	"дата рождения": rosterColBirthDate,
+	"group":         rosterColGroup,
+	"student_group": rosterColGroup,
+	"группа":        rosterColGroup,
+}
+
+var rosterDateLayouts = []string{"2006-01-02", "02.01.2006", "2.1.2006", "02/01/2006", "2006.01.02"}
+
+// RosterService - загрузка списка студентов (реестра students) из CSV и XLSX.
+type RosterService struct {
+	studentsRepo   *repositories.StudentsRepository
+	userRepo       *repositories.UserRepository
+	institutesRepo *repositories.InstitutesRepository
+	audit          *AuditService
+	uow            *repositories.UoW
+}
+
+func NewRosterService(
+	studentsRepo *repositories.StudentsRepository,
+	userRepo *repositories.UserRepository,
+	institutesRepo *repositories.InstitutesRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *RosterService {
+	return &RosterService{
+		studentsRepo:   studentsRepo,
+		userRepo:       userRepo,
+		institutesRepo: institutesRepo,
+		audit:          audit,
+		uow:            uow,
+	}
+}
+
+// Import разбирает файл со списком студентов и сравнивает каждую строку с реестром.
+// При dryRun только возвращает отчет. Иначе применяет все добавления и изменения в одной транзакции;
+// если хотя бы одна строка невалидна или конфликтует, ничего не применяется и возвращается ErrRosterHasErrors
+// вместе с отчетом.
+func (s *RosterService) Import(ctx context.Context, actorId int64, filename string, data []byte, dryRun bool) (models.RosterImportReport, error) {
+	// заголовок и не больше rosterMaxRows студентов
+	table, err := helpers.ReadSpreadsheet(filename, data, rosterMaxRows+1)
+	if err != nil {
+		return models.RosterImportReport{}, err
+	}
+	if len(table)-1 > rosterMaxRows {
+		return models.RosterImportReport{}, ErrRosterTooManyRows
+	}
+
+	columns, err := rosterColumns(table[0])
+	if err != nil {
+		return models.RosterImportReport{}, err
+	}
+
+	rows := make([]models.RosterRow, 0, len(table)-1)
+	for i, cells := range table[1:] {
+		if isBlankRow(cells) {
+			continue
+		}
+		rows = append(rows, parseRosterRow(i+2, cells, columns))
+	}
+
+	report := models.RosterImportReport{DryRun: dryRun}
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		studentsRepo := s.studentsRepo.WithDB(tx)
+
+		if err := s.resolveGroups(ctx, s.institutesRepo.WithDB(tx), rows); err != nil {
+			return err
+		}
+		if err := s.classify(ctx, studentsRepo, rows); err != nil {
+			return err
+		}
+
+		report.Rows = rows
+		report.Summary = summarizeRoster(rows)
+
+		if dryRun {
+			return nil
+		}
+		if report.Summary.Conflicts > 0 || report.Summary.Invalid > 0 {
+			return ErrRosterHasErrors
+		}
+
+		for _, row := range rows {
+			switch row.Status {
+			case models.RosterRowInsert:
+				err = studentsRepo.CreateStudent(ctx, *row.Student)
+			case models.RosterRowUpdate:
+				// данные, скопированные в аккаунт при регистрации, обновляются вместе с реестром
+				if err = studentsRepo.UpdateStudent(ctx, row.Student.Id, *row.Student); err == nil {
+					_, err = s.userRepo.WithDB(tx).SyncStudentData(ctx, row.BookId, *row.Student)
+				}
+			default:
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("line %d: %w", row.Line, err)
+			}
+		}
+
+		report.Applied = true
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudentsImport, AuditTargetStudent, filename, nil, report.Summary)
+	})
+	if err != nil {
+		if errors.Is(err, ErrRosterHasErrors) {
+			return report, err
+		}
+		return models.RosterImportReport{}, err
+	}
+
+	return report, nil
+}
+
+// resolveGroups находит группы строк по названию. Строки с неизвестной группой становятся невалидными:
+// группы заводятся заранее в структуре институтов, импорт их не создает.
+func (s *RosterService) resolveGroups(ctx context.Context, institutesRepo *repositories.InstitutesRepository, rows []models.RosterRow) error {
+	names := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if row.Student != nil {
+			names = append(names, row.Student.StudentGroup)
+		}
+	}
+
+	groups, err := institutesRepo.GetGroupsByNames(ctx, names)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]int64, len(groups))
+	for _, g := range groups {
+		byName[g.Name] = g.Id
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Student == nil {
+			continue
+		}
+
+		id, ok := byName[row.Student.StudentGroup]
+		if !ok {
+			row.Status = models.RosterRowInvalid
+			row.Errors = append(row.Errors, fmt.Sprintf("группа %q не найдена", row.Student.StudentGroup))
+			continue
+		}
+		row.Student.StudentGroupId = id
+	}
+
+	return nil
+}
+
+// classify сравнивает валидные строки с реестром и проставляет им статус.
+func (s *RosterService) classify(ctx context.Context, studentsRepo *repositories.StudentsRepository, rows []models.RosterRow) error {
+	// один номер зачетки в нескольких строках - конфликт для всех этих строк
+	lines := make(map[int64][]int)
+	for i, row := range rows {
+		if row.Status != models.RosterRowInvalid {
+			lines[row.BookId] = append(lines[row.BookId], i)
+		}
+	}
+
+	bookIds := make([]int64, 0, len(lines))
+	for bookId, idx := range lines {
+		if len(idx) == 1 {
+			bookIds = append(bookIds, bookId)
+			continue
+		}
+		for _, i := range idx {
+			rows[i].Status = models.RosterRowConflict
+			rows[i].Errors = append(rows[i].Errors, fmt.Sprintf("номер зачетной книжки %d встречается в файле %d раз", bookId, len(idx)))
+		}
+	}
+
+	existing, err := studentsRepo.GetStudentsByBookIds(ctx, bookIds)
+	if err != nil {
+		return err
+	}
+	byBookId := make(map[int64]models.RosterStudent, len(existing))
+	for _, st := range existing {
+		if !st.BirthDateSet {
+			st.BirthDate = time.Time{}
+		}
+		byBookId[st.BookId] = st
+	}
+
+	for i := range rows {
+		row := &rows[i]
+		if row.Status != "" {
+			continue
+		}
+
+		current, ok := byBookId[row.BookId]
+		if !ok {
+			row.Status = models.RosterRowInsert
+			continue
+		}
+
+		row.Student.Id = current.Id
+		row.Changes = studentChanges(current.Student, *row.Student)
+
+		switch {
+		case len(row.Changes) == 0:
+			row.Status = models.RosterRowUnchanged
+		case current.Registered && changesIdentity(row.Changes):
+			// по зачетке уже зарегистрирован аккаунт: смена ФИО или даты рождения может означать
+			// опечатку в номере, такие строки нужно проверить вручную
+			row.Status = models.RosterRowConflict
+			row.Errors = append(row.Errors, "по этой зачетке уже зарегистрирован аккаунт, а ФИО или дата рождения отличаются")
+		default:
+			row.Status = models.RosterRowUpdate
+		}
+	}
+
+	return nil
+}
+
+// rosterColumns сопоставляет заголовки файла с колонками реестра.
+func rosterColumns(header []string) (map[string]int, error) {
+	columns := make(map[string]int)
+	for i, cell := range header {
+		key := strings.Join(strings.Fields(strings.ToLower(strings.TrimPrefix(cell, "\ufeff"))), " ")
+		key = strings.ReplaceAll(key, "ё", "е")
+		if col, ok := rosterHeaders[key]; ok {
+			if _, dup := columns[col]; !dup {
+				columns[col] = i
+			}
+		}
+	}
+
+	var missing []string
+	if _, ok := columns[rosterColBookId]; !ok {
+		missing = append(missing, rosterColBookId)
+	}
+	if _, ok := columns[rosterColGroup]; !ok {
+		missing = append(missing, rosterColGroup)
+	}
+	if _, ok := columns[rosterColBirthDate]; !ok {
+		missing = append(missing, rosterColBirthDate)
+	}
+	if _, ok := columns[rosterColFIO]; !ok {
+		_, hasSurname := columns[rosterColSurname]
+		_, hasName := columns[rosterColName]
+		if !hasSurname || !hasName {
+			missing = append(missing, "fio или surname и name")
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrRosterMissingColumns, strings.Join(missing, ", "))
+	}
+
+	return columns, nil
+}
+
+// parseRosterRow валидирует строку файла. Невалидная строка получает статус invalid и список ошибок.
+func parseRosterRow(line int, cells []string, columns map[string]int) models.RosterRow {
+	row := models.RosterRow{Line: line}
+
+	cell := func(col string) string {
+		i, ok := columns[col]
+		if !ok || i >= len(cells) {
+			return ""
+		}
+		return strings.Join(strings.Fields(cells[i]), " ")
+	}
+
+	student := models.Student{
+		Surname:      cell(rosterColSurname),
+		Name:         cell(rosterColName),
+		MiddleName:   cell(rosterColMiddleName),
+		StudentGroup: cell(rosterColGroup),
+	}
+
+	// ФИО одной колонкой: фамилия, имя и отчество (может быть из нескольких слов или отсутствовать)
+	if fio := cell(rosterColFIO); fio != "" && student.Surname == "" && student.Name == "" {
+		parts := strings.Fields(fio)
+		switch len(parts) {
+		case 1:
+			student.Surname = parts[0]
+		case 2:
+			student.Surname, student.Name = parts[0], parts[1]
+		default:
+			student.Surname, student.Name, student.MiddleName = parts[0], parts[1], strings.Join(parts[2:], " ")
+		}
+	}
+
+	bookIdRaw := cell(rosterColBookId)
+	bookId, err := strconv.ParseInt(strings.TrimSuffix(bookIdRaw, ".0"), 10, 64)
+	switch {
+	case bookIdRaw == "":
+		row.Errors = append(row.Errors, "не указан номер зачетной книжки")
+	case err != nil || bookId <= 0 || bookId > 1<<31-1:
+		row.Errors = append(row.Errors, fmt.Sprintf("некорректный номер зачетной книжки %q", bookIdRaw))
+	default:
+		student.BookId = bookId
+		row.BookId = bookId
+	}
+
+	if student.Surname == "" {
+		row.Errors = append(row.Errors, "не указана фамилия")
+	}
+	if student.Name == "" {
+		row.Errors = append(row.Errors, "не указано имя")
+	}
+	if student.StudentGroup == "" {
+		row.Errors = append(row.Errors, "не указана группа")
+	}
+
+	if raw := cell(rosterColBirthDate); raw == "" {
+		row.Errors = append(row.Errors, "не указана дата рождения")
+	} else {
+		birthDate, ok := parseRosterDate(raw)
+		if !ok {
+			row.Errors = append(row.Errors, fmt.Sprintf("некорректная дата рождения %q", raw))
+		} else {
+			student.BirthDate = birthDate
+		}
+	}
+
+	if len(row.Errors) > 0 {
+		row.Status = models.RosterRowInvalid
+		return row
+	}
+
+	row.Student = &student
+	return row
+}
+
+// parseRosterDate разбирает дату рождения: текстом в одном из привычных форматов или серийным номером Excel.
+func parseRosterDate(raw string) (time.Time, bool) {
+	for _, layout := range rosterDateLayouts {
+		if t, err := time.Parse(layout, raw); err == nil {
+			return t, validBirthDate(t)
+		}
+	}
+
+	if serial, err := strconv.ParseFloat(raw, 64); err == nil && serial > 0 {
+		t := helpers.ExcelSerialToTime(serial)
+		return t, validBirthDate(t)
+	}
+
+	return time.Time{}, false
+}
+
+func validBirthDate(t time.Time) bool {
+	return t.Year() >= 1900 && t.Before(time.Now())
+}
+
+// studentChanges возвращает поля, которые отличаются у студента в реестре и в файле.
+func studentChanges(current, updated models.Student) []string {
+	var changes []string
+	if current.Surname != updated.Surname {
+		changes = append(changes, "surname")
+	}
+	if current.Name != updated.Name {
+		changes = append(changes, "name")
+	}
+	if current.MiddleName != updated.MiddleName {
+		changes = append(changes, "middle_name")
+	}
+	if !current.BirthDate.Equal(updated.BirthDate) {
+		changes = append(changes, "birth_date")
+	}
+	if current.StudentGroup != updated.StudentGroup {
+		changes = append(changes, "student_group")
+	}
+	return changes
+}
+
+// changesIdentity сообщает, что изменения затрагивают данные, по которым студент опознается при регистрации.
+func changesIdentity(changes []string) bool {
+	for _, field := range changes {
+		if field != "student_group" {
+			return true
+		}
+	}
+	return false
+}
+
+func summarizeRoster(rows []models.RosterRow) models.RosterImportSummary {
+	summary := models.RosterImportSummary{Total: len(rows)}
+	for _, row := range rows {
+		switch row.Status {
+		case models.RosterRowInsert:
+			summary.Inserted++
+		case models.RosterRowUpdate:
+			summary.Updated++
+		case models.RosterRowUnchanged:
+			summary.Unchanged++
+		case models.RosterRowConflict:
+			summary.Conflicts++
+		case models.RosterRowInvalid:
+			summary.Invalid++
+		}
+	}
+	return summary
+}
+
+func isBlankRow(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
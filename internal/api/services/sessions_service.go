@@ -0,0 +1,103 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+var (
+	ErrSessionNotFound = errors.New("сессия не найдена")
+	ErrTokenRevoked    = errors.New("токен отозван")
+)
+
+type SessionsService struct {
+	refreshRepo       *repositories.RefreshTokensRepository
+	impersonationRepo *repositories.ImpersonationRepository
+}
+
+func NewSessionsService(
+	refreshRepo *repositories.RefreshTokensRepository,
+	impersonationRepo *repositories.ImpersonationRepository,
+) *SessionsService {
+	return &SessionsService{
+		refreshRepo:       refreshRepo,
+		impersonationRepo: impersonationRepo,
+	}
+}
+
+// GetSessions возвращает активные сессии пользователя и помечает ту, с которой пришел запрос.
+func (s *SessionsService) GetSessions(ctx context.Context, userId, currentSessionId int64) ([]models.Session, error) {
+	sessions, err := s.refreshRepo.GetSessions(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range sessions {
+		sessions[i].Current = sessions[i].Id == currentSessionId
+	}
+
+	return sessions, nil
+}
+
+// RevokeSession завершает одну сессию пользователя.
+func (s *SessionsService) RevokeSession(ctx context.Context, userId, sessionId int64) error {
+	tag, err := s.refreshRepo.DeleteSession(ctx, userId, sessionId)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+// RevokeAllSessions завершает все сессии пользователя и возвращает их количество.
+func (s *SessionsService) RevokeAllSessions(ctx context.Context, userId int64) (int64, error) {
+	return s.refreshRepo.DeleteRefreshTokens(ctx, userId)
+}
+
+// CheckAccess проверяет, что access токен не отозван: пользователь существует,
+// его версия токенов не менялась (роль, удаление) и сессия, из которой выдан токен, не завершена.
+func (s *SessionsService) CheckAccess(ctx context.Context, payload *models.Payload) error {
+	if payload.IsImpersonated() {
+		return s.checkImpersonationAccess(ctx, payload)
+	}
+
+	state, err := s.refreshRepo.GetAccessState(ctx, payload.Sub, payload.Sid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTokenRevoked
+		}
+		return err
+	}
+
+	if state.TokenVersion != payload.Ver || !state.SessionActive {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
+
+// checkImpersonationAccess проверяет токен входа от имени пользователя: сессия не завершена
+// и не истекла, а версия токенов пользователя не менялась.
+func (s *SessionsService) checkImpersonationAccess(ctx context.Context, payload *models.Payload) error {
+	state, err := s.impersonationRepo.GetAccessState(ctx, payload.Sub, payload.Sid)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTokenRevoked
+		}
+		return err
+	}
+
+	if state.TokenVersion != payload.Ver || !state.Active {
+		return ErrTokenRevoked
+	}
+
+	return nil
+}
@@ -6,30 +6,60 @@ import (
 	"bobri/pkg/helpers"
 	"context"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
 var (
 	ErrUserAlreadyExists    = errors.New("пользователь с таким номером зачетки уже существует")
 	ErrTokenGenerationError = errors.New("ошибка при генерации токена")
 	ErrUpsetLinkToken       = errors.New("не удалось сохранить link token")
+	ErrStudentNotFound      = errors.New("студент не найден")
+	ErrStudentAlreadyExists = errors.New("студент с таким номером зачетки уже существует")
+	ErrInvalidStudentData   = errors.New("некорректные данные студента")
 )
 
 type StudentsService struct {
-	studentsRepo *repositories.StudentsRepository
-	uow          *repositories.UoW
+	studentsRepo   *repositories.StudentsRepository
+	userRepo       *repositories.UserRepository
+	institutesRepo *repositories.InstitutesRepository
+	throttle       *AuthThrottleService
+	audit          *AuditService
+	uow            *repositories.UoW
 }
 
-func NewStudentsService(repo *repositories.StudentsRepository, uow *repositories.UoW) *StudentsService {
+func NewStudentsService(
+	repo *repositories.StudentsRepository,
+	userRepo *repositories.UserRepository,
+	institutesRepo *repositories.InstitutesRepository,
+	throttle *AuthThrottleService,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *StudentsService {
 	return &StudentsService{
-		studentsRepo: repo,
-		uow:          uow,
+		studentsRepo:   repo,
+		userRepo:       userRepo,
+		institutesRepo: institutesRepo,
+		throttle:       throttle,
+		audit:          audit,
+		uow:            uow,
 	}
 }
 
-func (s *StudentsService) CheckStudent(ctx context.Context, bookId int64) (models.AuthStatus, error) {
+func (s *StudentsService) CheckStudent(ctx context.Context, bookId int64, ip string) (models.AuthStatus, error) {
+	// каждый запрос учитывается, чтобы нельзя было перебирать номера студенческих
+	account := strconv.FormatInt(bookId, 10)
+	if err := s.throttle.Check(ctx, ThrottleScopeCheckStudent, ip, account); err != nil {
+		return models.AuthStatus{}, err
+	}
+	if err := s.throttle.RegisterAttempt(ctx, ThrottleScopeCheckStudent, ip, account); err != nil {
+		return models.AuthStatus{}, err
+	}
+
 	// проверяем, есть ли студент
 	student, err := s.studentsRepo.GetStudentByBookId(ctx, bookId)
 	if err != nil {
@@ -86,3 +116,164 @@ func (s *StudentsService) CheckStudent(ctx context.Context, bookId int64) (model
 func (s *StudentsService) GetStudents(ctx context.Context, limit int) ([]models.Student, error) {
 	return s.studentsRepo.GetAllStudents(ctx, limit)
 }
+
+// CreateStudent добавляет студента в реестр, после чего он может зарегистрироваться.
+func (s *StudentsService) CreateStudent(ctx context.Context, actorId int64, req models.CreateStudentRequest) (models.Student, error) {
+	birthDate, err := parseStudentBirthDate(req.BirthDate)
+	if err != nil {
+		return models.Student{}, err
+	}
+
+	student := models.Student{
+		BookId:       req.BookId,
+		Surname:      strings.TrimSpace(req.Surname),
+		Name:         strings.TrimSpace(req.Name),
+		MiddleName:   strings.TrimSpace(req.MiddleName),
+		BirthDate:    birthDate,
+		StudentGroup: strings.TrimSpace(req.StudentGroup),
+	}
+	if student.BookId <= 0 || student.Surname == "" || student.Name == "" || student.StudentGroup == "" {
+		return models.Student{}, ErrInvalidStudentData
+	}
+
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		studentsRepo := s.studentsRepo.WithDB(tx)
+
+		group, err := resolveGroup(ctx, s.institutesRepo.WithDB(tx), 0, student.StudentGroup)
+		if err != nil {
+			return err
+		}
+		student.StudentGroupId = group.Id
+
+		if err := studentsRepo.CreateStudent(ctx, student); err != nil {
+			return mapStudentUniqueViolation(err)
+		}
+
+		created, err := studentsRepo.GetStudentByBookId(ctx, student.BookId)
+		if err != nil {
+			return err
+		}
+		student = created
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudentCreate, AuditTargetStudent, student.BookId, nil, student)
+	})
+	if err != nil {
+		return models.Student{}, err
+	}
+
+	return student, nil
+}
+
+// UpdateStudent меняет данные студента. Аккаунт, зарегистрированный по этой зачетке, получает те же
+// ФИО, дату рождения, группу и номер зачетки, что и реестр.
+func (s *StudentsService) UpdateStudent(ctx context.Context, actorId, bookId int64, req models.UpdateStudentRequest) (models.Student, error) {
+	var updated models.Student
+
+	err := s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		studentsRepo := s.studentsRepo.WithDB(tx)
+
+		before, err := studentsRepo.GetStudentByBookId(ctx, bookId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrStudentNotFound
+			}
+			return err
+		}
+
+		updated = before
+		if req.BookId != 0 {
+			if req.BookId < 0 {
+				return ErrInvalidStudentData
+			}
+			updated.BookId = req.BookId
+		}
+		if v := strings.TrimSpace(req.Surname); v != "" {
+			updated.Surname = v
+		}
+		if v := strings.TrimSpace(req.Name); v != "" {
+			updated.Name = v
+		}
+		if v := strings.TrimSpace(req.MiddleName); v != "" {
+			updated.MiddleName = v
+		}
+		if v := strings.TrimSpace(req.StudentGroup); v != "" && v != before.StudentGroup {
+			group, err := resolveGroup(ctx, s.institutesRepo.WithDB(tx), 0, v)
+			if err != nil {
+				return err
+			}
+			updated.StudentGroupId = group.Id
+			updated.StudentGroup = group.Name
+		}
+		if req.BirthDate != "" {
+			if updated.BirthDate, err = parseStudentBirthDate(req.BirthDate); err != nil {
+				return err
+			}
+		}
+
+		// токены привязки выданы на старый номер зачетки
+		if updated.BookId != before.BookId {
+			if err = studentsRepo.DeleteLinkTokens(ctx, before.BookId); err != nil {
+				return err
+			}
+		}
+
+		if err = studentsRepo.UpdateStudent(ctx, before.Id, updated); err != nil {
+			return mapStudentUniqueViolation(err)
+		}
+
+		if _, err = s.userRepo.WithDB(tx).SyncStudentData(ctx, before.BookId, updated); err != nil {
+			return mapStudentUniqueViolation(err)
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudentUpdate, AuditTargetStudent, before.BookId, before, updated)
+	})
+	if err != nil {
+		return models.Student{}, err
+	}
+
+	return updated, nil
+}
+
+// DeleteStudent удаляет студента из реестра. Аккаунт, зарегистрированный по его зачетке, не удаляется,
+// чтобы сохранить выполненные события, но отвязывается от зачетки и теряет выданные токены.
+func (s *StudentsService) DeleteStudent(ctx context.Context, actorId, bookId int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		studentsRepo := s.studentsRepo.WithDB(tx)
+
+		before, err := studentsRepo.GetStudentByBookId(ctx, bookId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrStudentNotFound
+			}
+			return err
+		}
+
+		if err = s.userRepo.WithDB(tx).DetachStudent(ctx, bookId); err != nil {
+			return err
+		}
+
+		if err = studentsRepo.DeleteStudent(ctx, bookId); err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditStudentDelete, AuditTargetStudent, bookId, before, nil)
+	})
+}
+
+// parseStudentBirthDate разбирает дату рождения в формате ГГГГ-ММ-ДД.
+func parseStudentBirthDate(raw string) (time.Time, error) {
+	birthDate, err := time.Parse("2006-01-02", strings.TrimSpace(raw))
+	if err != nil || !validBirthDate(birthDate) {
+		return time.Time{}, ErrInvalidStudentData
+	}
+	return birthDate, nil
+}
+
+// mapStudentUniqueViolation превращает нарушение уникальности номера зачетки в ErrStudentAlreadyExists.
+func mapStudentUniqueViolation(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrStudentAlreadyExists
+	}
+	return err
+}
@@ -17,51 +17,152 @@ var (
 type TokenProvider struct {
 	jwtMaker          *helpers.JWTMaker
 	refreshTokensRepo *repositories.RefreshTokensRepository
+	permissionsRepo   *repositories.PermissionsRepository
 }
 
-func NewTokenProvider(jwt *helpers.JWTMaker, repo *repositories.RefreshTokensRepository) *TokenProvider {
+func NewTokenProvider(
+	jwt *helpers.JWTMaker,
+	repo *repositories.RefreshTokensRepository,
+	permissionsRepo *repositories.PermissionsRepository,
+) *TokenProvider {
 	return &TokenProvider{
 		jwtMaker:          jwt,
 		refreshTokensRepo: repo,
+		permissionsRepo:   permissionsRepo,
 	}
 }
 
-// IssuePair генерирует access token и refresh token и сохраняет обновленный refresh token.
+// refreshTokenTTL - время жизни refresh токена (и сессии без активности).
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// IssuePair открывает новую сессию: генерирует access token и refresh token и сохраняет refresh token.
+// Остальные сессии пользователя (другие устройства) не затрагиваются.
 // Работает с DBTX, что позволяет использовать и транзакцию, и пул.
-func (p *TokenProvider) IssuePair(ctx context.Context, db repositories.DBTX, userId, roleLevel int64) (models.GetTokensResponse, error) {
-	// генерируем access token
-	accessToken, expUnix, err := p.jwtMaker.Issue(userId, roleLevel)
+func (p *TokenProvider) IssuePair(ctx context.Context, db repositories.DBTX, userId, roleLevel int64, meta models.SessionMeta) (models.GetTokensResponse, error) {
+	// создаем новый refresh token
+	rawRefreshToken, err := helpers.NewRefreshToken()
 	if err != nil {
 		return models.GetTokensResponse{}, err
 	}
 
-	// удаляем предыдущие refresh токены
-	_, err = p.refreshTokensRepo.WithDB(db).DeleteRefreshTokens(ctx, userId)
+	hash := helpers.HashToken(rawRefreshToken)
+	expiresAt := time.Now().Add(refreshTokenTTL)
+
+	if meta.DeviceName == "" {
+		meta.DeviceName = meta.UserAgent
+	}
+
+	// заодно чистим истекшие сессии пользователя вместе с историей ротаций
+	if err = p.refreshTokensRepo.WithDB(db).DeleteExpiredRefreshTokens(ctx, userId); err != nil {
+		return models.GetTokensResponse{}, err
+	}
+
+	sessionId, err := p.refreshTokensRepo.WithDB(db).CreateRefreshToken(ctx, userId, hash, expiresAt, meta)
 	if err != nil {
 		return models.GetTokensResponse{}, err
 	}
 
-	// создаем новый refresh token
+	// генерируем access token, привязанный к сессии и текущей версии токенов пользователя
+	tokenVersion, err := p.refreshTokensRepo.WithDB(db).GetTokenVersion(ctx, userId)
+	if err != nil {
+		return models.GetTokensResponse{}, err
+	}
+
+	permissions, err := p.permissionsRepo.WithDB(db).GetPermissionsByRoleLevel(ctx, roleLevel)
+	if err != nil {
+		return models.GetTokensResponse{}, err
+	}
+
+	accessToken, expUnix, err := p.jwtMaker.Issue(helpers.AccessClaims{
+		UserID:       userId,
+		RoleLevel:    roleLevel,
+		Permissions:  permissions,
+		SessionID:    sessionId,
+		TokenVersion: tokenVersion,
+	})
+	if err != nil {
+		return models.GetTokensResponse{}, err
+	}
+
+	return models.GetTokensResponse{
+		AccessToken:  accessToken,
+		RefreshToken: rawRefreshToken,
+		ExpUnix:      expUnix,
+		SessionId:    sessionId,
+	}, nil
+}
+
+// RotatePair перевыпускает пару токенов внутри существующей сессии: parent помечается как обменянный,
+// а новый refresh token добавляется в то же семейство со ссылкой на parent.
+func (p *TokenProvider) RotatePair(ctx context.Context, db repositories.DBTX, parent models.RefreshSession, roleLevel int64, meta models.SessionMeta) (models.GetTokensResponse, error) {
 	rawRefreshToken, err := helpers.NewRefreshToken()
 	if err != nil {
 		return models.GetTokensResponse{}, err
 	}
 
 	hash := helpers.HashToken(rawRefreshToken)
-	expiresAt := time.Now().Add(30 * 24 * time.Hour)
+	expiresAt := time.Now().Add(refreshTokenTTL)
 
-	tag, err := p.refreshTokensRepo.WithDB(db).CreateRefreshToken(ctx, userId, hash, expiresAt)
+	_, err = p.refreshTokensRepo.WithDB(db).RotateRefreshToken(ctx, parent.Id, hash, expiresAt, meta)
 	if err != nil {
 		return models.GetTokensResponse{}, err
 	}
 
-	if tag.RowsAffected() != 1 {
-		return models.GetTokensResponse{}, ErrRowsAffected
+	tokenVersion, err := p.refreshTokensRepo.WithDB(db).GetTokenVersion(ctx, parent.UserId)
+	if err != nil {
+		return models.GetTokensResponse{}, err
+	}
+
+	permissions, err := p.permissionsRepo.WithDB(db).GetPermissionsByRoleLevel(ctx, roleLevel)
+	if err != nil {
+		return models.GetTokensResponse{}, err
+	}
+
+	accessToken, expUnix, err := p.jwtMaker.Issue(helpers.AccessClaims{
+		UserID:       parent.UserId,
+		RoleLevel:    roleLevel,
+		Permissions:  permissions,
+		SessionID:    parent.FamilyId,
+		TokenVersion: tokenVersion,
+	})
+	if err != nil {
+		return models.GetTokensResponse{}, err
 	}
 
 	return models.GetTokensResponse{
 		AccessToken:  accessToken,
 		RefreshToken: rawRefreshToken,
 		ExpUnix:      expUnix,
+		SessionId:    parent.FamilyId,
 	}, nil
 }
+
+// IssueImpersonation выпускает access токен от имени пользователя userId для разработчика actorId.
+// Refresh токен не выдается: сессия живет ровно столько, сколько access токен.
+// sessionId - запись impersonation_sessions, по которой токен можно отозвать.
+func (p *TokenProvider) IssueImpersonation(ctx context.Context, db repositories.DBTX, actorId, userId, roleLevel, sessionId int64, readOnly bool) (string, int64, error) {
+	tokenVersion, err := p.refreshTokensRepo.WithDB(db).GetTokenVersion(ctx, userId)
+	if err != nil {
+		return "", 0, err
+	}
+
+	permissions, err := p.permissionsRepo.WithDB(db).GetPermissionsByRoleLevel(ctx, roleLevel)
+	if err != nil {
+		return "", 0, err
+	}
+
+	return p.jwtMaker.Issue(helpers.AccessClaims{
+		UserID:       userId,
+		RoleLevel:    roleLevel,
+		Permissions:  permissions,
+		SessionID:    sessionId,
+		TokenVersion: tokenVersion,
+		ActorID:      actorId,
+		ReadOnly:     readOnly,
+	})
+}
+
+// ImpersonationTTL - сколько живет вход от имени пользователя (время жизни access токена).
+func (p *TokenProvider) ImpersonationTTL() time.Duration {
+	return p.jwtMaker.Lifetime()
+}
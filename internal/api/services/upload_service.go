@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bobri/internal/api/repositories"
+	"bobri/internal/models"
+	"bobri/pkg/storage"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxUploadSize - предел размера файла для любого вида загрузки; у каждого вида свой, меньший предел.
+const MaxUploadSize = 10 << 20
+
+var (
+	ErrEmptyFile           = errors.New("файл пустой")
+	ErrFileTooLarge        = errors.New("файл слишком большой")
+	ErrUnsupportedFileType = errors.New("недопустимый тип файла")
+)
+
+// uploadKind - правила загрузки одного вида файлов: папка в хранилище, предельный размер
+// и допустимые типы (тип -> расширение).
+type uploadKind struct {
+	dir     string
+	maxSize int64
+	types   map[string]string
+}
+
+var imageTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/webp": ".webp",
+	"image/gif":  ".gif",
+}
+
+var (
+	uploadAvatar   = uploadKind{dir: "avatars", maxSize: 2 << 20, types: imageTypes}
+	uploadIcon     = uploadKind{dir: "icons", maxSize: 1 << 20, types: imageTypes}
+	uploadEvidence = uploadKind{dir: "evidence", maxSize: MaxUploadSize, types: map[string]string{
+		"image/png":       ".png",
+		"image/jpeg":      ".jpg",
+		"image/webp":      ".webp",
+		"application/pdf": ".pdf",
+	}}
+)
+
+// UploadService - загрузка иконок, аватаров и подтверждений к заявкам в файловое хранилище.
+type UploadService struct {
+	storage  storage.Storage
+	userRepo *repositories.UserRepository
+}
+
+func NewUploadService(storage storage.Storage, userRepo *repositories.UserRepository) *UploadService {
+	return &UploadService{
+		storage:  storage,
+		userRepo: userRepo,
+	}
+}
+
+// UploadAvatar сохраняет аватар пользователя и сразу ставит его в профиль.
+func (s *UploadService) UploadAvatar(ctx context.Context, userId int64, name string, data []byte) (models.ProfileResponse, error) {
+	file, err := s.put(ctx, uploadAvatar, name, data)
+	if err != nil {
+		return models.ProfileResponse{}, err
+	}
+
+	rows, err := s.userRepo.UpdateUser(ctx, models.UpdateUserRequest{
+		UserId:  userId,
+		NewData: models.UserUpdateData{Avatar: file.Url},
+	})
+	if err != nil {
+		return models.ProfileResponse{}, err
+	}
+	if rows != 1 {
+		return models.ProfileResponse{}, ErrUserNotFound
+	}
+
+	return s.userRepo.GetProfileByUserID(ctx, userId)
+}
+
+// UploadIcon сохраняет иконку для события или типа мероприятия.
+func (s *UploadService) UploadIcon(ctx context.Context, name string, data []byte) (models.UploadedFile, error) {
+	return s.put(ctx, uploadIcon, name, data)
+}
+
+// UploadEvidence сохраняет подтверждение (скан, фото, сертификат) для заявки на баллы.
+func (s *UploadService) UploadEvidence(ctx context.Context, name string, data []byte) (models.UploadedFile, error) {
+	return s.put(ctx, uploadEvidence, name, data)
+}
+
+// put проверяет размер и тип файла и сохраняет его. Тип определяется по содержимому, а не по
+// заголовку запроса или расширению. Ключ - хеш содержимого, поэтому URL не меняется и одинаковые файлы не дублируются.
+func (s *UploadService) put(ctx context.Context, kind uploadKind, name string, data []byte) (models.UploadedFile, error) {
+	if len(data) == 0 {
+		return models.UploadedFile{}, ErrEmptyFile
+	}
+	if int64(len(data)) > kind.maxSize {
+		return models.UploadedFile{}, ErrFileTooLarge
+	}
+
+	contentType := http.DetectContentType(data)
+	ext, ok := kind.types[contentType]
+	if !ok {
+		return models.UploadedFile{}, ErrUnsupportedFileType
+	}
+
+	sum := sha256.Sum256(data)
+	key := kind.dir + "/" + hex.EncodeToString(sum[:]) + ext
+
+	url, err := s.storage.Put(ctx, key, contentType, data)
+	if err != nil {
+		return models.UploadedFile{}, err
+	}
+
+	return models.UploadedFile{
+		Url:         url,
+		Name:        cleanFileName(name, key),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}, nil
+}
+
+// cleanFileName оставляет от имени файла клиента только базовое имя разумной длины.
+func cleanFileName(name, key string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" || !utf8.ValidString(name) {
+		return filepath.Base(key)
+	}
+	if utf8.RuneCountInString(name) > 255 {
+		name = string([]rune(name)[:255])
+	}
+	return name
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// memoryStorage - хранилище в памяти для тестов загрузки.
+type memoryStorage struct {
+	files map[string][]byte
+}
+
+func (s *memoryStorage) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	s.files[key] = data
+	return "/uploads/" + key, nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s.files, key)
+	return nil
+}
+
+// sampleFile возвращает файл размером size с сигнатурой формата в начале.
+func sampleFile(signature string, size int) []byte {
+	data := make([]byte, size)
+	copy(data, signature)
+	return data
+}
+
+const (
+	pngSignature = "\x89PNG\r\n\x1a\n"
+	gifSignature = "GIF89a"
+	pdfSignature = "%PDF-1.4\n"
+)
+
+func TestUploadLimits(t *testing.T) {
+	store := &memoryStorage{files: map[string][]byte{}}
+	s := NewUploadService(store, nil)
+	ctx := context.Background()
+
+	upload := map[string]func(data []byte) error{
+		"icon": func(data []byte) error {
+			_, err := s.UploadIcon(ctx, "file", data)
+			return err
+		},
+		"evidence": func(data []byte) error {
+			_, err := s.UploadEvidence(ctx, "file", data)
+			return err
+		},
+		// проверки аватара выполняются до обращения к профилю пользователя
+		"avatar": func(data []byte) error {
+			_, err := s.UploadAvatar(ctx, 1, "file", data)
+			return err
+		},
+	}
+
+	tests := []struct {
+		name    string
+		kind    string
+		data    []byte
+		wantErr error
+	}{
+		{"icon png", "icon", sampleFile(pngSignature, 1<<20), nil},
+		{"icon over 1 MiB", "icon", sampleFile(pngSignature, 1<<20+1), ErrFileTooLarge},
+		{"icon gif", "icon", sampleFile(gifSignature, 100), nil},
+		{"icon pdf", "icon", sampleFile(pdfSignature, 100), ErrUnsupportedFileType},
+		{"icon empty", "icon", nil, ErrEmptyFile},
+		{"evidence pdf", "evidence", sampleFile(pdfSignature, 5<<20), nil},
+		{"evidence at limit", "evidence", sampleFile(pngSignature, MaxUploadSize), nil},
+		{"evidence over limit", "evidence", sampleFile(pngSignature, MaxUploadSize+1), ErrFileTooLarge},
+		{"evidence gif", "evidence", sampleFile(gifSignature, 100), ErrUnsupportedFileType},
+		{"evidence text", "evidence", []byte("plain text, not a pdf"), ErrUnsupportedFileType},
+		{"avatar over 2 MiB", "avatar", sampleFile(pngSignature, 2<<20+1), ErrFileTooLarge},
+		{"avatar pdf", "avatar", sampleFile(pdfSignature, 100), ErrUnsupportedFileType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := upload[tt.kind](tt.data); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUploadKeyAndName(t *testing.T) {
+	store := &memoryStorage{files: map[string][]byte{}}
+	s := NewUploadService(store, nil)
+
+	file, err := s.UploadEvidence(context.Background(), `C:\scans\..\справка.pdf`, sampleFile(pdfSignature, 100))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ключ - хеш содержимого с расширением по типу файла, имя клиента в ключ не попадает
+	if !strings.HasPrefix(file.Url, "/uploads/evidence/") || !strings.HasSuffix(file.Url, ".pdf") || strings.Contains(file.Url, "справка") {
+		t.Errorf("url = %q", file.Url)
+	}
+	if file.Name != "справка.pdf" {
+		t.Errorf("name = %q, want %q", file.Name, "справка.pdf")
+	}
+	if file.ContentType != "application/pdf" || file.Size != 100 {
+		t.Errorf("content type %q, size %d", file.ContentType, file.Size)
+	}
+	if len(store.files) != 1 {
+		t.Errorf("stored %d files, want 1", len(store.files))
+	}
+}
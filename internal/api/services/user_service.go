@@ -5,29 +5,52 @@ import (
 	"bobri/internal/models"
 	"context"
 	"errors"
+
+	"github.com/jackc/pgx/v5"
 )
 
 type UserService struct {
-	userRepo *repositories.UserRepository
+	userRepo       *repositories.UserRepository
+	institutesRepo *repositories.InstitutesRepository
+	audit          *AuditService
+	uow            *repositories.UoW
 }
 
-func NewUserService(userRepo *repositories.UserRepository) *UserService {
+func NewUserService(
+	userRepo *repositories.UserRepository,
+	institutesRepo *repositories.InstitutesRepository,
+	audit *AuditService,
+	uow *repositories.UoW,
+) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		institutesRepo: institutesRepo,
+		audit:          audit,
+		uow:            uow,
 	}
 }
 
-func (s *UserService) DeleteUser(ctx context.Context, userId int64) error {
-	rows, err := s.userRepo.DeleteUser(ctx, userId)
-	if err != nil {
-		return err
-	}
+func (s *UserService) DeleteUser(ctx context.Context, actorId, userId int64) error {
+	return s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		before, err := s.userRepo.WithDB(tx).GetProfileByUserID(ctx, userId)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return err
+		}
 
-	if rows == 0 {
-		return ErrUserNotFound
-	}
+		rows, err := s.userRepo.WithDB(tx).DeleteUser(ctx, userId)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		if rows == 0 {
+			return ErrUserNotFound
+		}
+
+		return s.audit.Record(ctx, tx, actorId, AuditUserDelete, AuditTargetUser, userId, before, nil)
+	})
 }
 
 func (s *UserService) GetUsers(ctx context.Context, maxRole int64, limit int) ([]models.User, error) {
@@ -38,16 +61,32 @@ func (s *UserService) GetProfile(ctx context.Context, userID int64) (models.Prof
 	return s.userRepo.GetProfileByUserID(ctx, userID)
 }
 
-func (s *UserService) UpdateUser(ctx context.Context, adminRole int64, req models.UpdateUserRequest) (models.UpdateUserResponse, error) {
-	// проверяем существование роли
+// UpdateUser обновляет данные пользователя. Менять можно только пользователей с ролью ниже,
+// чем у actor, а смена роли дополнительно требует права users:manage_roles.
+func (s *UserService) UpdateUser(ctx context.Context, actor *models.Payload, req models.UpdateUserRequest) (models.UpdateUserResponse, error) {
 	if req.NewData.RoleLevel != 0 {
+		if !actor.HasPermission(models.PermissionUsersManageRoles) || req.NewData.RoleLevel >= actor.RoleLevel {
+			return models.UpdateUserResponse{}, ErrForbidden
+		}
+
+		// проверяем существование роли
 		exists, err := s.userRepo.RoleExists(ctx, req.NewData.RoleLevel)
 		if err != nil {
 			return models.UpdateUserResponse{}, err
 		}
 		if !exists {
-			return models.UpdateUserResponse{}, errors.New("такой роли не существует")
+			return models.UpdateUserResponse{}, ErrRoleNotFound
+		}
+	}
+
+	// группа хранится ссылкой на student_groups, название переводим в id
+	if req.NewData.StudentGroupId != 0 || req.NewData.StudentGroup != "" {
+		group, err := resolveGroup(ctx, s.institutesRepo, req.NewData.StudentGroupId, req.NewData.StudentGroup)
+		if err != nil {
+			return models.UpdateUserResponse{}, err
 		}
+		req.NewData.StudentGroupId = group.Id
+		req.NewData.StudentGroup = group.Name
 	}
 
 	// получаем текущую роль
@@ -57,20 +96,37 @@ func (s *UserService) UpdateUser(ctx context.Context, adminRole int64, req model
 	}
 
 	// проверяем полномочия
-	if req.NewData.RoleLevel >= adminRole || curRole >= adminRole {
-		return models.UpdateUserResponse{}, errors.New("недостаточно прав")
+	if curRole >= actor.RoleLevel {
+		return models.UpdateUserResponse{}, ErrForbidden
 	}
 
-	// выполняем обновление через репозиторий
-	rows, err := s.userRepo.UpdateUser(ctx, req)
+	// выполняем обновление через репозиторий вместе с записью в журнал
+	err = s.uow.WithinTransaction(ctx, func(ctx context.Context, tx repositories.DBTX) error {
+		before, err := s.userRepo.WithDB(tx).GetProfileByUserID(ctx, req.UserId)
+		if err != nil {
+			return err
+		}
+
+		rows, err := s.userRepo.WithDB(tx).UpdateUser(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if rows != 1 {
+			return errors.New("RowsAffected != 1")
+		}
+
+		after, err := s.userRepo.WithDB(tx).GetProfileByUserID(ctx, req.UserId)
+		if err != nil {
+			return err
+		}
+
+		return s.audit.Record(ctx, tx, actor.Sub, AuditUserUpdate, AuditTargetUser, req.UserId, before, after)
+	})
 	if err != nil {
 		return models.UpdateUserResponse{}, err
 	}
 
-	if rows != 1 {
-		return models.UpdateUserResponse{}, errors.New("RowsAffected != 1")
-	}
-
 	return models.UpdateUserResponse{
 		Successful: true,
 		UserID:     req.UserId,
@@ -78,15 +134,16 @@ func (s *UserService) UpdateUser(ctx context.Context, adminRole int64, req model
 	}, nil
 }
 
-func (s *UserService) GetLeaderboard(ctx context.Context, limit int) ([]models.UserWithPoints, error) {
-	if limit <= 0 {
-		limit = 50
+// GetLeaderboard возвращает лидерборд, при необходимости ограниченный институтом, направлением или группой.
+func (s *UserService) GetLeaderboard(ctx context.Context, filter models.LeaderboardFilter) ([]models.UserWithPoints, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = 50
 	}
-	if limit > 1000 {
-		limit = 1000
+	if filter.Limit > 1000 {
+		filter.Limit = 1000
 	}
 
-	return s.userRepo.GetLeaderboard(ctx, limit)
+	return s.userRepo.GetLeaderboard(ctx, filter)
 }
 
 func (s *UserService) GetSuggestions(ctx context.Context) ([]models.Event, error) {
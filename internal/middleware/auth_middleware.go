@@ -1,16 +1,19 @@
 package middleware
 
 import (
+	"bobri/internal/api/services"
 	"bobri/internal/models"
 	"bobri/pkg/helpers"
-	"fmt"
+	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-func AuthenticationMiddleware(accessJwtMaker *helpers.JWTMaker, roleLevelRequired int64) gin.HandlerFunc {
+func AuthenticationMiddleware(accessJwtMaker *helpers.JWTMaker, sessionsService *services.SessionsService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Берем токен авторизации из header
 		authHeader := c.GetHeader("Authorization")
@@ -24,7 +27,15 @@ func AuthenticationMiddleware(accessJwtMaker *helpers.JWTMaker, roleLevelRequire
 		}
 
 		// Получаем часть с bearer токеном
-		tokenString := strings.Split(authHeader, "Bearer ")[1]
+		tokenString, ok := strings.CutPrefix(authHeader, "Bearer ")
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized,
+				models.ErrorResponse{
+					Error:   "Authorization header must use the Bearer scheme",
+					Message: "Не удалось найти bearer Токен в header",
+				})
+			return
+		}
 
 		// Валидируем JWT токен
 		claims, err := accessJwtMaker.Verify(tokenString)
@@ -45,10 +56,26 @@ func AuthenticationMiddleware(accessJwtMaker *helpers.JWTMaker, roleLevelRequire
 			payload.Sub = int64(sub)
 		}
 
+		if sid, ok := claims["sid"].(float64); ok {
+			payload.Sid = int64(sid)
+		}
+
+		if ver, ok := claims["ver"].(float64); ok {
+			payload.Ver = int64(ver)
+		}
+
 		if roleLevel, ok := claims["roleLevel"].(float64); ok {
 			payload.RoleLevel = int64(roleLevel)
 		}
 
+		if perms, ok := claims["perms"].([]interface{}); ok {
+			for _, perm := range perms {
+				if code, ok := perm.(string); ok {
+					payload.Permissions = append(payload.Permissions, code)
+				}
+			}
+		}
+
 		if exp, ok := claims["exp"].(float64); ok {
 			payload.Exp = int64(exp)
 		}
@@ -57,12 +84,40 @@ func AuthenticationMiddleware(accessJwtMaker *helpers.JWTMaker, roleLevelRequire
 			payload.Iat = int64(iat)
 		}
 
-		fmt.Println(payload.RoleLevel)
+		if act, ok := claims["act"].(map[string]interface{}); ok {
+			if actorSub, ok := act["sub"].(float64); ok {
+				payload.Act = &models.Actor{Sub: int64(actorSub)}
+			}
+		}
+
+		if ro, ok := claims["ro"].(bool); ok {
+			payload.ReadOnly = ro
+		}
+
+		// Проверяем, что токен не отозван (logout, смена роли, удаление пользователя)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err = sessionsService.CheckAccess(ctx, payload); err != nil {
+			if errors.Is(err, services.ErrTokenRevoked) {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{
+					Error:   err.Error(),
+					Message: "Access токен отозван. Обновите токены или войдите заново",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Не удалось проверить access токен",
+			})
+			return
+		}
 
-		if payload.RoleLevel < roleLevelRequired {
+		// токен только для чтения (вход от имени пользователя) не может ничего менять
+		if payload.ReadOnly && !isReadOnlyMethod(c.Request.Method) {
 			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
-				Error:   "Forbidden",
-				Message: "Не достаточно прав",
+				Error:   "read_only_token",
+				Message: "Токен выдан только для чтения",
 			})
 			return
 		}
@@ -72,3 +127,7 @@ func AuthenticationMiddleware(accessJwtMaker *helpers.JWTMaker, roleLevelRequire
 		c.Next()
 	}
 }
+
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
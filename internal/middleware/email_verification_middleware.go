@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EmailVerificationMiddleware не пускает пользователей с неподтвержденной почтой,
+// если политика подтверждения запрещает им действие area.
+// Должен стоять после AuthenticationMiddleware.
+func EmailVerificationMiddleware(verificationService *services.EmailVerificationService, area string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		if err := verificationService.CheckAccess(ctx, payload.Sub, area); err != nil {
+			if errors.Is(err, services.ErrEmailNotVerified) {
+				c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+					Error:   "email_not_verified",
+					Message: "Подтвердите почту, чтобы продолжить",
+				})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Не удалось проверить подтверждение почты",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"bobri/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ForbidImpersonation не пускает токены входа от имени пользователя: пароль, почта, 2FA,
+// сессии и удаление аккаунта меняет только сам владелец, даже если токен разрешает запись.
+// Должен стоять после AuthenticationMiddleware.
+func ForbidImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		if payload.IsImpersonated() {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "impersonation_forbidden",
+				Message: "Действие недоступно при входе от имени пользователя",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
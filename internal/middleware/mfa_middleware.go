@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"bobri/internal/api/services"
+	"bobri/internal/models"
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MFAEnrollmentMiddleware не пускает пользователей с ролью, требующей второго фактора,
+// пока они не включили двухфакторную аутентификацию в /me/2fa.
+// Должен стоять после AuthenticationMiddleware.
+func MFAEnrollmentMiddleware(mfaService *services.MFAService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		if !services.MFARequired(payload.RoleLevel) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+		defer cancel()
+
+		enabled, err := mfaService.IsEnabled(ctx, payload.Sub)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{
+				Error:   err.Error(),
+				Message: "Не удалось проверить двухфакторную аутентификацию",
+			})
+			return
+		}
+
+		if !enabled {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "mfa_enrollment_required",
+				Message: "Для доступа к панели администратора включите двухфакторную аутентификацию",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"bobri/internal/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission пускает дальше только если у роли владельца токена есть право permission.
+// Должен стоять после AuthenticationMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		payload := c.MustGet("userPayload").(*models.Payload)
+
+		if !payload.HasPermission(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{
+				Error:   "Forbidden",
+				Message: "Не достаточно прав: требуется " + permission,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
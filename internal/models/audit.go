@@ -0,0 +1,38 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+type AuditEntry struct {
+	Id         int64           `json:"id" db:"id"`
+	ActorId    int64           `json:"actor_id" db:"actor_id"`
+	Action     string          `json:"action" db:"action"`
+	TargetType string          `json:"target_type" db:"target_type"`
+	TargetId   string          `json:"target_id" db:"target_id"`
+	Before     json.RawMessage `json:"before" db:"before" swaggertype:"object"`
+	After      json.RawMessage `json:"after" db:"after" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditFilter - условия выборки журнала. Пустые поля не фильтруют.
+type AuditFilter struct {
+	ActorId    int64
+	Action     string
+	TargetType string
+	TargetId   string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+	Offset     int
+}
+
+// AuditCompletedEvent - выполненное событие пользователя в журнале действий.
+type AuditCompletedEvent struct {
+	UserId      int64     `json:"user_id" db:"user_id"`
+	EventId     int64     `json:"event_id" db:"event_id"`
+	Title       string    `json:"title" db:"title"`
+	Points      int       `json:"points" db:"points"`
+	CompletedAt time.Time `json:"completed_at" db:"completed_at"`
+}
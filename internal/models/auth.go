@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type AuthBookRequest struct {
 	BookId int64 `json:"book_id"`
 }
@@ -12,9 +14,10 @@ type AuthStatus struct {
 }
 
 type RegisterRequest struct {
-	Token    string `json:"token" binding:"required"`
-	Password string `json:"password"`
-	Email    string `json:"email"`
+	Token      string `json:"token" binding:"required"`
+	Password   string `json:"password"`
+	Email      string `json:"email"`
+	DeviceName string `json:"device_name"`
 }
 type RegisterResponse struct {
 	UserSubstructure `json:"user"`
@@ -22,12 +25,22 @@ type RegisterResponse struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Email      string `json:"email" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	DeviceName string `json:"device_name"`
 }
 type LoginResponse struct {
 	UserSubstructure `json:"user"`
 	AuthTokens       `json:"auth"`
+	MFARequired      bool   `json:"mfa_required"`
+	MFAToken         string `json:"mfa_token,omitempty"`
+	MFAExpiresInSec  int64  `json:"mfa_expires_in_sec,omitempty"`
+}
+
+type LoginMFARequest struct {
+	MFAToken   string `json:"mfa_token" binding:"required"`
+	Code       string `json:"code" binding:"required"`
+	DeviceName string `json:"device_name"`
 }
 
 type GetTokensRequest struct {
@@ -39,6 +52,7 @@ type GetTokensResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpUnix      int64  `json:"expires_at"`
+	SessionId    int64  `json:"-"`
 }
 type AuthTokens struct {
 	AccessToken  string `json:"access_token"`
@@ -48,6 +62,7 @@ type AuthTokens struct {
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token"`
+	DeviceName   string `json:"device_name"`
 }
 type RefreshTokenResponse struct {
 	UserID     int64 `json:"user_id"`
@@ -57,6 +72,9 @@ type RefreshTokenResponse struct {
 type ResetPasswordRequest struct {
 	Email string `json:"email" binding:"required"`
 }
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
 type SetNewPasswordRequest struct {
 	Token       string `json:"token" binding:"required"`
 	NewPassword string `json:"new_password" binding:"required"`
@@ -66,3 +84,100 @@ type EmailAuth struct {
 	EmailFrom string
 	EmailPass string
 }
+
+// SessionMeta - данные об устройстве, с которого открыта сессия.
+type SessionMeta struct {
+	DeviceName string
+	UserAgent  string
+	IP         string
+}
+
+// RefreshSession - запись refresh токена. Все токены, полученные ротацией
+// из одного логина, образуют семейство (FamilyId), которое и является сессией.
+type RefreshSession struct {
+	Id        int64      `db:"id"`
+	UserId    int64      `db:"user_id"`
+	FamilyId  int64      `db:"family_id"`
+	ParentId  *int64     `db:"parent_id"`
+	ExpiresAt time.Time  `db:"expires_at"`
+	RotatedAt *time.Time `db:"rotated_at"`
+	RevokedAt *time.Time `db:"revoked_at"`
+}
+
+type Session struct {
+	Id         int64     `json:"id" db:"id"`
+	DeviceName string    `json:"device_name" db:"device_name"`
+	UserAgent  string    `json:"user_agent" db:"user_agent"`
+	IP         string    `json:"ip" db:"ip"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at" db:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at" db:"expires_at"`
+	Current    bool      `json:"current" db:"-"`
+}
+
+type RevokeSessionsResponse struct {
+	Successful bool  `json:"successful"`
+	Revoked    int64 `json:"revoked"`
+}
+
+// AccessState - актуальное состояние пользователя и сессии для проверки access токена.
+type AccessState struct {
+	TokenVersion  int64
+	SessionActive bool
+}
+
+// ThrottleState - состояние счетчика попыток после очередной попытки.
+type ThrottleState struct {
+	Failures    int        `db:"failures"`
+	Lockouts    int        `db:"lockouts"`
+	LockedUntil *time.Time `db:"locked_until"`
+}
+
+type AuthLock struct {
+	Scope       string    `json:"scope" db:"scope"`
+	KeyType     string    `json:"key_type" db:"key_type"`
+	Key         string    `json:"key" db:"key"`
+	Lockouts    int       `json:"lockouts" db:"lockouts"`
+	LockedUntil time.Time `json:"locked_until" db:"locked_until"`
+}
+
+type UnlockRequest struct {
+	Key string `json:"key" binding:"required"`
+}
+
+type UserTOTP struct {
+	UserId       int64      `db:"user_id"`
+	Secret       string     `db:"secret"`
+	EnabledAt    *time.Time `db:"enabled_at"`
+	LastUsedStep int64      `db:"last_used_step"`
+}
+
+type MFAChallenge struct {
+	UserId    int64     `db:"user_id"`
+	Attempts  int       `db:"attempts"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+type TwoFactorStatus struct {
+	Enabled           bool  `json:"enabled"`
+	Required          bool  `json:"required"`
+	RecoveryCodesLeft int64 `json:"recovery_codes_left"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type TwoFactorRecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
@@ -1,11 +1,36 @@
 package models
 
+import "slices"
+
 type Payload struct {
-	Sub       int64 `json:"sub"`
-	RoleLevel int64 `json:"role_level"`
-	Exp       int64 `json:"exp"`
-	Iat       int64 `json:"iat"`
+	Sub         int64    `json:"sub"`
+	Sid         int64    `json:"sid"`
+	Ver         int64    `json:"ver"`
+	RoleLevel   int64    `json:"role_level"`
+	Permissions []string `json:"permissions"`
+	Exp         int64    `json:"exp"`
+	Iat         int64    `json:"iat"`
+	// Act - кто на самом деле действует, если токен выдан для входа от имени пользователя.
+	// В этом случае Sid - id записи impersonation_sessions, а не сессии refresh токенов
+	Act *Actor `json:"act,omitempty"`
+	// ReadOnly - токен разрешает только запросы на чтение
+	ReadOnly bool `json:"read_only"`
+}
+
+type Actor struct {
+	Sub int64 `json:"sub"`
+}
+
+// IsImpersonated сообщает, что токен выдан для входа от имени пользователя.
+func (p *Payload) IsImpersonated() bool {
+	return p.Act != nil
+}
+
+// HasPermission проверяет, выдано ли право роли владельца токена.
+func (p *Payload) HasPermission(permission string) bool {
+	return slices.Contains(p.Permissions, permission)
 }
+
 type ErrorResponse struct {
 	Error   string
 	Message string
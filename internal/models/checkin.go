@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// CheckinSettings - окно самостоятельной отметки на событии.
+type CheckinSettings struct {
+	EventId  int64     `json:"event_id" db:"event_id"`
+	OpensAt  time.Time `json:"opens_at" db:"opens_at"`
+	ClosesAt time.Time `json:"closes_at" db:"closes_at"`
+	// CheckedInCount - сколько студентов уже отметились
+	CheckedInCount int `json:"checked_in_count" db:"checked_in_count"`
+}
+
+// CheckinSecret - окно отметки вместе с ключом подписи токенов. Наружу не отдается.
+type CheckinSecret struct {
+	OpensAt  time.Time `db:"opens_at"`
+	ClosesAt time.Time `db:"closes_at"`
+	Secret   []byte    `db:"secret"`
+}
+
+// SetCheckinSettingsRequest - окно, в которое студенты могут отметиться по QR коду.
+type SetCheckinSettingsRequest struct {
+	OpensAt  *time.Time `json:"opens_at" binding:"required"`
+	ClosesAt *time.Time `json:"closes_at" binding:"required"`
+}
+
+// CheckinTokenResponse - токен для QR кода. После expires_at нужно запросить новый.
+type CheckinTokenResponse struct {
+	EventId   int64     `json:"event_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// CheckinRequest - отсканированный студентом токен.
+type CheckinRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// CheckinResponse - засчитанное по отметке событие.
+type CheckinResponse struct {
+	EventId     int64     `json:"event_id"`
+	Title       string    `json:"title"`
+	Points      int       `json:"points"`
+	CheckedInAt time.Time `json:"checked_in_at"`
+}
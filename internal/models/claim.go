@@ -0,0 +1,60 @@
+package models
+
+import "time"
+
+// Статусы заявки студента на баллы.
+const (
+	ClaimStatusPending   = "pending"   // ждет рассмотрения
+	ClaimStatusApproved  = "approved"  // одобрена, событие отмечено выполненным
+	ClaimStatusRejected  = "rejected"  // отклонена с причиной
+	ClaimStatusWithdrawn = "withdrawn" // отозвана студентом до рассмотрения
+)
+
+// ClaimAttachment - подтверждение к заявке: ссылка на файл, статью, репозиторий, сертификат.
+type ClaimAttachment struct {
+	Id      int64  `json:"id" db:"id"`
+	ClaimId int64  `json:"-" db:"claim_id"`
+	Url     string `json:"url" db:"url"`
+	Name    string `json:"name" db:"name"`
+}
+
+// Claim - заявка студента на отметку о выполнении события.
+type Claim struct {
+	Id           int64             `json:"id" db:"id"`
+	UserId       int64             `json:"user_id" db:"user_id"`
+	UserName     string            `json:"user_name" db:"user_name"`
+	UserSurname  string            `json:"user_surname" db:"user_surname"`
+	EventId      int64             `json:"event_id" db:"event_id"`
+	EventTitle   string            `json:"event_title" db:"event_title"`
+	EventPoints  int               `json:"event_points" db:"event_points"`
+	Comment      string            `json:"comment" db:"comment"`
+	Status       string            `json:"status" db:"status" enums:"pending,approved,rejected,withdrawn"`
+	RejectReason *string           `json:"reject_reason,omitempty" db:"reject_reason"`
+	ReviewedBy   *int64            `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewedAt   *time.Time        `json:"reviewed_at,omitempty" db:"reviewed_at"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+	Attachments  []ClaimAttachment `json:"attachments" db:"-"`
+}
+
+type ClaimAttachmentInput struct {
+	Url  string `json:"url" binding:"required" example:"https://github.com/user/project"`
+	Name string `json:"name"`
+}
+
+type CreateClaimRequest struct {
+	EventId     int64                  `json:"event_id" binding:"required"`
+	Comment     string                 `json:"comment"`
+	Attachments []ClaimAttachmentInput `json:"attachments"`
+}
+
+type RejectClaimRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ClaimsFilter - условия выборки заявок. Пустые поля не фильтруют.
+type ClaimsFilter struct {
+	Status string
+	UserId int64
+	Limit  int
+	Offset int
+}
@@ -12,6 +12,8 @@ type Event struct {
 	EventDate     time.Time `json:"event_date" db:"event_date"`
 	CreatedAt     time.Time `json:"created" db:"created_at"`
 	Link          string    `json:"link" db:"link"`
+	EventState
+	RegistrationSettings
 }
 type UserCompletedEvent struct {
 	EventID       int64     `json:"event_id" db:"id"`
@@ -48,6 +50,12 @@ type CreateEventRequest struct {
 	IconUrl       string     `json:"icon_url"`
 	EventDate     *time.Time `json:"event_date"`
 	Link          string     `json:"link"`
+	// Status - draft или published, по умолчанию published
+	Status string `json:"status" enums:"draft,published"`
+	// Capacity - число мест, без него запись не ограничена
+	Capacity             *int       `json:"capacity"`
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at"`
 }
 type CreateEventResponse struct {
 	EventID       int64     `json:"event_id" db:"id"`
@@ -59,6 +67,28 @@ type CreateEventResponse struct {
 	EventDate     time.Time `json:"event_date"`
 	CreatedAt     time.Time `json:"created"`
 	Link          string    `json:"link"`
+	EventState
+	RegistrationSettings
+}
+
+// Статусы события. Студентам видны только опубликованные события.
+const (
+	EventStatusDraft     = "draft"     // черновик, виден только администраторам
+	EventStatusPublished = "published" // опубликовано
+	EventStatusArchived  = "archived"  // в архиве: запись и отметка закрыты
+)
+
+// EventState - статус события и отметка о мягком удалении.
+type EventState struct {
+	Status    string     `json:"status" db:"status" enums:"draft,published,archived"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
+}
+
+// RegistrationSettings - настройки записи на событие. Пустые значения - без ограничения.
+type RegistrationSettings struct {
+	Capacity             *int       `json:"capacity" db:"capacity"`
+	RegistrationOpensAt  *time.Time `json:"registration_opens_at" db:"registration_opens_at"`
+	RegistrationClosesAt *time.Time `json:"registration_closes_at" db:"registration_closes_at"`
 }
 
 type UpdateEventRequest struct {
@@ -71,6 +101,10 @@ type UpdateEventRequest struct {
 		IconUrl       string     `json:"icon_url,omitempty"`
 		EventDate     *time.Time `json:"event_date,omitempty"`
 		Link          string     `json:"link,omitempty"`
+		// Capacity 0 снимает ограничение мест. При увеличении мест ожидающие записываются автоматически
+		Capacity             *int       `json:"capacity,omitempty"`
+		RegistrationOpensAt  *time.Time `json:"registration_opens_at,omitempty"`
+		RegistrationClosesAt *time.Time `json:"registration_closes_at,omitempty"`
 	} `json:"new_data"`
 }
 
@@ -79,19 +113,115 @@ type CompleteUserEventRequest struct {
 	EventId int64 `json:"event_id"`
 }
 
-type CompletedEventsStats struct {
-	Hackathons int `json:"hackathons"`
-	Articles   int `json:"articles"`
-	Olympiads  int `json:"olympiads"`
-	Projects   int `json:"projects"`
+// EventType - тип мероприятия. Code 0 - "Неизвестно", его нельзя удалить.
+type EventType struct {
+	Id      int64  `json:"id" db:"id"`
+	Code    int    `json:"code" db:"code"`
+	Name    string `json:"name" db:"name"`
+	IconUrl string `json:"icon_url" db:"icon_url"`
+	Color   string `json:"color" db:"color" example:"#3F51B5"`
+}
+
+type CreateEventTypeRequest struct {
+	Code    int    `json:"code" binding:"required"`
+	Name    string `json:"name" binding:"required"`
+	IconUrl string `json:"icon_url"`
+	Color   string `json:"color" example:"#3F51B5"`
 }
 
+// UpdateEventTypeRequest - пустые поля не меняются. Код типа не меняется, на него ссылаются события.
+type UpdateEventTypeRequest struct {
+	Name    string `json:"name"`
+	IconUrl string `json:"icon_url"`
+	Color   string `json:"color" example:"#3F51B5"`
+}
+
+// CompletedEventsStat - количество выполненных мероприятий одного типа.
+type CompletedEventsStat struct {
+	EventType
+	Count int `json:"count" db:"count"`
+}
+
+// CompletedEventsFullResponse - выполненные мероприятия и статистика по всем существующим типам
+// (в том числе с нулевым количеством), упорядоченная по коду типа.
 type CompletedEventsFullResponse struct {
-	Events []UserCompletedEvent `json:"events"`
-	Stats  CompletedEventsStats `json:"stats"`
+	Events []UserCompletedEvent  `json:"events"`
+	Stats  []CompletedEventsStat `json:"stats"`
 }
 
 type CreateSuggestRequest struct {
 	EventId        int64 `json:"event_id" binding:"required"`
 	ExpiresAtHours int64 `json:"expires_at"`
 }
+
+// Сортировка поиска событий.
+const (
+	EventSortDate   = "date"
+	EventSortPoints = "points"
+)
+
+// EventSearchFilter - условия поиска событий. Пустые поля не фильтруют.
+type EventSearchFilter struct {
+	// Query - поисковый запрос по названию и описанию (синтаксис websearch: "фразы", -исключение, or)
+	Query         string
+	EventTypeCode *int
+	DateFrom      *time.Time
+	DateTo        *time.Time
+	PointsMin     *int
+	PointsMax     *int
+	// Statuses - допустимые статусы, пустой список - любые
+	Statuses []string
+	// Deleted - искать среди удаленных событий вместо действующих
+	Deleted bool
+	Sort    string
+	Desc    bool
+	Cursor  string
+	Limit   int
+	// After - позиция, после которой продолжается выдача. Заполняется из Cursor
+	After *EventCursor
+}
+
+// EventCursor - позиция в выдаче поиска: значение поля сортировки и id последнего события.
+type EventCursor struct {
+	Sort      string    `json:"s"`
+	Desc      bool      `json:"r,omitempty"`
+	EventDate time.Time `json:"d,omitempty"`
+	Points    int       `json:"p,omitempty"`
+	EventId   int64     `json:"id"`
+}
+
+// EventSearchResponse - страница результатов поиска. NextCursor пуст на последней странице.
+type EventSearchResponse struct {
+	Items      []Event `json:"items"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// Выборки каталога событий для студентов.
+const (
+	EventsUpcoming = "upcoming"
+	EventsPast     = "past"
+	EventsAll      = "all"
+)
+
+// EventUserStats - отметка о выполнении события текущим пользователем и число участников.
+type EventUserStats struct {
+	EventId           int64      `db:"event_id"`
+	CompletedAt       *time.Time `db:"completed_at"`
+	ParticipantsCount int        `db:"participants_count"`
+}
+
+// CatalogEvent - событие в каталоге для студента.
+type CatalogEvent struct {
+	Event
+	// Completed - событие уже засчитано текущему пользователю
+	Completed   bool       `json:"completed"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	// ParticipantsCount - скольким студентам засчитано событие
+	ParticipantsCount int `json:"participants_count"`
+}
+
+// EventCatalogResponse - страница каталога событий. NextCursor пуст на последней странице.
+type EventCatalogResponse struct {
+	Items      []CatalogEvent `json:"items"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// ImpersonationSession - вход разработчика от имени пользователя.
+type ImpersonationSession struct {
+	Id        int64      `json:"id" db:"id"`
+	ActorId   int64      `json:"actor_id" db:"actor_id"`
+	UserId    int64      `json:"user_id" db:"user_id"`
+	Reason    string     `json:"reason" db:"reason"`
+	ReadOnly  bool       `json:"read_only" db:"read_only"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	EndedAt   *time.Time `json:"ended_at" db:"ended_at"`
+}
+
+type StartImpersonationRequest struct {
+	Reason string `json:"reason" binding:"required"`
+	// ReadOnly - по умолчанию true; false разрешает изменения от имени пользователя
+	ReadOnly *bool `json:"read_only"`
+}
+
+type ImpersonationResponse struct {
+	AccessToken string               `json:"access_token"`
+	ExpUnix     int64                `json:"exp_unix"`
+	Session     ImpersonationSession `json:"session"`
+}
+
+// ImpersonationState - данные для проверки access токена, выданного для входа от имени пользователя.
+type ImpersonationState struct {
+	TokenVersion int64
+	Active       bool
+}
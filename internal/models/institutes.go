@@ -0,0 +1,63 @@
+package models
+
+// Institute - институт (факультет) с его направлениями подготовки.
+type Institute struct {
+	Id      int64   `json:"id" db:"id"`
+	Name    string  `json:"name" db:"name"`
+	Studies []Study `json:"studies,omitempty" db:"-"`
+}
+
+// Study - направление подготовки внутри института.
+type Study struct {
+	Id          int64          `json:"id" db:"id"`
+	Name        string         `json:"name" db:"name"`
+	InstituteId int64          `json:"institute_id" db:"institute_id"`
+	Groups      []StudentGroup `json:"groups,omitempty" db:"-"`
+}
+
+// StudentGroup - учебная группа. StudyId = 0 у групп, которым еще не назначено направление.
+type StudentGroup struct {
+	Id      int64  `json:"id" db:"id"`
+	Name    string `json:"name" db:"name"`
+	StudyId int64  `json:"study_id" db:"study_id"`
+}
+
+// InstitutesTree - вся структура институтов и группы без направления.
+type InstitutesTree struct {
+	Institutes []Institute    `json:"institutes"`
+	Unassigned []StudentGroup `json:"unassigned_groups"`
+}
+
+type InstituteRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+type CreateStudyRequest struct {
+	Name        string `json:"name" binding:"required"`
+	InstituteId int64  `json:"institute_id" binding:"required"`
+}
+
+// UpdateStudyRequest - пустые поля не меняются.
+type UpdateStudyRequest struct {
+	Name        string `json:"name"`
+	InstituteId int64  `json:"institute_id"`
+}
+
+type CreateGroupRequest struct {
+	Name    string `json:"name" binding:"required"`
+	StudyId int64  `json:"study_id"`
+}
+
+// UpdateGroupRequest - пустые поля не меняются.
+type UpdateGroupRequest struct {
+	Name    string `json:"name"`
+	StudyId int64  `json:"study_id"`
+}
+
+// LeaderboardFilter - ограничение лидерборда институтом, направлением или группой (0 - без ограничения).
+type LeaderboardFilter struct {
+	InstituteId    int64
+	StudyId        int64
+	StudentGroupId int64
+	Limit          int
+}
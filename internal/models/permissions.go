@@ -0,0 +1,47 @@
+package models
+
+// Права доступа. Набор прав роли хранится в role_permissions и попадает в access токен.
+const (
+	PermissionProfileRead          = "profile:read"
+	PermissionUsersRead            = "users:read"
+	PermissionUsersWrite           = "users:write"
+	PermissionUsersDelete          = "users:delete"
+	PermissionUsersManageRoles     = "users:manage_roles"
+	PermissionStudentsRead         = "students:read"
+	PermissionStudentsWrite        = "students:write"
+	PermissionEventsRead           = "events:read"
+	PermissionEventsWrite          = "events:write"
+	PermissionCompletedEventsRead  = "completed_events:read"
+	PermissionCompletedEventsWrite = "completed_events:write"
+	PermissionAuthLocksManage      = "auth_locks:manage"
+	PermissionRolesManage          = "roles:manage"
+	PermissionAuditRead            = "audit:read"
+	PermissionUsersImpersonate     = "users:impersonate"
+	PermissionImpersonationsManage = "impersonations:manage"
+	PermissionInstitutesWrite      = "institutes:write"
+	PermissionClaimsReview         = "claims:review"
+)
+
+type Permission struct {
+	Code        string `json:"code" db:"code"`
+	Description string `json:"description" db:"description"`
+}
+
+type Role struct {
+	Id          int64    `json:"id" db:"id"`
+	Code        string   `json:"code" db:"code"`
+	Name        string   `json:"name" db:"name"`
+	Level       int64    `json:"level" db:"level"`
+	Permissions []string `json:"permissions" db:"permissions"`
+}
+
+type CreateRoleRequest struct {
+	Code        string   `json:"code" binding:"required"`
+	Name        string   `json:"name" binding:"required"`
+	Level       int64    `json:"level" binding:"required"`
+	Permissions []string `json:"permissions"`
+}
+
+type SetRolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
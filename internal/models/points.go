@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// Причины изменения баллов в журнале.
+const (
+	PointsReasonEventCompleted = "event_completed"      // событие засчитано
+	PointsReasonEventRevoked   = "event_revoked"        // отметка о выполнении снята
+	PointsReasonEventRepriced  = "event_points_changed" // у события изменились баллы
+	PointsReasonManual         = "manual"               // ручное начисление или списание
+	PointsReasonReconcile      = "reconcile"            // исправление расхождения командой reconcile
+)
+
+// PointsTransaction - запись журнала баллов.
+type PointsTransaction struct {
+	Id         int64     `json:"id" db:"id"`
+	UserId     int64     `json:"user_id" db:"user_id"`
+	Delta      int       `json:"delta" db:"delta"`
+	Reason     string    `json:"reason" db:"reason" enums:"event_completed,event_revoked,event_points_changed,manual,reconcile"`
+	EventId    *int64    `json:"event_id,omitempty" db:"event_id"`
+	EventTitle *string   `json:"event_title,omitempty" db:"event_title"`
+	ActorId    int64     `json:"actor_id" db:"actor_id"`
+	Comment    string    `json:"comment" db:"comment"`
+	CreatedAt  time.Time `json:"created_at" db:"created_at"`
+}
+
+// NewPointsTransaction - новая запись журнала баллов.
+type NewPointsTransaction struct {
+	UserId  int64
+	Delta   int
+	Reason  string
+	EventId *int64
+	ActorId int64
+	Comment string
+}
+
+// PointsHistoryResponse - баланс пользователя и записи журнала, новые первыми.
+type PointsHistoryResponse struct {
+	TotalPoints  int64               `json:"total_points"`
+	Transactions []PointsTransaction `json:"transactions"`
+}
+
+// AdjustPointsRequest - ручное начисление (delta > 0) или списание (delta < 0) баллов.
+type AdjustPointsRequest struct {
+	Delta   int    `json:"delta" binding:"required"`
+	Comment string `json:"comment" binding:"required"`
+}
+
+// PointsDrift - расхождение баллов. Для событий - баллы пользователя за событие по журналу
+// против баллов события (0, если событие не засчитано). Для сумм - user_points против суммы журнала.
+type PointsDrift struct {
+	UserId   int64  `json:"user_id" db:"user_id"`
+	EventId  *int64 `json:"event_id,omitempty" db:"event_id"`
+	Expected int64  `json:"expected" db:"expected"`
+	Actual   int64  `json:"actual" db:"actual"`
+}
+
+// PointsReconcileReport - найденные расхождения. DryRun - только отчет, Applied - исправления записаны.
+type PointsReconcileReport struct {
+	DryRun     bool          `json:"dry_run"`
+	Applied    bool          `json:"applied"`
+	EventDrift []PointsDrift `json:"event_drift"`
+	TotalDrift []PointsDrift `json:"total_drift"`
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Статусы записи на событие.
+const (
+	RegistrationStatusRegistered = "registered" // место получено
+	RegistrationStatusWaitlisted = "waitlisted" // мест нет, студент в листе ожидания
+)
+
+// EventRegistration - запись текущего пользователя на событие.
+type EventRegistration struct {
+	EventId int64  `json:"event_id"`
+	Status  string `json:"status" enums:"registered,waitlisted"`
+	// WaitlistPosition - место в листе ожидания, начиная с 1. У записанных - 0
+	WaitlistPosition int        `json:"waitlist_position"`
+	RegisteredCount  int        `json:"registered_count"`
+	Capacity         *int       `json:"capacity"`
+	CreatedAt        time.Time  `json:"created_at"`
+	AttendedAt       *time.Time `json:"attended_at,omitempty"`
+}
+
+// RegistrationState - запись на событие в том виде, в каком она хранится.
+type RegistrationState struct {
+	EventId    int64      `db:"event_id"`
+	UserId     int64      `db:"user_id"`
+	Status     string     `db:"status"`
+	CreatedAt  time.Time  `db:"created_at"`
+	AttendedAt *time.Time `db:"attended_at"`
+}
+
+// EventLock - состояние события, строка которого заблокирована для изменения записей.
+type EventLock struct {
+	Status string `db:"status"`
+	RegistrationSettings
+}
+
+// Attendee - участник события в списке для администратора.
+type Attendee struct {
+	UserId           int64      `json:"user_id" db:"user_id"`
+	Name             string     `json:"name" db:"name"`
+	Surname          string     `json:"surname" db:"surname"`
+	Email            string     `json:"email" db:"email"`
+	Status           string     `json:"status" db:"status" enums:"registered,waitlisted"`
+	WaitlistPosition int        `json:"waitlist_position" db:"waitlist_position"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	AttendedAt       *time.Time `json:"attended_at,omitempty" db:"attended_at"`
+	Completed        bool       `json:"completed" db:"completed"`
+}
+
+// MarkAttendanceRequest - кто пришел на событие. All отмечает всех записанных (не из листа ожидания).
+type MarkAttendanceRequest struct {
+	UserIds []int64 `json:"user_ids"`
+	All     bool    `json:"all"`
+}
+
+// MarkAttendanceResponse - итог отметки посещения: кому засчитано событие и кто пропущен.
+type MarkAttendanceResponse struct {
+	Completed        []int64 `json:"completed"`
+	AlreadyCompleted []int64 `json:"already_completed"`
+	NotRegistered    []int64 `json:"not_registered"`
+}
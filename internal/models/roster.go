@@ -0,0 +1,79 @@
+package models
+
+// Результат проверки строки списка студентов.
+const (
+	RosterRowInsert    = "insert"    // студента нет, будет добавлен
+	RosterRowUpdate    = "update"    // студент есть, данные изменятся
+	RosterRowUnchanged = "unchanged" // студент есть, данные совпадают
+	RosterRowConflict  = "conflict"  // строку нельзя применить без ручной проверки
+	RosterRowInvalid   = "invalid"   // строка не прошла валидацию
+)
+
+// RosterRow - строка списка студентов после разбора и проверки.
+type RosterRow struct {
+	Line    int      `json:"line"` // номер строки в файле, начиная с 1 (заголовок - строка 1)
+	Status  string   `json:"status" enums:"insert,update,unchanged,conflict,invalid"`
+	BookId  int64    `json:"book_id,omitempty"`
+	Student *Student `json:"student,omitempty"`
+	Changes []string `json:"changes,omitempty"` // поля, которые изменятся при обновлении
+	Errors  []string `json:"errors,omitempty"`
+}
+
+type RosterImportSummary struct {
+	Total     int `json:"total"`
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Conflicts int `json:"conflicts"`
+	Invalid   int `json:"invalid"`
+}
+
+// RosterImportReport - построчный отчет об импорте списка студентов.
+type RosterImportReport struct {
+	DryRun  bool                `json:"dry_run"`
+	Applied bool                `json:"applied"`
+	Summary RosterImportSummary `json:"summary"`
+	Rows    []RosterRow         `json:"rows"`
+}
+
+// RosterStudent - студент из реестра вместе с признаком того, что по нему уже зарегистрирован аккаунт.
+type RosterStudent struct {
+	Student
+	BirthDateSet bool `db:"birth_date_set"` // false - в реестре дата рождения NULL
+	Registered   bool `db:"registered"`
+}
+
+// GroupMapping - перевод группы From в группу To при смене учебного года.
+type GroupMapping struct {
+	From string `json:"from" binding:"required" example:"ИВТ-21"`
+	To   string `json:"to" binding:"required" example:"ИВТ-31"`
+}
+
+// RolloverRequest - таблица перевода групп и список выпускающихся групп.
+type RolloverRequest struct {
+	Mappings         []GroupMapping `json:"mappings"`
+	GraduatingGroups []string       `json:"graduating_groups"`
+}
+
+// RolloverMappingResult - результат перевода одной группы.
+type RolloverMappingResult struct {
+	From         string `json:"from"`
+	To           string `json:"to"`
+	CreatedGroup bool   `json:"created_group"` // группы To не было, она создана на том же направлении, что и From
+	Students     int64  `json:"students"`
+	Users        int64  `json:"users"`
+}
+
+type RolloverGraduation struct {
+	Groups   []string `json:"groups"`
+	Students int64    `json:"students"`
+	Users    int64    `json:"users"`
+}
+
+// RolloverReport - отчет о переходе на новый учебный год.
+type RolloverReport struct {
+	DryRun     bool                    `json:"dry_run"`
+	Applied    bool                    `json:"applied"`
+	Mappings   []RolloverMappingResult `json:"mappings"`
+	Graduation RolloverGraduation      `json:"graduation"`
+}
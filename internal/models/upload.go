@@ -0,0 +1,10 @@
+package models
+
+// UploadedFile - загруженный файл. Url постоянный: его можно сохранять в icon_url, avatar
+// или в подтверждения заявки.
+type UploadedFile struct {
+	Url         string `json:"url" example:"/uploads/evidence/3f2a9c1e.pdf"`
+	Name        string `json:"name" example:"certificate.pdf"`
+	ContentType string `json:"content_type" example:"application/pdf"`
+	Size        int64  `json:"size" example:"183204"`
+}
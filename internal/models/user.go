@@ -3,36 +3,62 @@ package models
 import "time"
 
 type Student struct {
-	Id           int64     `json:"id"`
-	BookId       int64     `json:"book_id"`
-	Surname      string    `json:"surname"`
-	Name         string    `json:"name"`
-	MiddleName   string    `json:"middle_name"`
-	BirthDate    time.Time `json:"birth_date"`
-	StudentGroup string    `json:"student_group" db:"student_group"`
+	Id         int64     `json:"id"`
+	BookId     int64     `json:"book_id"`
+	Surname    string    `json:"surname"`
+	Name       string    `json:"name"`
+	MiddleName string    `json:"middle_name"`
+	BirthDate  time.Time `json:"birth_date"`
+	// StudentGroupId - ссылка на student_groups, StudentGroup - название группы
+	StudentGroupId int64  `json:"student_group_id" db:"student_group_id"`
+	StudentGroup   string `json:"student_group" db:"student_group"`
+	// ArchivedAt - когда студент выпустился, nil у действующих студентов
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
 }
 
-type User struct {
-	Id           int64     `json:"id" db:"id"`
-	BookId       int64     `json:"book_id" db:"book_id"`
-	Name         string    `json:"name" db:"name"`
-	Surname      string    `json:"surname" db:"surname"`
-	MiddleName   string    `json:"middle_name" db:"middle_name"`
-	BirthDate    time.Time `json:"birth_date" db:"birth_date"`
-	StudentGroup string    `json:"student_group" db:"student_group"`
-	Password     []byte    `json:"password" db:"password"`
-	Email        string    `json:"email" db:"email"`
-	RoleLevel    int64     `json:"role_level" db:"role_level"`
-	Avatar       string    `json:"avatar" db:"avatar"`
+type CreateStudentRequest struct {
+	BookId       int64  `json:"book_id" binding:"required"`
+	Surname      string `json:"surname" binding:"required"`
+	Name         string `json:"name" binding:"required"`
+	MiddleName   string `json:"middle_name"`
+	BirthDate    string `json:"birth_date" binding:"required" example:"2003-02-01"`
+	StudentGroup string `json:"student_group" binding:"required"`
 }
-type UserSubstructure struct {
-	ID           int64  `json:"id"`
+
+// UpdateStudentRequest - новые значения полей студента, пустые поля не меняются.
+type UpdateStudentRequest struct {
 	BookId       int64  `json:"book_id"`
-	Email        string `json:"email"`
-	FirstName    string `json:"first_name"`
-	RoleLevel    int64  `json:"role_level"`
+	Surname      string `json:"surname"`
+	Name         string `json:"name"`
+	MiddleName   string `json:"middle_name"`
+	BirthDate    string `json:"birth_date" example:"2003-02-01"`
 	StudentGroup string `json:"student_group"`
-	Avatar       string `json:"avatar"`
+}
+
+type User struct {
+	Id             int64     `json:"id" db:"id"`
+	BookId         int64     `json:"book_id" db:"book_id"`
+	Name           string    `json:"name" db:"name"`
+	Surname        string    `json:"surname" db:"surname"`
+	MiddleName     string    `json:"middle_name" db:"middle_name"`
+	BirthDate      time.Time `json:"birth_date" db:"birth_date"`
+	StudentGroupId int64     `json:"student_group_id" db:"student_group_id"`
+	StudentGroup   string    `json:"student_group" db:"student_group"`
+	Password       []byte    `json:"password" db:"password"`
+	Email          string    `json:"email" db:"email"`
+	RoleLevel      int64     `json:"role_level" db:"role_level"`
+	Avatar         string    `json:"avatar" db:"avatar"`
+	EmailVerified  bool      `json:"email_verified" db:"email_verified"`
+}
+type UserSubstructure struct {
+	ID            int64  `json:"id"`
+	BookId        int64  `json:"book_id"`
+	Email         string `json:"email"`
+	FirstName     string `json:"first_name"`
+	RoleLevel     int64  `json:"role_level"`
+	StudentGroup  string `json:"student_group"`
+	Avatar        string `json:"avatar"`
+	EmailVerified bool   `json:"email_verified"`
 }
 
 type ProfileResponse struct {
@@ -42,10 +68,21 @@ type ProfileResponse struct {
 	MiddleName   string    `json:"middle_name"`
 	BirthDate    time.Time `json:"birth_date"`
 	StudentGroup string    `json:"student_group"`
-	Email        string    `json:"email"`
-	RoleLevel    int64     `json:"role_level"`
-	TotalPoints  int64     `json:"total_points"`
-	Avatar       string    `json:"avatar"`
+	// место в структуре университета, 0 и пустая строка - не задано
+	StudentGroupId int64  `json:"student_group_id" db:"student_group_id"`
+	StudyId        int64  `json:"study_id" db:"study_id"`
+	Study          string `json:"study" db:"study"`
+	InstituteId    int64  `json:"institute_id" db:"institute_id"`
+	Institute      string `json:"institute" db:"institute"`
+	Email          string `json:"email"`
+	EmailVerified  bool   `json:"email_verified" db:"email_verified"`
+	RoleLevel      int64  `json:"role_level"`
+	TotalPoints    int64  `json:"total_points"`
+	Avatar         string `json:"avatar"`
+	// DeletionScheduledAt - когда аккаунт будет удален, если пользователь не отменит удаление
+	DeletionScheduledAt *time.Time `json:"deletion_scheduled_at,omitempty" db:"deletion_scheduled_at"`
+	// ArchivedAt - когда пользователь выпустился; выпускники не участвуют в лидерборде
+	ArchivedAt *time.Time `json:"archived_at,omitempty" db:"archived_at"`
 }
 
 type DeleteUserRequest struct {
@@ -62,9 +99,11 @@ type UserUpdateData struct {
 	Surname      string `json:"surname,omitempty"`
 	MiddleName   string `json:"middle_name,omitempty"`
 	StudentGroup string `json:"student_group,omitempty"`
-	Email        string `json:"email,omitempty"`
-	RoleLevel    int64  `json:"role_level,omitempty"`
-	Avatar       string `json:"avatar,omitempty"`
+	// StudentGroupId - группа по id; если задано только название StudentGroup, группа ищется по нему
+	StudentGroupId int64  `json:"student_group_id,omitempty"`
+	Email          string `json:"email,omitempty"`
+	RoleLevel      int64  `json:"role_level,omitempty"`
+	Avatar         string `json:"avatar,omitempty"`
 }
 type UpdateUserRequest struct {
 	UserId  int64          `json:"user_id"`
@@ -76,6 +115,45 @@ type UpdateUserResponse struct {
 	New        UserUpdateData `json:"new_data"`
 }
 
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+type ChangeEmailRequest struct {
+	Password string `json:"password" binding:"required"`
+	NewEmail string `json:"new_email" binding:"required,email"`
+}
+
+// UpdateProfileRequest - поля профиля, которые пользователь может менять сам.
+// ФИО, группа и номер книжки берутся из реестра студентов и меняются только администратором.
+type UpdateProfileRequest struct {
+	Avatar string `json:"avatar" binding:"required"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+type DeleteAccountResponse struct {
+	Successful          bool      `json:"successful"`
+	DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+}
+
+// PointsHistoryEntry - запись журнала баллов и сумма баллов после нее.
+type PointsHistoryEntry struct {
+	PointsTransaction
+	TotalPoints int64 `json:"total_points"`
+}
+
+// AccountExport - все данные, которые сервис хранит о пользователе.
+type AccountExport struct {
+	ExportedAt      time.Time                   `json:"exported_at"`
+	Profile         ProfileResponse             `json:"profile"`
+	CompletedEvents CompletedEventsFullResponse `json:"completed_events"`
+	PointsHistory   []PointsHistoryEntry        `json:"points_history"`
+	Sessions        []Session                   `json:"sessions"`
+}
+
 type UserRating struct {
 	UserId   int64  `json:"user_id"`
 	Points   int64  `json:"points"`
@@ -87,5 +165,6 @@ type UserWithPoints struct {
 	Name        string `json:"name"`
 	Surname     string `json:"surname"`
 	TotalPoints int64  `json:"total_points"`
+	Avatar      string `json:"avatar"`
 	Position    int64  `json:"position"`
 }
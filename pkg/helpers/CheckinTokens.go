@@ -0,0 +1,88 @@
+package helpers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkinTokenVersion - префикс формата токена отметки. Меняется при изменении формата.
+const checkinTokenVersion = "c1"
+
+var ErrMalformedCheckinToken = errors.New("malformed checkin token")
+
+// CheckinToken - разобранный токен отметки на событии (содержимое QR кода).
+// Формат: c1.{event_id}.{exp_unix}.{nonce}.{hmac}, подпись - HMAC-SHA256 секретом события.
+type CheckinToken struct {
+	EventId   int64
+	ExpiresAt time.Time
+	Nonce     string
+	signed    string
+	signature []byte
+}
+
+// NewCheckinSecret генерирует секрет события для подписи токенов отметки.
+func NewCheckinSecret() ([]byte, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// IssueCheckinToken подписывает новый токен отметки. Каждый токен уникален за счет случайного nonce.
+func IssueCheckinToken(secret []byte, eventId int64, expiresAt time.Time) (string, error) {
+	nonce, err := GenerateTokenRaw(12)
+	if err != nil {
+		return "", err
+	}
+
+	signed := fmt.Sprintf("%s.%d.%d.%s", checkinTokenVersion, eventId, expiresAt.Unix(), nonce)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signCheckin(secret, signed)), nil
+}
+
+// ParseCheckinToken разбирает токен без проверки подписи: секрет события известен только после чтения event_id.
+func ParseCheckinToken(token string) (CheckinToken, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 5 || parts[0] != checkinTokenVersion {
+		return CheckinToken{}, ErrMalformedCheckinToken
+	}
+
+	eventId, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return CheckinToken{}, ErrMalformedCheckinToken
+	}
+	exp, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return CheckinToken{}, ErrMalformedCheckinToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[4])
+	if err != nil {
+		return CheckinToken{}, ErrMalformedCheckinToken
+	}
+
+	return CheckinToken{
+		EventId:   eventId,
+		ExpiresAt: time.Unix(exp, 0),
+		Nonce:     parts[3],
+		signed:    strings.Join(parts[:4], "."),
+		signature: signature,
+	}, nil
+}
+
+// Verify проверяет подпись токена секретом события (сравнение за постоянное время).
+func (t CheckinToken) Verify(secret []byte) bool {
+	return len(secret) > 0 && hmac.Equal(t.signature, signCheckin(secret, t.signed))
+}
+
+func signCheckin(secret []byte, data string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,323 @@
+package helpers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedSpreadsheet = errors.New("unsupported spreadsheet format")
+	ErrEmptySpreadsheet       = errors.New("spreadsheet has no rows")
+	ErrSpreadsheetTooLarge    = errors.New("spreadsheet exceeds row or column limit")
+)
+
+// xlsxMaxColumns - число колонок листа Excel (последняя - XFD).
+const xlsxMaxColumns = 16384
+
+// ReadSpreadsheet читает таблицу из CSV или XLSX (по расширению файла) и возвращает строки как массивы ячеек.
+// Пустые строки в конце не возвращаются. maxRows ограничивает номера строк листа XLSX:
+// пропущенные строки восстанавливаются по номеру, и без ограничения один номер вроде r="2000000000"
+// заставил бы выделить память под миллиарды строк.
+func ReadSpreadsheet(filename string, data []byte, maxRows int) ([][]string, error) {
+	var (
+		rows [][]string
+		err  error
+	)
+
+	switch strings.ToLower(path.Ext(filename)) {
+	case ".csv":
+		rows, err = ReadCSV(data)
+	case ".xlsx":
+		rows, err = ReadXLSX(data, maxRows)
+	default:
+		return nil, ErrUnsupportedSpreadsheet
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for len(rows) > 0 && isEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	if len(rows) == 0 {
+		return nil, ErrEmptySpreadsheet
+	}
+
+	return rows, nil
+}
+
+// ReadCSV читает CSV. Разделитель (',' или ';' - так сохраняет Excel с русской локалью)
+// определяется по первой строке, BOM в начале файла пропускается.
+func ReadCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		r.Comma = ';'
+	}
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("could not read csv: %w", err)
+	}
+
+	return rows, nil
+}
+
+// ReadXLSX читает первый лист книги XLSX. Числа возвращаются как есть (даты - серийным номером Excel,
+// см. ExcelSerialToTime), строки из общей таблицы строк подставляются.
+// Строка с номером больше maxRows или ячейка правее колонки XFD - ErrSpreadsheetTooLarge.
+func ReadXLSX(data []byte, maxRows int) ([][]string, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("could not open xlsx: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := xlsxFirstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedStrings []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if sharedStrings, err = xlsxSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+
+	sheetFile, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("could not read xlsx: sheet %s not found", sheetPath)
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Index int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string `xml:"r,attr"`
+				Type   string `xml:"t,attr"`
+				Value  string `xml:"v"`
+				Inline struct {
+					Text string `xml:",innerxml"`
+				} `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err = xlsxDecode(sheetFile, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, row := range sheet.Rows {
+		if row.Index > maxRows {
+			return nil, fmt.Errorf("%w: row %d, limit %d", ErrSpreadsheetTooLarge, row.Index, maxRows)
+		}
+
+		// пропущенные пустые строки в XML не записываются, восстанавливаем их по номеру
+		for row.Index > len(rows)+1 {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for i, cell := range row.Cells {
+			col := i
+			if cell.Ref != "" {
+				col = xlsxColumnIndex(cell.Ref)
+			}
+			if col >= xlsxMaxColumns {
+				return nil, fmt.Errorf("%w: cell %.16s", ErrSpreadsheetTooLarge, cell.Ref)
+			}
+			for len(cells) < col {
+				cells = append(cells, "")
+			}
+
+			var value string
+			switch cell.Type {
+			case "s":
+				idx, err := strconv.Atoi(cell.Value)
+				if err != nil || idx < 0 || idx >= len(sharedStrings) {
+					return nil, fmt.Errorf("could not read xlsx: bad shared string in %s", cell.Ref)
+				}
+				value = sharedStrings[idx]
+			case "inlineStr":
+				value = xlsxText(cell.Inline.Text)
+			default:
+				value = cell.Value
+			}
+			cells = append(cells, strings.TrimSpace(value))
+		}
+		rows = append(rows, cells)
+	}
+
+	return rows, nil
+}
+
+// ExcelSerialToTime переводит серийный номер даты Excel (дни с 30.12.1899) в дату.
+func ExcelSerialToTime(serial float64) time.Time {
+	base := time.Date(1899, time.December, 30, 0, 0, 0, 0, time.UTC)
+	return base.Add(time.Duration(serial * float64(24*time.Hour))).Truncate(24 * time.Hour)
+}
+
+// xlsxFirstSheetPath находит файл первого листа по workbook.xml и его связям.
+func xlsxFirstSheetPath(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	workbookFile, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("could not read xlsx: workbook.xml not found")
+	}
+	relsFile, ok := files["xl/_rels/workbook.xml.rels"]
+	if !ok {
+		return fallback, nil
+	}
+
+	var workbook struct {
+		Sheets []struct {
+			RelId string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xlsxDecode(workbookFile, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", ErrEmptySpreadsheet
+	}
+
+	var rels struct {
+		Relationships []struct {
+			Id     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := xlsxDecode(relsFile, &rels); err != nil {
+		return "", err
+	}
+
+	for _, rel := range rels.Relationships {
+		if rel.Id != workbook.Sheets[0].RelId {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+
+	return fallback, nil
+}
+
+// xlsxSharedStrings читает общую таблицу строк книги.
+func xlsxSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []struct {
+			Text string `xml:",innerxml"`
+		} `xml:"si"`
+	}
+	if err := xlsxDecode(f, &sst); err != nil {
+		return nil, err
+	}
+
+	result := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		result[i] = xlsxText(item.Text)
+	}
+
+	return result, nil
+}
+
+// xlsxText собирает текст строки: простой <t> или несколько форматированных фрагментов <r><t>.
+// Фонетические подсказки <rPh> пропускаются.
+func xlsxText(inner string) string {
+	dec := xml.NewDecoder(strings.NewReader(inner))
+
+	var (
+		sb     strings.Builder
+		inText bool
+		skip   int
+	)
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "rPh":
+				skip++
+			case "t":
+				inText = skip == 0
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "rPh":
+				skip--
+			case "t":
+				inText = false
+			}
+		case xml.CharData:
+			if inText {
+				sb.Write(t)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// xlsxColumnIndex возвращает индекс колонки (с нуля) по ссылке на ячейку, например "C12" -> 2.
+// Для ссылок правее XFD возвращает xlsxMaxColumns, не дожидаясь переполнения.
+func xlsxColumnIndex(ref string) int {
+	col := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		if col > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return col - 1
+}
+
+func xlsxDecode(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("could not open %s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	if err = xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("could not parse %s: %w", f.Name, err)
+	}
+
+	return nil
+}
+
+func isEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -4,6 +4,8 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"time"
 )
@@ -23,34 +25,104 @@ func HashToken(token string) []byte {
 	return sum[:]
 }
 
+var (
+	ErrUnknownSigningKey       = errors.New("unknown signing key")
+	ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
+)
+
+// JWTMaker подписывает access токены активным ключом и проверяет их любым из известных ключей.
+// Ключ выбирается по заголовку kid, поэтому после ротации токены, подписанные старым ключом,
+// остаются валидными, пока этот ключ не удален из набора.
 type JWTMaker struct {
-	secret   []byte
+	signing  *SigningKey
+	keys     map[string]*SigningKey
 	lifetime time.Duration
 }
 
-func NewJWTMaker(secret []byte, lifetime time.Duration) *JWTMaker {
-	return &JWTMaker{secret: secret, lifetime: lifetime}
+// NewJWTMaker создает JWTMaker. activeKid - ключ, которым подписываются новые токены,
+// он должен содержать приватную часть.
+func NewJWTMaker(keys []*SigningKey, activeKid string, lifetime time.Duration) (*JWTMaker, error) {
+	m := &JWTMaker{
+		keys:     make(map[string]*SigningKey, len(keys)),
+		lifetime: lifetime,
+	}
+
+	for _, key := range keys {
+		if _, ok := m.keys[key.Kid]; ok {
+			return nil, fmt.Errorf("duplicate signing key kid %q", key.Kid)
+		}
+		m.keys[key.Kid] = key
+	}
+
+	active, ok := m.keys[activeKid]
+	if !ok {
+		return nil, fmt.Errorf("active signing key %q not found", activeKid)
+	}
+	if active.Private == nil {
+		return nil, fmt.Errorf("active signing key %q has no private key", activeKid)
+	}
+	m.signing = active
+
+	return m, nil
+}
+
+// AccessClaims - данные, которые кладутся в access токен.
+type AccessClaims struct {
+	UserID       int64
+	RoleLevel    int64
+	Permissions  []string // права роли на момент выдачи
+	SessionID    int64    // семейство refresh токенов, из которого выдан токен
+	TokenVersion int64    // users.token_version на момент выдачи
+	ActorID      int64    // кто вошел от имени пользователя (0 - обычный токен)
+	ReadOnly     bool     // токен разрешает только чтение
 }
 
-func (m *JWTMaker) Issue(userID, roleLevel int64) (token string, exp int64, err error) {
+// Lifetime - время жизни выдаваемых access токенов.
+func (m *JWTMaker) Lifetime() time.Duration {
+	return m.lifetime
+}
+
+func (m *JWTMaker) Issue(c AccessClaims) (token string, exp int64, err error) {
 	exp = time.Now().Add(m.lifetime).Unix()
 
 	claims := jwt.MapClaims{
-		"sub":       userID, // кто
-		"roleLevel": roleLevel,
+		"sub":       c.UserID,    // кто
+		"sid":       c.SessionID, // с какой сессии (refresh токена)
+		"ver":       c.TokenVersion,
+		"roleLevel": c.RoleLevel,
+		"perms":     c.Permissions,
 		"exp":       exp, // срок
 		"iat":       time.Now().Unix(),
 	}
+	if c.ActorID != 0 {
+		claims["act"] = map[string]any{"sub": c.ActorID}
+	}
+	if c.ReadOnly {
+		claims["ro"] = true
+	}
 
-	j := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	token, err = j.SignedString(m.secret)
+	j := jwt.NewWithClaims(m.signing.Method, claims)
+	j.Header["kid"] = m.signing.Kid
+	token, err = j.SignedString(m.signing.Private)
 	return
 }
 
 func (m *JWTMaker) Verify(token string) (jwt.MapClaims, error) {
 	t, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		return m.secret, nil
-	})
+		kid, _ := t.Header["kid"].(string)
+		key, ok := m.keys[kid]
+		if !ok {
+			return nil, ErrUnknownSigningKey
+		}
+		// алгоритм определяется ключом, а не заголовком токена
+		if t.Method.Alg() != key.Method.Alg() {
+			return nil, ErrUnexpectedSigningMethod
+		}
+		return key.Public, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,32 @@ CREATE TABLE IF NOT EXISTS roles (
                                      name TEXT NOT NULL,
                                      level INT unique NOT NULL CHECK (level > 0)
 );
+CREATE TABLE IF NOT EXISTS permissions (
+    code TEXT PRIMARY KEY,                 -- 'events:write', 'users:delete', ...
+    description TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_level INT NOT NULL REFERENCES roles(level) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission_code TEXT NOT NULL REFERENCES permissions(code) ON DELETE CASCADE,
+    PRIMARY KEY (role_level, permission_code)
+);
+CREATE TABLE IF NOT EXISTS institutes (
+                                          id serial primary key,
+                                          name text unique not null
+);
+CREATE TABLE IF NOT EXISTS studies (
+                                       id serial primary key,
+                                       name text unique not null,
+                                       institute_id int references institutes(id) ON DELETE CASCADE,
+                                       FOREIGN KEY (institute_id) REFERENCES institutes(id) ON DELETE CASCADE
+);
+CREATE TABLE IF NOT EXISTS student_groups (
+    id serial primary key,
+    name text unique not null,
+    studies_id int references studies(id) on DELETE CASCADE,  -- NULL у групп, перенесенных из текстовых полей и еще не распределенных
+    FOREIGN KEY (studies_id) references studies(id) ON DELETE CASCADE
+
+);
 CREATE TABLE IF NOT EXISTS users (
                        id serial primary key,
                        book_id int unique,
@@ -11,10 +37,16 @@ CREATE TABLE IF NOT EXISTS users (
                        name text not null,
                        middle_name text not null,
                        birth_date date,
-                       student_group text,
+                       student_group_id int REFERENCES student_groups(id),
+                       archived_at timestamptz,  -- выпускник: не участвует в лидерборде, история сохраняется
                        password bytea,
                        email text not null,
-                       role_level int not null REFERENCES roles(level)
+                       email_verified_at timestamptz,  -- NULL, пока владелец почты не подтвердил ее по ссылке из письма
+                       role_level int not null REFERENCES roles(level),
+                       avatar text not null default '',
+                       token_version int not null default 0, -- увеличивается, чтобы отозвать выданные access токены
+                       deletion_scheduled_at timestamptz,     -- когда аккаунт будет анонимизирован по запросу пользователя
+                       deleted_at timestamptz                 -- когда персональные данные были удалены
 );
 CREATE TABLE IF NOT EXISTS students (
                           id serial primary key,
@@ -23,7 +55,8 @@ CREATE TABLE IF NOT EXISTS students (
                           name text not null,
                           middle_name text not null,
                           birth_date date,
-                          student_group text not null
+                          student_group_id int REFERENCES student_groups(id),
+                          archived_at timestamptz  -- выпускник, группа больше не переводится
 );
 CREATE TABLE IF NOT EXISTS link_tokens (
                              book_id     INT NOT NULL REFERENCES students(book_id) ON DELETE CASCADE,
@@ -39,6 +72,14 @@ CREATE TABLE IF NOT EXISTS refresh_tokens (
     token_hash BYTEA NOT NULL,
     expires_at TIMESTAMPTZ NOT NULL DEFAULT now() + INTERVAL '30 days',
     created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    device_name TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    ip TEXT NOT NULL DEFAULT '',
+    last_used_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    family_id INT NOT NULL,             -- id первого токена цепочки ротаций (= сессия)
+    parent_id INT,                      -- токен, из которого получен этот при ротации
+    rotated_at TIMESTAMPTZ,             -- когда токен был обменян на новый
+    revoked_at TIMESTAMPTZ,             -- когда цепочка была отозвана
     FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 );
 CREATE TABLE IF NOT EXISTS reset_password_tokens (
@@ -49,28 +90,72 @@ CREATE TABLE IF NOT EXISTS reset_password_tokens (
     created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
     PRIMARY KEY (user_id)
 );
-CREATE TABLE IF NOT EXISTS institutes (
-                                          id serial primary key,
-                                          name text unique not null
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,                 -- почта, которую подтверждает токен
+    token_hash BYTEA NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id)
 );
-CREATE TABLE IF NOT EXISTS studies (
-                                       id serial primary key,
-                                       name text unique not null,
-                                       institute_id int references institutes(id) ON DELETE CASCADE,
-                                       FOREIGN KEY (institute_id) REFERENCES institutes(id) ON DELETE CASCADE
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMPTZ,                      -- NULL, пока привязка не подтверждена кодом
+    last_used_step BIGINT NOT NULL DEFAULT 0,    -- последний принятый интервал TOTP (защита от повтора кода)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
 );
-CREATE TABLE IF NOT EXISTS student_groups (
-    id serial primary key,
-    name text unique not null,
-    studies_id int references studies(id) on DELETE CASCADE,
-    FOREIGN KEY (studies_id) references studies(id) ON DELETE CASCADE
-
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash BYTEA NOT NULL,
+    used_at TIMESTAMPTZ
+);
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+    token_hash BYTEA PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS auth_throttle (
+    scope TEXT NOT NULL,                -- 'login', 'check', 'reset_password'
+    key_type TEXT NOT NULL,             -- 'ip' или 'account'
+    key TEXT NOT NULL,                  -- IP адрес, почта или номер студенческого
+    failures INT NOT NULL DEFAULT 0,    -- попыток в текущем окне
+    window_started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    lockouts INT NOT NULL DEFAULT 0,    -- сколько раз подряд ключ блокировался (для прогрессивной блокировки)
+    locked_until TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (scope, key_type, key)
+);
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT NOT NULL,              -- кто выполнил действие (без FK, чтобы запись пережила удаление админа)
+    action TEXT NOT NULL,               -- 'event.update', 'completed_event.add', ...
+    target_type TEXT NOT NULL,          -- 'event', 'user', 'role', ...
+    target_id TEXT NOT NULL,
+    before JSONB,                       -- состояние до изменения
+    after JSONB,                        -- состояние после изменения
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT NOT NULL,              -- разработчик, который вошел от имени пользователя (без FK, как в audit_log)
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    read_only BOOLEAN NOT NULL DEFAULT true,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,    -- совпадает со сроком жизни выданного access токена
+    ended_at TIMESTAMPTZ                -- NULL, пока сессию не завершили вручную
 );
 
 CREATE TABLE IF NOT EXISTS events_types (
     id serial primary key,
-    code int unique not null,
-    name text not null
+    code int unique not null,     -- 0 - "Неизвестно", на него переходят события удаленного типа
+    name text not null,
+    icon_url text not null default '',
+    color text not null default '#9E9E9E'  -- #RRGGBB
 );
 
 
@@ -79,22 +164,75 @@ CREATE TABLE IF NOT EXISTS events (
                                       event_type_code int default 0 references events_types(code) on DELETE SET DEFAULT,
                                       title text unique not null,
                                       description text default 'Empty description',
-                                      points int default 100,
+                                      points int not null default 100,
                                       icon_url text default 'https://09edcbd14ce2e9c5981946024728da15.bckt.ru/testIcons/star.webp',
-                                      event_date timestamptz default '1970-01-01T00:00:00Z',
-                                      created_at timestamptz default now()
+                                      event_date timestamptz not null default '1970-01-01T00:00:00Z',
+                                      created_at timestamptz default now(),
+                                      capacity int check (capacity > 0),     -- NULL - без ограничения мест
+                                      registration_opens_at timestamptz,     -- NULL - запись открыта сразу
+                                      registration_closes_at timestamptz,    -- NULL - запись не закрывается
+                                      status text not null default 'published'
+                                          check (status in ('draft', 'published', 'archived')),
+                                      deleted_at timestamptz,                -- мягкое удаление, событие можно восстановить
+                                      -- полнотекстовый поиск: название важнее описания
+                                      search_vector tsvector generated always as (
+                                          setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+                                          setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+                                      ) stored
 );
 CREATE TABLE IF NOT EXISTS completed_events (
     user_id int references users(id) on DELETE CASCADE,
-    event_id int references events(id) on DELETE CASCADE,
+    event_id int references events(id) on DELETE RESTRICT,   -- события удаляются мягко, баллы за них не теряются
     completed_at timestamptz default now(),
     PRIMARY KEY (user_id, event_id)
 );
+CREATE TABLE IF NOT EXISTS claims (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    comment TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    reject_reason TEXT,                 -- причина отказа, заполняется при status = 'rejected'
+    reviewed_by INT,                    -- кто рассмотрел заявку (без FK, как в audit_log)
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS claim_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    claim_id BIGINT NOT NULL REFERENCES claims(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS event_registrations (
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('registered', 'waitlisted')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),  -- порядок в листе ожидания
+    attended_at TIMESTAMPTZ,                        -- посещение отмечено, событие засчитано
+    PRIMARY KEY (event_id, user_id)
+);
+CREATE TABLE IF NOT EXISTS event_checkin_settings (
+    event_id INT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    opens_at TIMESTAMPTZ NOT NULL,
+    closes_at TIMESTAMPTZ NOT NULL,
+    secret BYTEA NOT NULL,                          -- ключ подписи QR токенов, смена отзывает выданные токены
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (closes_at > opens_at)
+);
+CREATE TABLE IF NOT EXISTS event_checkins (
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_nonce TEXT NOT NULL,                      -- какой токен использован для отметки
+    checked_in_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id)
+);
 CREATE TABLE IF NOT EXISTS suggest_events (
     event_id int references events(id) on DELETE CASCADE,
     created_at timestamptz not null default now(),
     expires_at timestamptz not null DEFAULT now() + INTERVAL '7 days'
 );
+-- кэш суммы points_transactions по пользователю, меняется только вместе с журналом баллов
 CREATE TABLE IF NOT EXISTS user_points (
     user_id int primary key,
     total_points int not null default 0,
@@ -103,19 +241,108 @@ CREATE TABLE IF NOT EXISTS user_points (
             REFERENCES users(id)
             ON DELETE CASCADE
 );
+-- журнал баллов: строки только добавляются, баланс - сумма delta
+CREATE TABLE IF NOT EXISTS points_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delta INT NOT NULL,
+    reason TEXT NOT NULL
+        CHECK (reason IN ('event_completed', 'event_revoked', 'event_points_changed', 'manual', 'reconcile')),
+    event_id INT REFERENCES events(id) ON DELETE RESTRICT,  -- NULL у ручных начислений
+    actor_id INT NOT NULL,              -- кто изменил баллы, 0 - система (без FK, как в audit_log)
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
 
 INSERT INTO roles (code, name, level) VALUES
                                           ('student', 'Студент', 10),
                                           ('admin', 'Администратор', 50),
                                           ('developer', 'Разработчик', 100);
 
-INSERT into events_types (code, name) VALUES
-                                          (1, 'Хакатон'),
-                                          (2, 'Статья'),
-                                          (3, 'Олимпиада'),
-                                          (4, 'Проект'),
-                                          (0, 'Неизвестно');
+INSERT INTO permissions (code, description) VALUES
+                                          ('profile:read', 'Личный кабинет (/me)'),
+                                          ('users:read', 'Просмотр пользователей'),
+                                          ('users:write', 'Изменение данных пользователей'),
+                                          ('users:delete', 'Удаление пользователей'),
+                                          ('users:manage_roles', 'Назначение ролей пользователям'),
+                                          ('students:read', 'Просмотр реестра студентов'),
+                                          ('students:write', 'Загрузка и изменение реестра студентов'),
+                                          ('institutes:write', 'Изменение институтов, направлений и групп'),
+                                          ('events:read', 'Просмотр мероприятий'),
+                                          ('events:write', 'Создание, изменение и удаление мероприятий'),
+                                          ('completed_events:read', 'Просмотр выполненных мероприятий'),
+                                          ('completed_events:write', 'Начисление и отмена выполненных мероприятий'),
+                                          ('auth_locks:manage', 'Просмотр и снятие блокировок входа'),
+                                          ('roles:manage', 'Создание ролей и настройка их прав'),
+                                          ('audit:read', 'Просмотр журнала действий администраторов'),
+                                          ('users:impersonate', 'Вход от имени пользователя'),
+                                          ('impersonations:manage', 'Завершение чужих сессий входа от имени пользователя'),
+                                          ('claims:review', 'Рассмотрение заявок студентов на баллы');
+
+-- студент получает только личный кабинет, администратор - все права, кроме управления ролями и входа
+-- от имени пользователя, разработчик - все, кроме завершения чужих сессий входа от имени пользователя:
+-- это право выдается роли явно
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+                                          (10, 'profile:read');
+INSERT INTO role_permissions (role_level, permission_code)
+SELECT 50, code FROM permissions WHERE code NOT IN ('roles:manage', 'users:impersonate', 'impersonations:manage');
+INSERT INTO role_permissions (role_level, permission_code)
+SELECT 100, code FROM permissions WHERE code <> 'impersonations:manage';
+
+INSERT into events_types (code, name, color) VALUES
+                                          (1, 'Хакатон', '#3F51B5'),
+                                          (2, 'Статья', '#009688'),
+                                          (3, 'Олимпиада', '#FF9800'),
+                                          (4, 'Проект', '#8E24AA'),
+                                          (0, 'Неизвестно', '#9E9E9E');
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_uq
+    ON users (email);
 
 CREATE UNIQUE INDEX IF NOT EXISTS link_tokens_token_hash_uq
     ON link_tokens (token_hash);
 
+CREATE UNIQUE INDEX IF NOT EXISTS refresh_tokens_token_hash_uq
+    ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx
+    ON refresh_tokens (user_id);
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx
+    ON refresh_tokens (family_id);
+
+CREATE INDEX IF NOT EXISTS audit_log_target_idx
+    ON audit_log (target_type, target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx
+    ON audit_log (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx
+    ON audit_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS impersonation_sessions_user_id_idx
+    ON impersonation_sessions(user_id);
+CREATE INDEX IF NOT EXISTS users_student_group_id_idx
+    ON users(student_group_id);
+CREATE INDEX IF NOT EXISTS students_student_group_id_idx
+    ON students(student_group_id);
+-- у пользователя не больше одной заявки на рассмотрении по одному событию
+CREATE UNIQUE INDEX IF NOT EXISTS claims_pending_uq
+    ON claims (user_id, event_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS claims_status_created_at_idx
+    ON claims (status, created_at);
+CREATE INDEX IF NOT EXISTS claims_user_id_idx
+    ON claims (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS claim_attachments_claim_id_idx
+    ON claim_attachments (claim_id);
+CREATE INDEX IF NOT EXISTS event_registrations_waitlist_idx
+    ON event_registrations (event_id, status, created_at);
+CREATE INDEX IF NOT EXISTS event_registrations_user_id_idx
+    ON event_registrations (user_id);
+CREATE INDEX IF NOT EXISTS event_checkins_user_id_idx
+    ON event_checkins (user_id);
+CREATE INDEX IF NOT EXISTS events_search_vector_idx
+    ON events USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS events_event_date_idx
+    ON events (event_date, id);
+CREATE INDEX IF NOT EXISTS events_points_idx
+    ON events (points, id);
+CREATE INDEX IF NOT EXISTS points_transactions_user_id_idx
+    ON points_transactions (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS points_transactions_event_id_idx
+    ON points_transactions (event_id, user_id) WHERE event_id IS NOT NULL;
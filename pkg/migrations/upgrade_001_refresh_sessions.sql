@@ -0,0 +1,17 @@
+-- Данные устройства в refresh_tokens (/me/sessions) для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_001_refresh_sessions.sql
+
+BEGIN;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS device_name TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS user_agent TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS ip TEXT NOT NULL DEFAULT '';
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS last_used_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE UNIQUE INDEX IF NOT EXISTS refresh_tokens_token_hash_uq
+    ON refresh_tokens (token_hash);
+CREATE INDEX IF NOT EXISTS refresh_tokens_user_id_idx
+    ON refresh_tokens (user_id);
+
+COMMIT;
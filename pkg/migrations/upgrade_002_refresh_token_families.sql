@@ -0,0 +1,20 @@
+-- Семейства refresh токенов (ротация и отзыв цепочки при повторном использовании) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_002_refresh_token_families.sql
+
+BEGIN;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS family_id INT;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS parent_id INT;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS rotated_at TIMESTAMPTZ;
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS revoked_at TIMESTAMPTZ;
+
+-- токены, выданные до ротации, - каждый начало своей цепочки
+UPDATE refresh_tokens SET family_id = id WHERE family_id IS NULL;
+ALTER TABLE refresh_tokens ALTER COLUMN family_id SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS refresh_tokens_family_id_idx
+    ON refresh_tokens (family_id);
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- Версия токенов пользователя (отзыв выданных access токенов) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_003_token_version.sql
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS token_version INT NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+-- Ограничение попыток входа, проверки студента и сброса пароля (auth_throttle) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_004_auth_throttle.sql
+
+CREATE TABLE IF NOT EXISTS auth_throttle (
+    scope TEXT NOT NULL,                -- 'login', 'check', 'reset_password'
+    key_type TEXT NOT NULL,             -- 'ip' или 'account'
+    key TEXT NOT NULL,                  -- IP адрес, почта или номер студенческого
+    failures INT NOT NULL DEFAULT 0,    -- попыток в текущем окне
+    window_started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    lockouts INT NOT NULL DEFAULT 0,    -- сколько раз подряд ключ блокировался (для прогрессивной блокировки)
+    locked_until TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (scope, key_type, key)
+);
@@ -0,0 +1,29 @@
+-- Двухфакторная аутентификация (TOTP, коды восстановления, челленджи входа) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_005_two_factor.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMPTZ,                      -- NULL, пока привязка не подтверждена кодом
+    last_used_step BIGINT NOT NULL DEFAULT 0,    -- последний принятый интервал TOTP (защита от повтора кода)
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS totp_recovery_codes (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash BYTEA NOT NULL,
+    used_at TIMESTAMPTZ
+);
+CREATE TABLE IF NOT EXISTS mfa_challenges (
+    token_hash BYTEA PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMIT;
@@ -0,0 +1,30 @@
+-- Подтверждение почты (users.email_verified_at, email_verification_tokens) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_006_email_verification.sql
+--
+-- Аккаунты, созданные до подтверждения почты, считаются подтвержденными: иначе при политике reset
+-- их владельцы не смогли бы сбросить пароль. Отметка ставится только при добавлении колонки,
+-- поэтому повторный запуск не подтверждает новые аккаунты.
+
+BEGIN;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM information_schema.columns
+                   WHERE table_name = 'users' AND column_name = 'email_verified_at') THEN
+        ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+        UPDATE users SET email_verified_at = now();
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS email_verification_tokens (
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,                 -- почта, которую подтверждает токен
+    token_hash BYTEA NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (user_id)
+);
+
+COMMIT;
@@ -0,0 +1,51 @@
+-- Права ролей (permissions, role_permissions) для уже развернутых баз. Скрипт можно запускать повторно.
+-- Без него RequirePermission запрещает все маршруты /admin, а скрипты с большими номерами
+-- не могут добавить свои права.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_007_permissions.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS permissions (
+    code TEXT PRIMARY KEY,                 -- 'events:write', 'users:delete', ...
+    description TEXT NOT NULL DEFAULT ''
+);
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_level INT NOT NULL REFERENCES roles(level) ON UPDATE CASCADE ON DELETE CASCADE,
+    permission_code TEXT NOT NULL REFERENCES permissions(code) ON DELETE CASCADE,
+    PRIMARY KEY (role_level, permission_code)
+);
+
+INSERT INTO permissions (code, description) VALUES
+    ('profile:read', 'Личный кабинет (/me)'),
+    ('users:read', 'Просмотр пользователей'),
+    ('users:write', 'Изменение данных пользователей'),
+    ('users:delete', 'Удаление пользователей'),
+    ('users:manage_roles', 'Назначение ролей пользователям'),
+    ('students:read', 'Просмотр реестра студентов'),
+    ('events:read', 'Просмотр мероприятий'),
+    ('events:write', 'Создание, изменение и удаление мероприятий'),
+    ('completed_events:read', 'Просмотр выполненных мероприятий'),
+    ('completed_events:write', 'Начисление и отмена выполненных мероприятий'),
+    ('auth_locks:manage', 'Просмотр и снятие блокировок входа'),
+    ('roles:manage', 'Создание ролей и настройка их прав')
+ON CONFLICT (code) DO NOTHING;
+
+-- студент получает только личный кабинет, администратор - все права, кроме управления ролями, разработчик - все.
+-- Права перечислены явно, чтобы повторный запуск не выдал администратору права, добавленные позже
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+    (10, 'profile:read')
+ON CONFLICT DO NOTHING;
+INSERT INTO role_permissions (role_level, permission_code)
+SELECT r.level, p.code
+FROM (VALUES (50), (100)) AS r(level)
+CROSS JOIN (VALUES
+    ('profile:read'), ('users:read'), ('users:write'), ('users:delete'), ('users:manage_roles'),
+    ('students:read'), ('events:read'), ('events:write'),
+    ('completed_events:read'), ('completed_events:write'),
+    ('auth_locks:manage'), ('roles:manage')
+) AS p(code)
+WHERE r.level = 100 OR p.code <> 'roles:manage'
+ON CONFLICT DO NOTHING;
+
+COMMIT;
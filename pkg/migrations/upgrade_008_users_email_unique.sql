@@ -0,0 +1,15 @@
+-- Уникальность почты пользователей (users_email_uq) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_008_users_email_unique.sql
+--
+-- Если индекс не создается, в базе уже есть повторяющиеся почты. Их нужно развести вручную:
+--
+--   SELECT email, array_agg(id ORDER BY id) FROM users GROUP BY email HAVING count(*) > 1;
+
+BEGIN;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_uq
+    ON users (email);
+
+COMMIT;
@@ -0,0 +1,34 @@
+-- Журнал действий администраторов (audit_log) для уже развернутых баз. Скрипт можно запускать повторно.
+-- Запись в журнал идет в транзакции самого действия, поэтому без таблицы не проходит ни одно изменение в /admin.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_009_audit_log.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT NOT NULL,              -- кто выполнил действие (без FK, чтобы запись пережила удаление админа)
+    action TEXT NOT NULL,               -- 'event.update', 'completed_event.add', ...
+    target_type TEXT NOT NULL,          -- 'event', 'user', 'role', ...
+    target_id TEXT NOT NULL,
+    before JSONB,                       -- состояние до изменения
+    after JSONB,                        -- состояние после изменения
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_target_idx
+    ON audit_log (target_type, target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_log_actor_id_idx
+    ON audit_log (actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS audit_log_created_at_idx
+    ON audit_log (created_at DESC);
+
+INSERT INTO permissions (code, description) VALUES
+    ('audit:read', 'Просмотр журнала действий администраторов')
+ON CONFLICT (code) DO NOTHING;
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+    (50, 'audit:read'),
+    (100, 'audit:read')
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,7 @@
+-- Удаление аккаунта по запросу пользователя (users.deletion_scheduled_at, users.deleted_at)
+-- для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_010_account_deletion.sql
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deletion_scheduled_at timestamptz;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS deleted_at timestamptz;
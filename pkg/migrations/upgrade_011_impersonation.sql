@@ -0,0 +1,31 @@
+-- Вход от имени пользователя (impersonation_sessions, право users:impersonate) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_011_impersonation.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS impersonation_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    actor_id INT NOT NULL,              -- разработчик, который вошел от имени пользователя (без FK, как в audit_log)
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    read_only BOOLEAN NOT NULL DEFAULT true,
+    started_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at TIMESTAMPTZ NOT NULL,    -- совпадает со сроком жизни выданного access токена
+    ended_at TIMESTAMPTZ                -- NULL, пока сессию не завершили вручную
+);
+
+CREATE INDEX IF NOT EXISTS impersonation_sessions_user_id_idx
+    ON impersonation_sessions(user_id);
+
+-- вход - только разработчик, завершение чужих сессий не выдается никому, пока роли его не назначат явно
+INSERT INTO permissions (code, description) VALUES
+    ('users:impersonate', 'Вход от имени пользователя'),
+    ('impersonations:manage', 'Завершение чужих сессий входа от имени пользователя')
+ON CONFLICT (code) DO NOTHING;
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+    (100, 'users:impersonate')
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,12 @@
+-- Право students:write (загрузка и изменение реестра студентов) для уже развернутых баз.
+-- Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_012_students_write.sql
+
+INSERT INTO permissions (code, description) VALUES
+    ('students:write', 'Загрузка и изменение реестра студентов')
+ON CONFLICT (code) DO NOTHING;
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+    (50, 'students:write'),
+    (100, 'students:write')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,63 @@
+-- Перевод текстовых групп users.student_group и students.student_group в ссылки на student_groups
+-- для уже развернутых баз. Скрипт можно запускать повторно: на новой базе, созданной по tables.sql,
+-- текстовых колонок нет и он ничего не меняет.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_013_student_group_fk.sql
+
+BEGIN;
+
+-- каждая встреченная группа становится записью student_groups без направления,
+-- направление потом назначается через /admin/groups.
+-- Запросы к старым колонкам выполняются внутри DO, чтобы не падать на базе, где их уже нет
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'students' AND column_name = 'student_group') THEN
+        INSERT INTO student_groups (name)
+        SELECT DISTINCT btrim(student_group)
+        FROM students
+        WHERE btrim(student_group) <> ''
+        ON CONFLICT (name) DO NOTHING;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'users' AND column_name = 'student_group') THEN
+        INSERT INTO student_groups (name)
+        SELECT DISTINCT btrim(student_group)
+        FROM users
+        WHERE student_group IS NOT NULL AND btrim(student_group) <> ''
+        ON CONFLICT (name) DO NOTHING;
+
+        ALTER TABLE users ADD COLUMN IF NOT EXISTS student_group_id int REFERENCES student_groups(id);
+        UPDATE users u
+        SET student_group_id = g.id
+        FROM student_groups g
+        WHERE g.name = btrim(u.student_group);
+        ALTER TABLE users DROP COLUMN student_group;
+    END IF;
+
+    IF EXISTS (SELECT 1 FROM information_schema.columns
+               WHERE table_name = 'students' AND column_name = 'student_group') THEN
+        ALTER TABLE students ADD COLUMN IF NOT EXISTS student_group_id int REFERENCES student_groups(id);
+        UPDATE students s
+        SET student_group_id = g.id
+        FROM student_groups g
+        WHERE g.name = btrim(s.student_group);
+        ALTER TABLE students DROP COLUMN student_group;
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS users_student_group_id_idx
+    ON users(student_group_id);
+CREATE INDEX IF NOT EXISTS students_student_group_id_idx
+    ON students(student_group_id);
+
+INSERT INTO permissions (code, description) VALUES
+    ('institutes:write', 'Изменение институтов, направлений и групп')
+ON CONFLICT (code) DO NOTHING;
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+    (50, 'institutes:write'),
+    (100, 'institutes:write')
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,6 @@
+-- Статус выпускника (archived_at) для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_014_archived_at.sql
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS archived_at timestamptz;
+ALTER TABLE students ADD COLUMN IF NOT EXISTS archived_at timestamptz;
@@ -0,0 +1,11 @@
+-- Иконки и цвета типов мероприятий для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_015_event_types_style.sql
+
+ALTER TABLE events_types ADD COLUMN IF NOT EXISTS icon_url text NOT NULL DEFAULT '';
+ALTER TABLE events_types ADD COLUMN IF NOT EXISTS color text NOT NULL DEFAULT '#9E9E9E';
+
+UPDATE events_types SET color = '#3F51B5' WHERE code = 1 AND color = '#9E9E9E';
+UPDATE events_types SET color = '#009688' WHERE code = 2 AND color = '#9E9E9E';
+UPDATE events_types SET color = '#FF9800' WHERE code = 3 AND color = '#9E9E9E';
+UPDATE events_types SET color = '#8E24AA' WHERE code = 4 AND color = '#9E9E9E';
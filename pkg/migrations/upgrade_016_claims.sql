@@ -0,0 +1,43 @@
+-- Заявки студентов на баллы (claims) для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_016_claims.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS claims (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    comment TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending'
+        CHECK (status IN ('pending', 'approved', 'rejected', 'withdrawn')),
+    reject_reason TEXT,
+    reviewed_by INT,
+    reviewed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS claim_attachments (
+    id BIGSERIAL PRIMARY KEY,
+    claim_id BIGINT NOT NULL REFERENCES claims(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS claims_pending_uq
+    ON claims (user_id, event_id) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS claims_status_created_at_idx
+    ON claims (status, created_at);
+CREATE INDEX IF NOT EXISTS claims_user_id_idx
+    ON claims (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS claim_attachments_claim_id_idx
+    ON claim_attachments (claim_id);
+
+INSERT INTO permissions (code, description) VALUES
+    ('claims:review', 'Рассмотрение заявок студентов на баллы')
+ON CONFLICT (code) DO NOTHING;
+INSERT INTO role_permissions (role_level, permission_code) VALUES
+    (50, 'claims:review'),
+    (100, 'claims:review')
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,25 @@
+-- Запись на события с ограничением мест и листом ожидания для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_017_event_registrations.sql
+
+BEGIN;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS capacity int CHECK (capacity > 0);
+ALTER TABLE events ADD COLUMN IF NOT EXISTS registration_opens_at timestamptz;
+ALTER TABLE events ADD COLUMN IF NOT EXISTS registration_closes_at timestamptz;
+
+CREATE TABLE IF NOT EXISTS event_registrations (
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('registered', 'waitlisted')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    attended_at TIMESTAMPTZ,
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS event_registrations_waitlist_idx
+    ON event_registrations (event_id, status, created_at);
+CREATE INDEX IF NOT EXISTS event_registrations_user_id_idx
+    ON event_registrations (user_id);
+
+COMMIT;
@@ -0,0 +1,27 @@
+-- Самостоятельная отметка на событиях по QR коду для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_018_event_checkins.sql
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS event_checkin_settings (
+    event_id INT PRIMARY KEY REFERENCES events(id) ON DELETE CASCADE,
+    opens_at TIMESTAMPTZ NOT NULL,
+    closes_at TIMESTAMPTZ NOT NULL,
+    secret BYTEA NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (closes_at > opens_at)
+);
+
+CREATE TABLE IF NOT EXISTS event_checkins (
+    event_id INT NOT NULL REFERENCES events(id) ON DELETE CASCADE,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_nonce TEXT NOT NULL,
+    checked_in_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS event_checkins_user_id_idx
+    ON event_checkins (user_id);
+
+COMMIT;
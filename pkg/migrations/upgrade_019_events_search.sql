@@ -0,0 +1,26 @@
+-- Полнотекстовый поиск событий и индексы для сортировки для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_019_events_search.sql
+
+BEGIN;
+
+-- поля сортировки не могут быть NULL: сравнение курсора (поле, id) с NULL не дает результата,
+-- и выдача страниц обрывается. Пустые баллы и так считаются нулем, пустая дата - значение по умолчанию
+UPDATE events SET points = 0 WHERE points IS NULL;
+UPDATE events SET event_date = '1970-01-01T00:00:00Z' WHERE event_date IS NULL;
+ALTER TABLE events ALTER COLUMN points SET NOT NULL;
+ALTER TABLE events ALTER COLUMN event_date SET NOT NULL;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('russian', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(description, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS events_search_vector_idx
+    ON events USING gin (search_vector);
+CREATE INDEX IF NOT EXISTS events_event_date_idx
+    ON events (event_date, id);
+CREATE INDEX IF NOT EXISTS events_points_idx
+    ON events (points, id);
+
+COMMIT;
@@ -0,0 +1,20 @@
+-- Статусы событий (черновик, опубликовано, в архиве) и мягкое удаление для уже развернутых баз.
+-- Существующие события считаются опубликованными. Выполненные события больше не удаляются
+-- каскадно вместе с событием, чтобы суммы баллов не расходились. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_020_event_lifecycle.sql
+
+BEGIN;
+
+ALTER TABLE events ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published';
+ALTER TABLE events ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+ALTER TABLE events DROP CONSTRAINT IF EXISTS events_status_check;
+ALTER TABLE events ADD CONSTRAINT events_status_check
+    CHECK (status IN ('draft', 'published', 'archived'));
+
+ALTER TABLE completed_events DROP CONSTRAINT IF EXISTS completed_events_event_id_fkey;
+ALTER TABLE completed_events ADD CONSTRAINT completed_events_event_id_fkey
+    FOREIGN KEY (event_id) REFERENCES events(id) ON DELETE RESTRICT;
+
+COMMIT;
@@ -0,0 +1,38 @@
+-- Журнал баллов (points_transactions) для уже развернутых баз. Скрипт можно запускать повторно.
+--
+--   psql "$DATABASE_URL" -f pkg/migrations/upgrade_021_points_ledger.sql
+--
+-- Журнал заполняется по выполненным событиям с их текущими баллами. user_points не меняется:
+-- расхождения, накопившиеся до журнала, показывает и исправляет команда reconcile:
+--
+--   go run ./cmd/reconcile          # отчет
+--   go run ./cmd/reconcile -apply   # пересчитать user_points по журналу
+
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS points_transactions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id INT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    delta INT NOT NULL,
+    reason TEXT NOT NULL
+        CHECK (reason IN ('event_completed', 'event_revoked', 'event_points_changed', 'manual', 'reconcile')),
+    event_id INT REFERENCES events(id) ON DELETE RESTRICT,
+    actor_id INT NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS points_transactions_user_id_idx
+    ON points_transactions (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS points_transactions_event_id_idx
+    ON points_transactions (event_id, user_id) WHERE event_id IS NOT NULL;
+
+-- перенос только в пустой журнал, чтобы повторный запуск не задвоил баллы
+INSERT INTO points_transactions (user_id, delta, reason, event_id, actor_id, comment, created_at)
+SELECT ce.user_id, COALESCE(e.points, 0), 'event_completed', ce.event_id, 0,
+       'перенос выполненных событий в журнал', COALESCE(ce.completed_at, now())
+FROM completed_events ce
+JOIN events e ON e.id = ce.event_id
+WHERE NOT EXISTS (SELECT 1 FROM points_transactions);
+
+COMMIT;
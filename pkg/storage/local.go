@@ -0,0 +1,76 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// LocalStorage хранит файлы на диске сервера. Файлы раздаются самим сервером по LocalRoute.
+type LocalStorage struct {
+	dir       string
+	publicURL string
+}
+
+// NewLocalStorage создает локальное хранилище в dir (директория создается при необходимости).
+func NewLocalStorage(dir, publicURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("could not create storage dir: %w", err)
+	}
+
+	return &LocalStorage{dir: dir, publicURL: publicURL}, nil
+}
+
+// Dir возвращает директорию, из которой нужно раздавать файлы.
+func (s *LocalStorage) Dir() string {
+	return s.dir
+}
+
+// Put записывает файл через временный файл и переименование, чтобы по URL не отдавался недописанный файл.
+func (s *LocalStorage) Put(_ context.Context, key, _ string, data []byte) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(s.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("could not create storage dir: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", fmt.Errorf("could not create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("could not write file: %w", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return "", fmt.Errorf("could not write file: %w", err)
+	}
+	if err = os.Chmod(tmp.Name(), 0o644); err != nil {
+		return "", fmt.Errorf("could not write file: %w", err)
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("could not save file: %w", err)
+	}
+
+	return joinURL(s.publicURL, key), nil
+}
+
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(s.dir, filepath.FromSlash(key)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not delete file: %w", err)
+	}
+
+	return nil
+}
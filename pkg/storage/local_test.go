@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidKey(t *testing.T) {
+	valid := []string{
+		"avatars/ab12.webp",
+		"evidence/2024/scan.pdf",
+		"icon.png",
+		"..hidden/file",
+	}
+	for _, key := range valid {
+		if err := validKey(key); err != nil {
+			t.Errorf("validKey(%q) = %v, want nil", key, err)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"/etc/passwd",
+		"../secret",
+		"avatars/../../secret",
+		"avatars/..",
+		"./avatars/a.png",
+		"avatars//a.png",
+		"avatars/",
+		`avatars\..\secret`,
+	}
+	for _, key := range invalid {
+		if err := validKey(key); err == nil {
+			t.Errorf("validKey(%q) = nil, want error", key)
+		}
+	}
+}
+
+func TestLocalStoragePut(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewLocalStorage(filepath.Join(dir, "uploads"), "/uploads/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	url, err := s.Put(context.Background(), "avatars/a.png", "image/png", []byte("data"))
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if url != "/uploads/avatars/a.png" {
+		t.Errorf("url = %q", url)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "uploads", "avatars", "a.png"))
+	if err != nil || string(data) != "data" {
+		t.Fatalf("stored file = %q, %v", data, err)
+	}
+
+	if err = s.Delete(context.Background(), "avatars/a.png"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err = s.Delete(context.Background(), "avatars/a.png"); err != nil {
+		t.Fatalf("Delete of missing file: %v", err)
+	}
+}
+
+func TestLocalStorageRejectsPathTraversal(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "uploads")
+	s, err := NewLocalStorage(root, "/uploads")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// файл рядом с хранилищем, который не должен быть ни перезаписан, ни удален
+	outside := filepath.Join(dir, "secret")
+	if err = os.WriteFile(outside, []byte("secret"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"../secret", "avatars/../../secret", "/" + outside, `..\secret`} {
+		if _, err = s.Put(context.Background(), key, "text/plain", []byte("overwritten")); err == nil {
+			t.Errorf("Put(%q) = nil, want error", key)
+		}
+		if err = s.Delete(context.Background(), key); err == nil {
+			t.Errorf("Delete(%q) = nil, want error", key)
+		}
+	}
+
+	data, err := os.ReadFile(outside)
+	if err != nil || string(data) != "secret" {
+		t.Fatalf("file outside storage changed: %q, %v", data, err)
+	}
+}
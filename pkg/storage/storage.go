@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Поддерживаемые хранилища файлов.
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// LocalRoute - путь, по которому сервер раздает файлы локального хранилища.
+const LocalRoute = "/uploads"
+
+// Storage - хранилище загруженных файлов (иконки, аватары, подтверждения к заявкам).
+// URL, который возвращает Put, постоянный: по нему файл доступен без подписи и срока действия.
+type Storage interface {
+	// Put сохраняет data под ключом key (например, avatars/ab12.webp) и возвращает публичный URL файла.
+	Put(ctx context.Context, key, contentType string, data []byte) (string, error)
+	// Delete удаляет файл. Отсутствие файла ошибкой не считается.
+	Delete(ctx context.Context, key string) error
+}
+
+// Config - настройки хранилища. Пустые поля заполняются значениями по умолчанию в New.
+type Config struct {
+	Backend   string // local (по умолчанию) или s3
+	PublicURL string // начало публичного URL файлов; для local по умолчанию /uploads
+
+	LocalDir string // директория локального хранилища, по умолчанию ./uploads
+
+	S3Endpoint  string // например, https://storage.yandexcloud.net
+	S3Region    string // по умолчанию us-east-1
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// ConfigFromEnv читает настройки хранилища из переменных окружения STORAGE_* и S3_*.
+func ConfigFromEnv() Config {
+	return Config{
+		Backend:     os.Getenv("STORAGE_BACKEND"),
+		PublicURL:   os.Getenv("STORAGE_PUBLIC_URL"),
+		LocalDir:    os.Getenv("STORAGE_LOCAL_DIR"),
+		S3Endpoint:  os.Getenv("S3_ENDPOINT"),
+		S3Region:    os.Getenv("S3_REGION"),
+		S3Bucket:    os.Getenv("S3_BUCKET"),
+		S3AccessKey: os.Getenv("S3_ACCESS_KEY"),
+		S3SecretKey: os.Getenv("S3_SECRET_KEY"),
+	}
+}
+
+// New создает хранилище по настройкам. Без настроек - локальное хранилище в ./uploads.
+func New(cfg Config) (Storage, error) {
+	switch strings.ToLower(strings.TrimSpace(cfg.Backend)) {
+	case "", BackendLocal:
+		if cfg.LocalDir == "" {
+			cfg.LocalDir = "./uploads"
+		}
+		if cfg.PublicURL == "" {
+			cfg.PublicURL = LocalRoute
+		}
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicURL)
+	case BackendS3:
+		if cfg.S3Endpoint == "" || cfg.S3Bucket == "" || cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return nil, fmt.Errorf("S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required for s3 storage")
+		}
+		if cfg.S3Region == "" {
+			cfg.S3Region = "us-east-1"
+		}
+		return NewS3Storage(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey, cfg.PublicURL)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q, expected local or s3", cfg.Backend)
+	}
+}
+
+// validKey проверяет, что ключ относительный и не выходит за пределы хранилища.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// joinURL склеивает базовый URL и ключ через один слэш.
+func joinURL(base, key string) string {
+	return strings.TrimRight(base, "/") + "/" + key
+}